@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/fd0/erpel/internal/erpel"
+	"github.com/spf13/cobra"
+)
+
+var checkCmd = &cobra.Command{
+	Use:     "check",
+	Short:   "Check all rules files",
+	Example: "$ erpel check --expiry-warning 14",
+	Long: `
+The check command parses all files in the rules directories and runs the
+self-tests against the samples in each file. All errors are reported, not only
+the first one. Overrides from the config file (and the override_dir) are
+applied and listed, the samples are checked afterwards. In addition, warnings
+for templates which are likely too broad and for rules which have expired or
+will expire soon (see --expiry-warning) are printed.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return CheckRules()
+	},
+}
+
+// warn about rules which expire within this many days
+var expiryWarning int
+
+func init() {
+	RootCmd.AddCommand(checkCmd)
+	flags := checkCmd.Flags()
+
+	addRulesFlags(flags)
+
+	flags.IntVar(&expiryWarning, "expiry-warning", 7, "warn about rules which expire within `days`")
+	bindConfigValue("expiry_warning", flags.Lookup("expiry-warning"))
+}
+
+// warnExpiring prints warnings for rules which expire soon to wr.
+func warnExpiring(wr io.Writer, rules []erpel.Rules) (n int) {
+	for _, r := range rules {
+		for _, w := range r.Expiring(time.Now(), expiryWarning) {
+			fmt.Fprintf(wr, "warning: %v\n", w)
+			n++
+		}
+	}
+
+	return n
+}
+
+// CheckRules checks all rules files and reports all problems found.
+func CheckRules() error {
+	files, err := erpel.FindRulesFiles(rulesDirs, rulesFilter)
+	if err != nil {
+		return err
+	}
+
+	var failed, warnings int
+	for _, file := range files {
+		V("checking %v\n", file.Filename)
+
+		r, notes, disabled, err := cfg.LoadRulesFile(file)
+		if err != nil {
+			fmt.Printf("error: %v: %v\n", file.Filename, err)
+			failed++
+			continue
+		}
+
+		for _, note := range notes {
+			fmt.Printf("override: %v\n", note)
+		}
+
+		if disabled {
+			continue
+		}
+
+		for _, w := range r.Lint() {
+			fmt.Printf("warning: %v: %v\n", file.Filename, w)
+			warnings++
+		}
+
+		warnings += warnExpiring(os.Stdout, []erpel.Rules{r})
+	}
+
+	for _, name := range cfg.UnusedOverrides(files) {
+		fmt.Printf("warning: override for %v does not match any rules file\n", name)
+		warnings++
+	}
+
+	fmt.Printf("checked %d rules files, %d errors, %d warnings\n", len(files), failed, warnings)
+
+	if failed > 0 {
+		return fmt.Errorf("%d rules files have errors", failed)
+	}
+
+	return nil
+}
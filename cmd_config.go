@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Inspect the configuration",
+	Long: `
+The config command groups subcommands for inspecting the configuration.
+`,
+}
+
+var configDumpCmd = &cobra.Command{
+	Use:     "dump",
+	Short:   "Print the effective configuration",
+	Example: "$ erpel config dump",
+	Long: `
+The dump command prints the configuration after all included files have been
+merged, in the syntax of the config file. The files which have been loaded are
+listed first, each option and section is followed by a comment naming the file
+it was defined in. Environment variables in option values are expanded.
+
+Files are merged in the order they are loaded: first the including file, then
+the included files in the order of the include statements, files matched by a
+glob pattern sorted by name. Options, fields and file sections from later files
+replace the ones from earlier files, a rules file may only be overridden once.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return DumpConfig()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configDumpCmd)
+}
+
+// DumpConfig prints the effective configuration.
+func DumpConfig() error {
+	if configFile == "" {
+		fmt.Println("no config file found")
+		return nil
+	}
+
+	return cfg.Dump(os.Stdout)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fd0/erpel/internal/erpel"
+	"github.com/spf13/cobra"
+)
+
+var expectCmd = &cobra.Command{
+	Use:   "expect",
+	Short: "Manage expected messages",
+	Long: `
+The expect command groups subcommands for messages which are expected to be
+seen regularly, as configured with "@expect" in the rules files.
+`,
+}
+
+var expectStatusCmd = &cobra.Command{
+	Use:     "status",
+	Short:   "Show the status of expected messages",
+	Example: "$ erpel expect status",
+	Long: `
+The status command lists all templates with an "@expect" annotation together
+with the time the message has been seen last and the time by which it needs to
+be seen next. Messages which are overdue are marked as missing.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ExpectStatus()
+	},
+	PreRunE: func(*cobra.Command, []string) error {
+		return LoadRules()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(expectCmd)
+	expectCmd.AddCommand(expectStatusCmd)
+	flags := expectCmd.PersistentFlags()
+
+	flags.StringVarP(&stateDir, "state-dir", "s", "/var/lib/erpel", "set the directory for keeping log file positions")
+	bindConfigValue("state_dir", flags.Lookup("state-dir"))
+
+	addRulesFlags(flags)
+}
+
+func expectFilename() string {
+	return filepath.Join(stateDir, "expect.json")
+}
+
+// loadExpectations returns the expectations saved in the state directory.
+// Empty expectations are returned when the state is ignored or cannot be
+// loaded, save is false then so that the saved state is not overwritten.
+func loadExpectations() (e *erpel.Expectations, save bool) {
+	if ignoreState {
+		return erpel.NewExpectations(), false
+	}
+
+	e, err := erpel.LoadExpectations(expectFilename())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error loading expected messages: %v\n", err)
+		return erpel.NewExpectations(), false
+	}
+
+	return e, true
+}
+
+// ExpectStatus prints the status of the expected messages.
+func ExpectStatus() error {
+	now := time.Now()
+	e, _ := loadExpectations()
+	list := e.Status(Rules, now)
+	if len(list) == 0 {
+		fmt.Println("no expected messages configured")
+		return nil
+	}
+
+	for _, st := range list {
+		status := "ok"
+		if st.Missing {
+			status = "MISSING"
+		}
+
+		seen := "never seen, expected since"
+		if st.Seen {
+			seen = "last seen"
+		}
+
+		fmt.Printf("%-7s %v\n", status, st.Rules.Describe(st.Template))
+		fmt.Printf("        expected %v, %v %v, next by %v\n", st.Template.Expect,
+			seen, st.LastSeen.Format(time.RFC3339), st.Next.Format(time.RFC3339))
+	}
+
+	return nil
+}
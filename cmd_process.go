@@ -1,15 +1,21 @@
 package main
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/fd0/erpel/internal/erpel"
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 )
 
 var processCmd = &cobra.Command{
@@ -17,9 +23,24 @@ var processCmd = &cobra.Command{
 	Short:   "Process log files",
 	Example: "$ erpel process /var/log/messages",
 	Long: `
-The process command is the main operation of erpel. It processes all logfile
-specified on the command line, going throuh each file line by line and only
-prints those log messages that do not match any of the process rules.
+The process command is the main operation of erpel. It processes all log files
+specified on the command line, going through each file line by line and only
+prints those log messages that do not match any of the rules. Messages from
+rules files with the action 'report' or 'alert' are always printed, the lines
+from each log file are then printed grouped by category.
+
+With --context (or --before-context and --after-context), neighbouring lines
+are printed along with each unmatched line. Unmatched lines are then marked
+with ":", context lines with "-", and groups of lines which are not adjacent
+are separated by "--". Control characters and invalid UTF-8 are escaped unless
+--raw is given.
+
+The positions in the log files, the counters for thresholds, pending sequences
+and the times expected messages have been seen are kept in the state
+directory. The rules are loaded from the directories given with --rules, and
+--tags and --exclude-tags select the templates to apply. The templates are
+described in doc/rules.md, the options of rules files in doc/rules.d/dovecot
+and the config file in doc/erpel.conf.
 `,
 	RunE: Process,
 	PreRunE: func(*cobra.Command, []string) error {
@@ -29,9 +50,14 @@ prints those log messages that do not match any of the process rules.
 
 var (
 	stateDir      string
-	rulesDir      string
 	ignoreState   bool
 	noUpdateState bool
+
+	contextBefore int
+	contextAfter  int
+	contextLines  int
+
+	rawOutput bool
 )
 
 func init() {
@@ -41,11 +67,25 @@ func init() {
 	flags.StringVarP(&stateDir, "state-dir", "s", "/var/lib/erpel", "set the directory for keeping log file positions")
 	bindConfigValue("state_dir", flags.Lookup("state-dir"))
 
-	flags.StringVarP(&rulesDir, "rules", "r", "/etc/erpel/rules.d", "load rules from this directory")
-	bindConfigValue("rules_dir", flags.Lookup("rules"))
+	addRulesFlags(flags)
 
 	flags.BoolVarP(&ignoreState, "ignore-state", "i", false, "ignore the state and process the files from the start")
 	flags.BoolVarP(&noUpdateState, "no-update-state", "n", false, "do not update the state")
+
+	flags.IntVarP(&contextBefore, "before-context", "B", 0, "print `num` lines of context before unmatched lines")
+	flags.IntVarP(&contextAfter, "after-context", "A", 0, "print `num` lines of context after unmatched lines")
+	flags.IntVarP(&contextLines, "context", "C", 0, "print `num` lines of context around unmatched lines")
+
+	flags.IntVar(&expiryWarning, "expiry-warning", 7, "warn about rules which expire within `days`")
+	bindConfigValue("expiry_warning", flags.Lookup("expiry-warning"))
+
+	flags.StringSliceVar(&tagFilter.Tags, "tags", nil, "only apply templates with one of the `tags`")
+	bindConfigValue("tags", flags.Lookup("tags"))
+
+	flags.StringSliceVar(&tagFilter.Exclude, "exclude-tags", nil, "do not apply templates with one of the `tags`")
+	bindConfigValue("exclude_tags", flags.Lookup("exclude-tags"))
+
+	flags.BoolVar(&rawOutput, "raw", false, "print lines as read, without escaping control characters and invalid UTF-8")
 }
 
 func stateFilename(logfile string) string {
@@ -101,6 +141,184 @@ func saveMarker(logfile string, m erpel.Marker) error {
 	return nil
 }
 
+// processOptions returns the options for processing log files as configured
+// on the command line. --before-context and --after-context override
+// --context when given, even with zero lines.
+func processOptions(flags *pflag.FlagSet) (opts erpel.Options, err error) {
+	if contextBefore < 0 || contextAfter < 0 || contextLines < 0 {
+		return opts, errors.New("number of context lines must not be negative")
+	}
+
+	opts.Before, opts.After = contextLines, contextLines
+
+	if flags.Changed("before-context") {
+		opts.Before = contextBefore
+	}
+
+	if flags.Changed("after-context") {
+		opts.After = contextAfter
+	}
+
+	return opts, nil
+}
+
+// linePrinter prints lines passed on by erpel.Process. Lines are collected
+// until flush is called and printed grouped by category then, the header for
+// a category is only printed if lines from more than the default category are
+// found. The formatted lines are kept in temporary files, so the memory used
+// does not depend on the size of the log file. When context lines are
+// requested, unmatched lines are marked with ":", context lines with "-", and
+// non-adjacent groups of lines are separated by "--", like grep does. Unless
+// raw is set, control characters and invalid UTF-8 are escaped. Lines which
+// are forced through by report or alert rules are annotated with the rule.
+type linePrinter struct {
+	context bool
+	raw     bool
+	groups  map[string]*lineGroup
+}
+
+// lineGroup holds the formatted lines of one category.
+type lineGroup struct {
+	f    *os.File
+	wr   *bufio.Writer
+	last int
+}
+
+// add collects a batch of lines.
+func (p *linePrinter) add(lines []erpel.Line) error {
+	for _, line := range lines {
+		g, err := p.group(line.Category)
+		if err != nil {
+			return err
+		}
+
+		if err = p.format(g, line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// group returns the group for the category, the temporary file is removed
+// right away and only kept open until flush is called.
+func (p *linePrinter) group(category string) (*lineGroup, error) {
+	if g, ok := p.groups[category]; ok {
+		return g, nil
+	}
+
+	f, err := ioutil.TempFile("", "erpel-lines-")
+	if err != nil {
+		return nil, err
+	}
+
+	if err = os.Remove(f.Name()); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	if p.groups == nil {
+		p.groups = make(map[string]*lineGroup)
+	}
+
+	g := &lineGroup{f: f, wr: bufio.NewWriter(f)}
+	p.groups[category] = g
+	return g, nil
+}
+
+// flush prints all lines collected so far.
+func (p *linePrinter) flush() error {
+	categories := make([]string, 0, len(p.groups))
+	for category := range p.groups {
+		categories = append(categories, category)
+	}
+
+	// sort categories by name, the default category is printed last
+	sort.Slice(categories, func(i, j int) bool {
+		if categories[i] == erpel.DefaultCategory || categories[j] == erpel.DefaultCategory {
+			return categories[j] == erpel.DefaultCategory && categories[i] != erpel.DefaultCategory
+		}
+		return categories[i] < categories[j]
+	})
+
+	var firstErr error
+	headers := len(categories) > 1 || (len(categories) == 1 && categories[0] != erpel.DefaultCategory)
+	for i, category := range categories {
+		if headers {
+			if i > 0 {
+				fmt.Println()
+			}
+			fmt.Printf("=== %s ===\n", category)
+		}
+
+		if err := p.groups[category].print(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	p.groups = nil
+
+	return firstErr
+}
+
+// print copies the lines of the group to stdout and closes the file.
+func (g *lineGroup) print() error {
+	err := g.wr.Flush()
+	if err == nil {
+		_, err = g.f.Seek(0, io.SeekStart)
+	}
+
+	if err == nil {
+		_, err = io.Copy(os.Stdout, g.f)
+	}
+
+	if cerr := g.f.Close(); err == nil {
+		err = cerr
+	}
+
+	return err
+}
+
+// format writes the line to the group.
+func (p *linePrinter) format(g *lineGroup, line erpel.Line) error {
+	text := line.Text
+	if line.Summary != "" {
+		text = fmt.Sprintf("%v, sample: %v", line.Summary, text)
+	}
+
+	if line.Action != erpel.ActionIgnore {
+		action := line.Action.String()
+		if line.Severity != "" {
+			action += " (" + line.Severity + ")"
+		}
+		text += fmt.Sprintf("  [%v: %v]", action, line.Rule)
+	}
+
+	// the summary may contain values from the log as well
+	if !p.raw {
+		text = erpel.Escape(text)
+	}
+
+	if !p.context {
+		_, err := fmt.Fprintln(g.wr, text)
+		return err
+	}
+
+	if g.last > 0 && line.Number != g.last+1 {
+		if _, err := fmt.Fprintln(g.wr, "--"); err != nil {
+			return err
+		}
+	}
+	g.last = line.Number
+
+	marker := ":"
+	if line.Context {
+		marker = "-"
+	}
+
+	_, err := fmt.Fprintf(g.wr, "%s %s\n", marker, text)
+	return err
+}
+
 // Process is the main command.
 func Process(cmd *cobra.Command, args []string) error {
 	if len(args) == 0 {
@@ -116,6 +334,41 @@ func Process(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	opts, err := processOptions(cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	warnExpiring(os.Stderr, Rules)
+
+	countersFile := filepath.Join(stateDir, "thresholds.json")
+	if !ignoreState {
+		opts.Counters, err = erpel.LoadCounters(countersFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error loading threshold counters: %v\n", err)
+		}
+	}
+	if opts.Counters == nil {
+		opts.Counters = erpel.NewCounters()
+	}
+
+	// pending sequences are only saved if they have been loaded before
+	correlationsFile := filepath.Join(stateDir, "correlations.json")
+	saveCorrelations := false
+	if !ignoreState {
+		opts.Correlations, err = erpel.LoadCorrelations(correlationsFile)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "error loading pending sequences: %v\n", err)
+		}
+		saveCorrelations = err == nil
+	}
+	if opts.Correlations == nil {
+		opts.Correlations = erpel.NewCorrelations()
+	}
+
+	var saveExpectations bool
+	opts.Expectations, saveExpectations = loadExpectations()
+
 	for _, logfile := range args {
 		V("processing log file %v\n", logfile)
 
@@ -131,13 +384,19 @@ func Process(cmd *cobra.Command, args []string) error {
 			}
 		}
 
-		pos, err := erpel.ProcessFile(Rules, logfile, last, func(lines []string) error {
-			for _, line := range lines {
-				fmt.Println(line)
-			}
+		opts.Charset = cfg.FileOptions(logfile).Charset
+		printer := &linePrinter{
+			context: opts.Before > 0 || opts.After > 0,
+			raw:     rawOutput,
+		}
+
+		pos, err := erpel.ProcessFile(Rules, logfile, last, opts, printer.add)
+
+		// lines found before an error are printed anyway
+		if ferr := printer.flush(); err == nil {
+			err = ferr
+		}
 
-			return nil
-		})
 		if err != nil {
 			return err
 		}
@@ -149,5 +408,33 @@ func Process(cmd *cobra.Command, args []string) error {
 		}
 	}
 
+	printer := &linePrinter{raw: rawOutput}
+	err = printer.add(opts.Expectations.CheckExpected(Rules, time.Now()))
+	if ferr := printer.flush(); err == nil {
+		err = ferr
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !noUpdateState {
+		if saveExpectations {
+			if err = opts.Expectations.Save(expectFilename()); err != nil {
+				fmt.Fprintf(os.Stderr, "error saving expected messages: %v\n", err)
+			}
+		}
+
+		if err = opts.Counters.Save(countersFile, time.Now()); err != nil {
+			fmt.Fprintf(os.Stderr, "error saving threshold counters: %v\n", err)
+		}
+
+		if saveCorrelations {
+			if err = opts.Correlations.Save(correlationsFile); err != nil {
+				fmt.Fprintf(os.Stderr, "error saving pending sequences: %v\n", err)
+			}
+		}
+	}
+
 	return nil
 }
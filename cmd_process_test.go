@@ -0,0 +1,44 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/spf13/pflag"
+)
+
+var testProcessOptions = []struct {
+	args          []string
+	before, after int
+}{
+	{nil, 0, 0},
+	{[]string{"-C", "3"}, 3, 3},
+	{[]string{"-C", "3", "-A", "1"}, 3, 1},
+	{[]string{"-C", "3", "-A", "0"}, 3, 0},
+	{[]string{"-B", "0", "-C", "2"}, 0, 2},
+	{[]string{"-B", "2"}, 2, 0},
+}
+
+func TestProcessOptions(t *testing.T) {
+	for i, test := range testProcessOptions {
+		contextBefore, contextAfter, contextLines = 0, 0, 0
+
+		flags := pflag.NewFlagSet("process", pflag.ContinueOnError)
+		flags.IntVarP(&contextBefore, "before-context", "B", 0, "")
+		flags.IntVarP(&contextAfter, "after-context", "A", 0, "")
+		flags.IntVarP(&contextLines, "context", "C", 0, "")
+		if err := flags.Parse(test.args); err != nil {
+			t.Fatal(err)
+		}
+
+		opts, err := processOptions(flags)
+		if err != nil {
+			t.Errorf("test %v: %v", i, err)
+			continue
+		}
+
+		if opts.Before != test.before || opts.After != test.after {
+			t.Errorf("test %v: want %v/%v lines of context, got %v/%v", i,
+				test.before, test.after, opts.Before, opts.After)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fd0/erpel/internal/erpel"
+	"github.com/spf13/cobra"
+)
+
+var rulesCmd = &cobra.Command{
+	Use:   "rules",
+	Short: "Inspect the rules",
+	Long: `
+The rules command groups subcommands for inspecting the loaded rules files.
+`,
+}
+
+var rulesListCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List rules files and templates",
+	Example: "$ erpel rules list --tag mail",
+	Long: `
+The list command prints the rules files together with their description,
+owner, references and tags, followed by the IDs of their templates. With
+--tag, only templates with at least one of the given tags are listed, and
+--exclude-tag skips templates with one of the given tags. Templates inherit
+the tags of their rules file.
+`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return ListRules()
+	},
+	PreRunE: func(*cobra.Command, []string) error {
+		return LoadRules()
+	},
+}
+
+func init() {
+	RootCmd.AddCommand(rulesCmd)
+	rulesCmd.AddCommand(rulesListCmd)
+	flags := rulesCmd.PersistentFlags()
+
+	addRulesFlags(flags)
+
+	listFlags := rulesListCmd.Flags()
+	listFlags.StringSliceVar(&tagFilter.Tags, "tag", nil, "only list templates with one of the `tags`")
+	listFlags.StringSliceVar(&tagFilter.Exclude, "exclude-tag", nil, "do not list templates with one of the `tags`")
+}
+
+// printMetadata prints the metadata indented by prefix.
+func printMetadata(prefix string, m erpel.Metadata, tags []string) {
+	if m.Description != "" {
+		fmt.Printf("%sdescription: %v\n", prefix, m.Description)
+	}
+	if m.Owner != "" {
+		fmt.Printf("%sowner: %v\n", prefix, m.Owner)
+	}
+	for _, ref := range m.References {
+		fmt.Printf("%sreference: %v\n", prefix, ref)
+	}
+	if len(tags) > 0 {
+		fmt.Printf("%stags: %v\n", prefix, strings.Join(tags, ", "))
+	}
+}
+
+// ListRules prints the rules files and templates with their metadata.
+func ListRules() error {
+	if len(Rules) == 0 {
+		fmt.Println("no rules found")
+		return nil
+	}
+
+	for i := range Rules {
+		r := &Rules[i]
+		if i > 0 {
+			fmt.Println()
+		}
+
+		fmt.Printf("%v\n", r.Filename)
+		printMetadata("  ", r.Metadata, r.Metadata.Tags)
+
+		for _, t := range r.Templates {
+			fmt.Printf("  %v: %v\n", r.TemplateID(t), t.Text)
+			printMetadata("    ", t.Metadata, r.Tags(t))
+		}
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"sort"
 
 	"github.com/fatih/color"
 	"github.com/fd0/erpel/internal/erpel"
@@ -16,6 +17,14 @@ var showCmd = &cobra.Command{
 	Short:   "Parse and show a rules file",
 	Long: `
 The show command parses and visualises a file containing erpel ignore rules.
+Overrides for the file from the config file are applied and listed first,
+followed by the files included by the rules file and the fields defined in the
+file (including the ones from included files) together with the constraints
+for their values. Each template is followed by its ID, which is either set
+with @id in the rules file or derived from the template text. The description,
+owner, references and tags of the file and the templates are shown as well.
+With --templates, the patterns of fields composed from other fields are shown
+expanded.
 `,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		return ShowRules(args)
@@ -41,8 +50,143 @@ var (
 	printText        = color.New(color.FgWhite).PrintfFunc()
 	printField       = color.New(color.FgHiRed).PrintfFunc()
 	printGlobalField = color.New(color.FgHiBlue).PrintfFunc()
+	printSyntax      = color.New(color.FgHiYellow).PrintfFunc()
 )
 
+// printView prints the view of a rule with the fields highlighted.
+func printView(rv erpel.RuleView) {
+	for _, item := range rv {
+		switch item := item.(type) {
+		case erpel.Text:
+			fmt.Printf("%s", item)
+		case erpel.FieldView:
+			p := printField
+			if item.Global {
+				p = printGlobalField
+			}
+
+			if displayTemplates {
+				p("%s", item.S)
+			} else {
+				p("%s", item.F.Name)
+			}
+		case erpel.OptionalView:
+			printSyntax("[[")
+			printView(erpel.RuleView(item))
+			printSyntax("]]")
+		case erpel.AlternationView:
+			printSyntax("(")
+			for i, v := range item {
+				if i > 0 {
+					printSyntax("|")
+				}
+				printView(v)
+			}
+			printSyntax(")")
+		case erpel.RepeatView:
+			printSyntax("{{repeat: ")
+			printView(item.V)
+			if item.Separator != "" {
+				printSyntax(", sep=%q", item.Separator)
+			}
+			printSyntax("}}")
+		case erpel.WildcardView:
+			printSyntax("%s", item)
+		case erpel.RegexpView:
+			printSyntax("re:")
+			printView(erpel.RuleView(item))
+		}
+	}
+}
+
+// printConstraints prints the restrictions for the values of the field.
+func printConstraints(f erpel.Field) {
+	if len(f.Values) > 0 {
+		printSyntax(" values=%q", f.Values)
+	}
+	if len(f.NotValues) > 0 {
+		printSyntax(" not_values=%q", f.NotValues)
+	}
+	if len(f.Networks) > 0 {
+		printSyntax(" cidr=%v", f.Networks)
+	}
+	if f.ValuesFile != nil {
+		printSyntax(" values_file=%q", f.ValuesFile.Filename)
+	}
+}
+
+// printFields lists the local fields of the rules. For fields which are
+// composed of other fields the expanded pattern is shown if templates are
+// displayed.
+func printFields(rules erpel.Rules) {
+	names := make([]string, 0, len(rules.Fields))
+	for name := range rules.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Printf("Fields:\n")
+	for _, name := range names {
+		f := rules.Fields[name]
+
+		printField("%s", name)
+		if f.Template != "" {
+			fmt.Printf(" %q", f.Template)
+		}
+		if f.Type != "" {
+			printSyntax(" type=%v", f.Type)
+		}
+		if f.TimeFormat != "" {
+			printSyntax(" time_format=%q", f.TimeFormat)
+		}
+		printConstraints(f)
+		if f.Expression == "" {
+			fmt.Printf(" %s\n", f.Pattern)
+			continue
+		}
+
+		fmt.Printf(" %s", f.Expression)
+		if displayTemplates {
+			printSyntax(" =>")
+			fmt.Printf(" %s", f.Pattern)
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+// printAnnotations prints the threshold, the sequence, the schedule, the
+// active times and the expiry date of a template.
+func printAnnotations(t erpel.Template) {
+	if t.Threshold != nil {
+		printSyntax("  [threshold %v", t.Threshold)
+		if t.GroupBy != "" {
+			printSyntax(" by %v", t.GroupBy)
+		}
+		printSyntax("]")
+	}
+
+	if t.Sequence != "" {
+		printSyntax("  [sequence %v by %v", t.Sequence, t.Key)
+		if t.Within != nil {
+			printSyntax(" within %v", t.Within)
+		}
+		printSyntax("]")
+	}
+
+	if t.Expect != nil {
+		printSyntax("  [expect %v]", t.Expect)
+	}
+
+	if t.Active != nil {
+		printSyntax("  [active %v]", t.Active)
+	}
+
+	if !t.Expires.IsZero() {
+		printSyntax("  [expires %v]", t.Expires.Format("2006-01-02"))
+	}
+}
+
 // ShowRules visualises an erpel rule file.
 func ShowRules(args []string) error {
 	if len(args) == 0 {
@@ -60,6 +204,11 @@ func ShowRules(args []string) error {
 		return err
 	}
 
+	notes, _, err := cfg.ApplyOverride(&rules)
+	if err != nil {
+		return err
+	}
+
 	if err = rules.Check(); err != nil {
 		if !ignoreRuleSamples {
 			return err
@@ -67,28 +216,60 @@ func ShowRules(args []string) error {
 		fmt.Fprintf(os.Stderr, "error checking rules file: %v\n", err)
 	}
 
-	fmt.Printf("Rules from %v:\n", filename)
-	for _, rv := range rules.Views() {
-		for _, field := range rv {
-			switch f := field.(type) {
-			case erpel.Text:
-				fmt.Printf("%s", f)
-			case erpel.FieldView:
-				p := printField
-				if f.Global {
-					p = printGlobalField
-				}
+	for _, w := range rules.Lint() {
+		fmt.Fprintf(os.Stderr, "warning: %v\n", w)
+	}
 
-				if displayTemplates {
-					p("%s", f.S)
-				} else {
-					p("%s", f.F.Name)
-				}
-			}
+	if len(notes) > 0 {
+		fmt.Printf("Overrides:\n")
+		for _, note := range notes {
+			fmt.Printf("  %v\n", note)
 		}
 		fmt.Println()
 	}
 
+	if len(rules.Includes) > 0 {
+		fmt.Printf("Includes:\n")
+		for _, file := range rules.Includes {
+			fmt.Printf("  %v\n", file)
+		}
+		fmt.Println()
+	}
+
+	printFields(rules)
+
+	if rules.Action != erpel.ActionIgnore {
+		fmt.Printf("Messages matched by these rules are always reported (action %v).\n", rules.Action)
+		category, severity := rules.Classify(erpel.Template{})
+		fmt.Printf("Category: %v", category)
+		if severity != "" {
+			fmt.Printf(", severity: %v", severity)
+		}
+		fmt.Printf("\n\n")
+	}
+
+	if rules.Active != nil {
+		fmt.Printf("These rules are only active %v.\n\n", rules.Active)
+	}
+
+	if !rules.Expires.IsZero() {
+		fmt.Printf("These rules expire on %v.\n\n", rules.Expires.Format("2006-01-02"))
+	}
+
+	if m := rules.Metadata; m.Description != "" || m.Owner != "" || len(m.References) > 0 || len(m.Tags) > 0 {
+		printMetadata("", m, m.Tags)
+		fmt.Println()
+	}
+
+	fmt.Printf("Rules from %v:\n", filename)
+	for i, rv := range rules.Views() {
+		printView(rv)
+		printAnnotations(rules.Templates[i])
+		printSyntax("  [%v]", rules.TemplateID(rules.Templates[i]))
+		fmt.Println()
+		printMetadata("    ", rules.Templates[i].Metadata, rules.Templates[i].Metadata.Tags)
+	}
+
 	if debugOutput {
 		fmt.Printf("\nGenerated regexps:\n")
 		for _, r := range rules.RegExps() {
@@ -1,7 +1,10 @@
 package main
 
 import (
+	"bytes"
+	"encoding/csv"
 	"fmt"
+	"strings"
 
 	"github.com/BurntSushi/xdg"
 	"github.com/fd0/erpel/internal/erpel"
@@ -50,21 +53,77 @@ func parseConfig(cmd *cobra.Command, args []string) error {
 
 	cfg = c
 
-	for name, value := range cfg.Options {
-		if f, ok := configBinds[name]; ok && !f.Changed {
-			f.Value.Set(value)
+	values, err := configValues(cfg)
+	if err != nil {
+		return err
+	}
+
+	return applyConfig(cmd.Flags(), values)
+}
+
+// configValues returns the values of the config options as strings for
+// pflag.Value.Set, lists are encoded as CSV like the slice flags expect it.
+func configValues(c erpel.Config) (map[string]string, error) {
+	values := make(map[string]string, len(c.Options)+len(c.Lists))
+	for name, value := range c.Options {
+		values[name] = value
+	}
+
+	for name, list := range c.Lists {
+		buf := bytes.NewBuffer(nil)
+		wr := csv.NewWriter(buf)
+		if err := wr.Write(list); err != nil {
+			return nil, err
+		}
+		wr.Flush()
+		if err := wr.Error(); err != nil {
+			return nil, err
+		}
+
+		values[name] = strings.TrimSuffix(buf.String(), "\n")
+	}
+
+	return values, nil
+}
+
+// applyConfig sets the flags bound to the config options, unless they have
+// been set on the command line. Several commands bind their own flags to the
+// same variables, so only the flags in the set of the command are used.
+func applyConfig(flags *pflag.FlagSet, options map[string]string) error {
+	for name, value := range options {
+		var bound []*pflag.Flag
+		changed := false
+		for _, f := range configBinds[name] {
+			if flags.Lookup(f.Name) != f {
+				continue
+			}
+
+			bound = append(bound, f)
+			if f.Changed {
+				changed = true
+			}
+		}
+
+		if changed {
+			continue
+		}
+
+		for _, f := range bound {
+			if err := f.Value.Set(value); err != nil {
+				return fmt.Errorf("config option %v: %v", name, err)
+			}
 		}
 	}
 
 	return nil
 }
 
-var configBinds map[string]*pflag.Flag
+var configBinds map[string][]*pflag.Flag
 
 func bindConfigValue(name string, flag *pflag.Flag) {
 	if configBinds == nil {
-		configBinds = make(map[string]*pflag.Flag)
+		configBinds = make(map[string][]*pflag.Flag)
 	}
 
-	configBinds[name] = flag
+	configBinds[name] = append(configBinds[name], flag)
 }
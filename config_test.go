@@ -0,0 +1,58 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/fd0/erpel/internal/erpel"
+	"github.com/spf13/pflag"
+)
+
+func TestApplyConfig(t *testing.T) {
+	// both commands use the same variable and config option
+	a := pflag.NewFlagSet("a", pflag.ContinueOnError)
+	addRulesFlags(a)
+	b := pflag.NewFlagSet("b", pflag.ContinueOnError)
+	addRulesFlags(b)
+
+	options, err := configValues(erpel.Config{
+		Options: map[string]string{"rules_recursive": "true"},
+		Lists:   map[string][]string{"rules_dir": {"/etc/erpel/rules.d", "/srv/rules,local.d"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := a.Parse([]string{"-r", "/tmp/rules"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applyConfig(a, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"/tmp/rules"}; !reflect.DeepEqual(rulesDirs, want) {
+		t.Errorf("config overrides the command line, want %v, got %v", want, rulesDirs)
+	}
+
+	if !rulesFilter.Recursive {
+		t.Errorf("option rules_recursive from the config was not applied")
+	}
+
+	rulesFilter.Recursive = false
+	if err := b.Parse(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := applyConfig(b, options); err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"/etc/erpel/rules.d", "/srv/rules,local.d"}; !reflect.DeepEqual(rulesDirs, want) {
+		t.Errorf("option rules_dir from the config was not applied, want %v, got %v", want, rulesDirs)
+	}
+
+	if err := applyConfig(b, map[string]string{"rules_recursive": "maybe"}); err == nil {
+		t.Errorf("expected error for invalid value not found")
+	}
+}
@@ -23,6 +23,15 @@ type State struct {
 
 	// collection of all fields encountered during parsing
 	Fields map[string]erpelRules.Field
+
+	// options for log files, indexed by the (quoted) file name
+	Files map[string]erpelRules.Field
+
+	// overrides for rules files, indexed by the (quoted) rules file name
+	Overrides map[string]erpelRules.Field
+
+	// Includes lists the (quoted) names of the files to include
+	Includes []string
 }
 
 func (c *State) setGlobal(key, value string) {
@@ -31,6 +40,10 @@ func (c *State) setGlobal(key, value string) {
 	c.Global[key] = value
 }
 
+func (c *State) addInclude(filename string) {
+	c.Includes = append(c.Includes, filename)
+}
+
 func (c *State) newField(name string) {
 	name = strings.TrimSpace(name)
 	f := make(erpelRules.Field)
@@ -38,6 +51,18 @@ func (c *State) newField(name string) {
 	c.currentField = f
 }
 
+func (c *State) newFile(name string) {
+	f := make(erpelRules.Field)
+	c.Files[name] = f
+	c.currentField = f
+}
+
+func (c *State) newOverride(name string) {
+	f := make(erpelRules.Field)
+	c.Overrides[name] = f
+	c.currentField = f
+}
+
 func (c *State) setField(key, value string) {
 	key = strings.TrimSpace(key)
 	value = strings.TrimSpace(value)
@@ -57,8 +82,10 @@ func (c *State) set(key, value string) {
 func Parse(data string) (State, error) {
 	c := &erpelParser{
 		State: State{
-			Fields: make(map[string]erpelRules.Field),
-			Global: make(map[string]string),
+			Fields:    make(map[string]erpelRules.Field),
+			Files:     make(map[string]erpelRules.Field),
+			Overrides: make(map[string]erpelRules.Field),
+			Global:    make(map[string]string),
 		},
 		Buffer: data,
 	}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"reflect"
 	"testing"
 
 	erpelRules "github.com/fd0/erpel/internal/rules"
@@ -401,6 +402,65 @@ var testConfigs = []struct {
 			},
 		},
 	},
+	{
+		cfg: `
+		state_dir = '/foo'
+
+		file '/var/log/legacy.log' {
+			charset = 'latin1' # old application
+		}
+
+		file "/var/log/*.log" { charset = "utf-8" }
+	`,
+		state: State{
+			Global: map[string]string{
+				"state_dir": "'/foo'",
+			},
+			Files: map[string]erpelRules.Field{
+				"'/var/log/legacy.log'": erpelRules.Field{
+					"charset": "'latin1'",
+				},
+				`"/var/log/*.log"`: erpelRules.Field{
+					"charset": `"utf-8"`,
+				},
+			},
+		},
+	},
+	{
+		cfg: `
+		override 'dovecot' {
+			disable_templates = ['login']
+			pattern.num = '\d{1,5}'
+		}
+
+		override "postfix" { disable = 'true' }
+	`,
+		state: State{
+			Global: map[string]string{},
+			Overrides: map[string]erpelRules.Field{
+				"'dovecot'": erpelRules.Field{
+					"disable_templates": "['login']",
+					"pattern.num":       `'\d{1,5}'`,
+				},
+				`"postfix"`: erpelRules.Field{
+					"disable": "'true'",
+				},
+			},
+		},
+	},
+	{
+		cfg: `
+		include = '/etc/erpel/conf.d/*.conf'
+		include_dir = 'foo'
+		include = "local.conf" # comment
+	`,
+		state: State{
+			Global: map[string]string{
+				"include_dir": "'foo'",
+			},
+			Includes: []string{"'/etc/erpel/conf.d/*.conf'", `"local.conf"`},
+		},
+	},
 }
 
 func equalMap(t testing.TB, name string, want map[string]string, got map[string]string) {
@@ -468,6 +528,12 @@ func TestParseConfig(t *testing.T) {
 
 		equalMap(t, "globals", test.state.Global, state.Global)
 		equalFields(t, test.state.Fields, state.Fields)
+		equalFields(t, test.state.Files, state.Files)
+		equalFields(t, test.state.Overrides, state.Overrides)
+
+		if !reflect.DeepEqual(test.state.Includes, state.Includes) {
+			t.Errorf("config %d: wrong includes, want %q, got %q", i, test.state.Includes, state.Includes)
+		}
 	}
 }
 
@@ -18,10 +18,15 @@ const (
 	ruleLine
 	ruleName
 	ruleStatement
+	ruleInclude
 	ruleField
 	ruleFieldName
 	ruleFieldData
 	ruleFieldStatement
+	ruleFile
+	ruleFileName
+	ruleOverride
+	ruleOverrideName
 	ruleValue
 	ruleString
 	ruleList
@@ -41,6 +46,11 @@ const (
 	ruleAction5
 	ruleAction6
 	ruleAction7
+	ruleAction8
+	ruleAction9
+	ruleAction10
+	ruleAction11
+	ruleAction12
 
 	rulePre
 	ruleIn
@@ -53,10 +63,15 @@ var rul3s = [...]string{
 	"Line",
 	"Name",
 	"Statement",
+	"Include",
 	"Field",
 	"FieldName",
 	"FieldData",
 	"FieldStatement",
+	"File",
+	"FileName",
+	"Override",
+	"OverrideName",
 	"Value",
 	"String",
 	"List",
@@ -76,6 +91,11 @@ var rul3s = [...]string{
 	"Action5",
 	"Action6",
 	"Action7",
+	"Action8",
+	"Action9",
+	"Action10",
+	"Action11",
+	"Action12",
 
 	"Pre_",
 	"_In_",
@@ -396,7 +416,7 @@ type erpelParser struct {
 
 	Buffer string
 	buffer []rune
-	rules  [28]func() bool
+	rules  [38]func() bool
 	Parse  func(rule ...int) error
 	Reset  func()
 	Pretty bool
@@ -485,17 +505,29 @@ func (p *erpelParser) Execute() {
 		case ruleAction1:
 			p.set(p.name, p.value)
 		case ruleAction2:
-			p.inField = false
+			p.addInclude(p.value)
 		case ruleAction3:
+			p.inField = false
+		case ruleAction4:
 			p.inField = true
 			p.newField(buffer[begin:end])
-		case ruleAction4:
-			p.value = buffer[begin:end]
 		case ruleAction5:
-			p.value = buffer[begin:end]
+			p.inField = false
 		case ruleAction6:
-			p.value = buffer[begin:end]
+			p.inField = true
+			p.newFile(p.value)
 		case ruleAction7:
+			p.inField = false
+		case ruleAction8:
+			p.inField = true
+			p.newOverride(p.value)
+		case ruleAction9:
+			p.value = buffer[begin:end]
+		case ruleAction10:
+			p.value = buffer[begin:end]
+		case ruleAction11:
+			p.value = buffer[begin:end]
+		case ruleAction12:
 			p.value = buffer[begin:end]
 
 		}
@@ -608,7 +640,7 @@ func (p *erpelParser) Init() {
 			position, tokenIndex, depth = position0, tokenIndex0, depth0
 			return false
 		},
-		/* 1 Line <- <((Field / Statement)? s Comment?)> */
+		/* 1 Line <- <((Field / File / Override / Include / Statement)? s Comment?)> */
 		func() bool {
 			position6, tokenIndex6, depth6 := position, tokenIndex, depth
 			{
@@ -623,6 +655,24 @@ func (p *erpelParser) Init() {
 						}
 						goto l10
 					l11:
+						position, tokenIndex, depth = position10, tokenIndex10, depth10
+						if !_rules[ruleFile]() {
+							goto l12
+						}
+						goto l10
+					l12:
+						position, tokenIndex, depth = position10, tokenIndex10, depth10
+						if !_rules[ruleOverride]() {
+							goto l13
+						}
+						goto l10
+					l13:
+						position, tokenIndex, depth = position10, tokenIndex10, depth10
+						if !_rules[ruleInclude]() {
+							goto l14
+						}
+						goto l10
+					l14:
 						position, tokenIndex, depth = position10, tokenIndex10, depth10
 						if !_rules[ruleStatement]() {
 							goto l8
@@ -638,15 +688,15 @@ func (p *erpelParser) Init() {
 					goto l6
 				}
 				{
-					position12, tokenIndex12, depth12 := position, tokenIndex, depth
+					position15, tokenIndex15, depth15 := position, tokenIndex, depth
 					if !_rules[ruleComment]() {
-						goto l12
+						goto l15
 					}
-					goto l13
-				l12:
-					position, tokenIndex, depth = position12, tokenIndex12, depth12
+					goto l16
+				l15:
+					position, tokenIndex, depth = position15, tokenIndex15, depth15
 				}
-			l13:
+			l16:
 				depth--
 				add(ruleLine, position7)
 			}
@@ -655,939 +705,1426 @@ func (p *erpelParser) Init() {
 			position, tokenIndex, depth = position6, tokenIndex6, depth6
 			return false
 		},
-		/* 2 Name <- <(<([a-z] / [A-Z] / [0-9] / '-' / '_')+> Action0)> */
+		/* 2 Name <- <(<([a-z] / [A-Z] / [0-9] / '-' / '_' / '.')+> Action0)> */
 		func() bool {
-			position14, tokenIndex14, depth14 := position, tokenIndex, depth
+			position17, tokenIndex17, depth17 := position, tokenIndex, depth
 			{
-				position15 := position
+				position18 := position
 				depth++
 				{
-					position16 := position
+					position19 := position
 					depth++
 					{
-						position19, tokenIndex19, depth19 := position, tokenIndex, depth
+						position22, tokenIndex22, depth22 := position, tokenIndex, depth
 						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l20
+							goto l23
 						}
 						position++
-						goto l19
-					l20:
-						position, tokenIndex, depth = position19, tokenIndex19, depth19
+						goto l22
+					l23:
+						position, tokenIndex, depth = position22, tokenIndex22, depth22
 						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l21
+							goto l24
 						}
 						position++
-						goto l19
-					l21:
-						position, tokenIndex, depth = position19, tokenIndex19, depth19
+						goto l22
+					l24:
+						position, tokenIndex, depth = position22, tokenIndex22, depth22
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l22
+							goto l25
 						}
 						position++
-						goto l19
-					l22:
-						position, tokenIndex, depth = position19, tokenIndex19, depth19
+						goto l22
+					l25:
+						position, tokenIndex, depth = position22, tokenIndex22, depth22
 						if buffer[position] != rune('-') {
-							goto l23
+							goto l26
 						}
 						position++
-						goto l19
-					l23:
-						position, tokenIndex, depth = position19, tokenIndex19, depth19
+						goto l22
+					l26:
+						position, tokenIndex, depth = position22, tokenIndex22, depth22
 						if buffer[position] != rune('_') {
-							goto l14
+							goto l27
+						}
+						position++
+						goto l22
+					l27:
+						position, tokenIndex, depth = position22, tokenIndex22, depth22
+						if buffer[position] != rune('.') {
+							goto l17
 						}
 						position++
 					}
-				l19:
-				l17:
+				l22:
+				l20:
 					{
-						position18, tokenIndex18, depth18 := position, tokenIndex, depth
+						position21, tokenIndex21, depth21 := position, tokenIndex, depth
 						{
-							position24, tokenIndex24, depth24 := position, tokenIndex, depth
+							position28, tokenIndex28, depth28 := position, tokenIndex, depth
 							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l25
+								goto l29
 							}
 							position++
-							goto l24
-						l25:
-							position, tokenIndex, depth = position24, tokenIndex24, depth24
+							goto l28
+						l29:
+							position, tokenIndex, depth = position28, tokenIndex28, depth28
 							if c := buffer[position]; c < rune('A') || c > rune('Z') {
-								goto l26
+								goto l30
 							}
 							position++
-							goto l24
-						l26:
-							position, tokenIndex, depth = position24, tokenIndex24, depth24
+							goto l28
+						l30:
+							position, tokenIndex, depth = position28, tokenIndex28, depth28
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l27
+								goto l31
 							}
 							position++
-							goto l24
-						l27:
-							position, tokenIndex, depth = position24, tokenIndex24, depth24
+							goto l28
+						l31:
+							position, tokenIndex, depth = position28, tokenIndex28, depth28
 							if buffer[position] != rune('-') {
-								goto l28
+								goto l32
 							}
 							position++
-							goto l24
-						l28:
-							position, tokenIndex, depth = position24, tokenIndex24, depth24
+							goto l28
+						l32:
+							position, tokenIndex, depth = position28, tokenIndex28, depth28
 							if buffer[position] != rune('_') {
-								goto l18
+								goto l33
+							}
+							position++
+							goto l28
+						l33:
+							position, tokenIndex, depth = position28, tokenIndex28, depth28
+							if buffer[position] != rune('.') {
+								goto l21
 							}
 							position++
 						}
-					l24:
-						goto l17
-					l18:
-						position, tokenIndex, depth = position18, tokenIndex18, depth18
+					l28:
+						goto l20
+					l21:
+						position, tokenIndex, depth = position21, tokenIndex21, depth21
 					}
 					depth--
-					add(rulePegText, position16)
+					add(rulePegText, position19)
 				}
 				if !_rules[ruleAction0]() {
-					goto l14
+					goto l17
 				}
 				depth--
-				add(ruleName, position15)
+				add(ruleName, position18)
 			}
 			return true
-		l14:
-			position, tokenIndex, depth = position14, tokenIndex14, depth14
+		l17:
+			position, tokenIndex, depth = position17, tokenIndex17, depth17
 			return false
 		},
 		/* 3 Statement <- <(s Name s '=' s Value Action1)> */
 		func() bool {
-			position29, tokenIndex29, depth29 := position, tokenIndex, depth
+			position34, tokenIndex34, depth34 := position, tokenIndex, depth
 			{
-				position30 := position
+				position35 := position
 				depth++
 				if !_rules[rules]() {
-					goto l29
+					goto l34
 				}
 				if !_rules[ruleName]() {
-					goto l29
+					goto l34
 				}
 				if !_rules[rules]() {
-					goto l29
+					goto l34
 				}
 				if buffer[position] != rune('=') {
-					goto l29
+					goto l34
 				}
 				position++
 				if !_rules[rules]() {
-					goto l29
+					goto l34
 				}
 				if !_rules[ruleValue]() {
-					goto l29
+					goto l34
 				}
 				if !_rules[ruleAction1]() {
-					goto l29
+					goto l34
 				}
 				depth--
-				add(ruleStatement, position30)
+				add(ruleStatement, position35)
 			}
 			return true
-		l29:
-			position, tokenIndex, depth = position29, tokenIndex29, depth29
+		l34:
+			position, tokenIndex, depth = position34, tokenIndex34, depth34
 			return false
 		},
-		/* 4 Field <- <(s (('f' / 'F') ('i' / 'I') ('e' / 'E') ('l' / 'L') ('d' / 'D')) s FieldName s '{' FieldData '}' Action2)> */
+		/* 4 Include <- <(s (('i' / 'I') ('n' / 'N') ('c' / 'C') ('l' / 'L') ('u' / 'U') ('d' / 'D') ('e' / 'E')) s '=' s String Action2)> */
 		func() bool {
-			position31, tokenIndex31, depth31 := position, tokenIndex, depth
+			position36, tokenIndex36, depth36 := position, tokenIndex, depth
 			{
-				position32 := position
+				position37 := position
 				depth++
 				if !_rules[rules]() {
-					goto l31
+					goto l36
+				}
+				{
+					position38, tokenIndex38, depth38 := position, tokenIndex, depth
+					if buffer[position] != rune('i') {
+						goto l39
+					}
+					position++
+					goto l38
+				l39:
+					position, tokenIndex, depth = position38, tokenIndex38, depth38
+					if buffer[position] != rune('I') {
+						goto l36
+					}
+					position++
+				}
+			l38:
+				{
+					position40, tokenIndex40, depth40 := position, tokenIndex, depth
+					if buffer[position] != rune('n') {
+						goto l41
+					}
+					position++
+					goto l40
+				l41:
+					position, tokenIndex, depth = position40, tokenIndex40, depth40
+					if buffer[position] != rune('N') {
+						goto l36
+					}
+					position++
+				}
+			l40:
+				{
+					position42, tokenIndex42, depth42 := position, tokenIndex, depth
+					if buffer[position] != rune('c') {
+						goto l43
+					}
+					position++
+					goto l42
+				l43:
+					position, tokenIndex, depth = position42, tokenIndex42, depth42
+					if buffer[position] != rune('C') {
+						goto l36
+					}
+					position++
+				}
+			l42:
+				{
+					position44, tokenIndex44, depth44 := position, tokenIndex, depth
+					if buffer[position] != rune('l') {
+						goto l45
+					}
+					position++
+					goto l44
+				l45:
+					position, tokenIndex, depth = position44, tokenIndex44, depth44
+					if buffer[position] != rune('L') {
+						goto l36
+					}
+					position++
+				}
+			l44:
+				{
+					position46, tokenIndex46, depth46 := position, tokenIndex, depth
+					if buffer[position] != rune('u') {
+						goto l47
+					}
+					position++
+					goto l46
+				l47:
+					position, tokenIndex, depth = position46, tokenIndex46, depth46
+					if buffer[position] != rune('U') {
+						goto l36
+					}
+					position++
 				}
+			l46:
 				{
-					position33, tokenIndex33, depth33 := position, tokenIndex, depth
+					position48, tokenIndex48, depth48 := position, tokenIndex, depth
+					if buffer[position] != rune('d') {
+						goto l49
+					}
+					position++
+					goto l48
+				l49:
+					position, tokenIndex, depth = position48, tokenIndex48, depth48
+					if buffer[position] != rune('D') {
+						goto l36
+					}
+					position++
+				}
+			l48:
+				{
+					position50, tokenIndex50, depth50 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l51
+					}
+					position++
+					goto l50
+				l51:
+					position, tokenIndex, depth = position50, tokenIndex50, depth50
+					if buffer[position] != rune('E') {
+						goto l36
+					}
+					position++
+				}
+			l50:
+				if !_rules[rules]() {
+					goto l36
+				}
+				if buffer[position] != rune('=') {
+					goto l36
+				}
+				position++
+				if !_rules[rules]() {
+					goto l36
+				}
+				if !_rules[ruleString]() {
+					goto l36
+				}
+				if !_rules[ruleAction2]() {
+					goto l36
+				}
+				depth--
+				add(ruleInclude, position37)
+			}
+			return true
+		l36:
+			position, tokenIndex, depth = position36, tokenIndex36, depth36
+			return false
+		},
+		/* 5 Field <- <(s (('f' / 'F') ('i' / 'I') ('e' / 'E') ('l' / 'L') ('d' / 'D')) s FieldName s '{' FieldData '}' Action3)> */
+		func() bool {
+			position52, tokenIndex52, depth52 := position, tokenIndex, depth
+			{
+				position53 := position
+				depth++
+				if !_rules[rules]() {
+					goto l52
+				}
+				{
+					position54, tokenIndex54, depth54 := position, tokenIndex, depth
 					if buffer[position] != rune('f') {
-						goto l34
+						goto l55
 					}
 					position++
-					goto l33
-				l34:
-					position, tokenIndex, depth = position33, tokenIndex33, depth33
+					goto l54
+				l55:
+					position, tokenIndex, depth = position54, tokenIndex54, depth54
 					if buffer[position] != rune('F') {
-						goto l31
+						goto l52
 					}
 					position++
 				}
-			l33:
+			l54:
 				{
-					position35, tokenIndex35, depth35 := position, tokenIndex, depth
+					position56, tokenIndex56, depth56 := position, tokenIndex, depth
 					if buffer[position] != rune('i') {
-						goto l36
+						goto l57
 					}
 					position++
-					goto l35
-				l36:
-					position, tokenIndex, depth = position35, tokenIndex35, depth35
+					goto l56
+				l57:
+					position, tokenIndex, depth = position56, tokenIndex56, depth56
 					if buffer[position] != rune('I') {
-						goto l31
+						goto l52
 					}
 					position++
 				}
-			l35:
+			l56:
 				{
-					position37, tokenIndex37, depth37 := position, tokenIndex, depth
+					position58, tokenIndex58, depth58 := position, tokenIndex, depth
 					if buffer[position] != rune('e') {
-						goto l38
+						goto l59
 					}
 					position++
-					goto l37
-				l38:
-					position, tokenIndex, depth = position37, tokenIndex37, depth37
+					goto l58
+				l59:
+					position, tokenIndex, depth = position58, tokenIndex58, depth58
 					if buffer[position] != rune('E') {
-						goto l31
+						goto l52
 					}
 					position++
 				}
-			l37:
+			l58:
 				{
-					position39, tokenIndex39, depth39 := position, tokenIndex, depth
+					position60, tokenIndex60, depth60 := position, tokenIndex, depth
 					if buffer[position] != rune('l') {
-						goto l40
+						goto l61
 					}
 					position++
-					goto l39
-				l40:
-					position, tokenIndex, depth = position39, tokenIndex39, depth39
+					goto l60
+				l61:
+					position, tokenIndex, depth = position60, tokenIndex60, depth60
 					if buffer[position] != rune('L') {
-						goto l31
+						goto l52
 					}
 					position++
 				}
-			l39:
+			l60:
 				{
-					position41, tokenIndex41, depth41 := position, tokenIndex, depth
+					position62, tokenIndex62, depth62 := position, tokenIndex, depth
 					if buffer[position] != rune('d') {
-						goto l42
+						goto l63
 					}
 					position++
-					goto l41
-				l42:
-					position, tokenIndex, depth = position41, tokenIndex41, depth41
+					goto l62
+				l63:
+					position, tokenIndex, depth = position62, tokenIndex62, depth62
 					if buffer[position] != rune('D') {
-						goto l31
+						goto l52
 					}
 					position++
 				}
-			l41:
+			l62:
 				if !_rules[rules]() {
-					goto l31
+					goto l52
 				}
 				if !_rules[ruleFieldName]() {
-					goto l31
+					goto l52
 				}
 				if !_rules[rules]() {
-					goto l31
+					goto l52
 				}
 				if buffer[position] != rune('{') {
-					goto l31
+					goto l52
 				}
 				position++
 				if !_rules[ruleFieldData]() {
-					goto l31
+					goto l52
 				}
 				if buffer[position] != rune('}') {
-					goto l31
+					goto l52
 				}
 				position++
-				if !_rules[ruleAction2]() {
-					goto l31
+				if !_rules[ruleAction3]() {
+					goto l52
 				}
 				depth--
-				add(ruleField, position32)
+				add(ruleField, position53)
 			}
 			return true
-		l31:
-			position, tokenIndex, depth = position31, tokenIndex31, depth31
+		l52:
+			position, tokenIndex, depth = position52, tokenIndex52, depth52
 			return false
 		},
-		/* 5 FieldName <- <(<([a-z] / [A-Z] / [0-9] / '-' / '_')+> Action3)> */
+		/* 6 FieldName <- <(<([a-z] / [A-Z] / [0-9] / '-' / '_')+> Action4)> */
 		func() bool {
-			position43, tokenIndex43, depth43 := position, tokenIndex, depth
+			position64, tokenIndex64, depth64 := position, tokenIndex, depth
 			{
-				position44 := position
+				position65 := position
 				depth++
 				{
-					position45 := position
+					position66 := position
 					depth++
 					{
-						position48, tokenIndex48, depth48 := position, tokenIndex, depth
+						position69, tokenIndex69, depth69 := position, tokenIndex, depth
 						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l49
+							goto l70
 						}
 						position++
-						goto l48
-					l49:
-						position, tokenIndex, depth = position48, tokenIndex48, depth48
+						goto l69
+					l70:
+						position, tokenIndex, depth = position69, tokenIndex69, depth69
 						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l50
+							goto l71
 						}
 						position++
-						goto l48
-					l50:
-						position, tokenIndex, depth = position48, tokenIndex48, depth48
+						goto l69
+					l71:
+						position, tokenIndex, depth = position69, tokenIndex69, depth69
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l51
+							goto l72
 						}
 						position++
-						goto l48
-					l51:
-						position, tokenIndex, depth = position48, tokenIndex48, depth48
+						goto l69
+					l72:
+						position, tokenIndex, depth = position69, tokenIndex69, depth69
 						if buffer[position] != rune('-') {
-							goto l52
+							goto l73
 						}
 						position++
-						goto l48
-					l52:
-						position, tokenIndex, depth = position48, tokenIndex48, depth48
+						goto l69
+					l73:
+						position, tokenIndex, depth = position69, tokenIndex69, depth69
 						if buffer[position] != rune('_') {
-							goto l43
+							goto l64
 						}
 						position++
 					}
-				l48:
-				l46:
+				l69:
+				l67:
 					{
-						position47, tokenIndex47, depth47 := position, tokenIndex, depth
+						position68, tokenIndex68, depth68 := position, tokenIndex, depth
 						{
-							position53, tokenIndex53, depth53 := position, tokenIndex, depth
+							position74, tokenIndex74, depth74 := position, tokenIndex, depth
 							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l54
+								goto l75
 							}
 							position++
-							goto l53
-						l54:
-							position, tokenIndex, depth = position53, tokenIndex53, depth53
+							goto l74
+						l75:
+							position, tokenIndex, depth = position74, tokenIndex74, depth74
 							if c := buffer[position]; c < rune('A') || c > rune('Z') {
-								goto l55
+								goto l76
 							}
 							position++
-							goto l53
-						l55:
-							position, tokenIndex, depth = position53, tokenIndex53, depth53
+							goto l74
+						l76:
+							position, tokenIndex, depth = position74, tokenIndex74, depth74
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l56
+								goto l77
 							}
 							position++
-							goto l53
-						l56:
-							position, tokenIndex, depth = position53, tokenIndex53, depth53
+							goto l74
+						l77:
+							position, tokenIndex, depth = position74, tokenIndex74, depth74
 							if buffer[position] != rune('-') {
-								goto l57
+								goto l78
 							}
 							position++
-							goto l53
-						l57:
-							position, tokenIndex, depth = position53, tokenIndex53, depth53
+							goto l74
+						l78:
+							position, tokenIndex, depth = position74, tokenIndex74, depth74
 							if buffer[position] != rune('_') {
-								goto l47
+								goto l68
 							}
 							position++
 						}
-					l53:
-						goto l46
-					l47:
-						position, tokenIndex, depth = position47, tokenIndex47, depth47
+					l74:
+						goto l67
+					l68:
+						position, tokenIndex, depth = position68, tokenIndex68, depth68
 					}
 					depth--
-					add(rulePegText, position45)
+					add(rulePegText, position66)
 				}
-				if !_rules[ruleAction3]() {
-					goto l43
+				if !_rules[ruleAction4]() {
+					goto l64
 				}
 				depth--
-				add(ruleFieldName, position44)
+				add(ruleFieldName, position65)
 			}
 			return true
-		l43:
-			position, tokenIndex, depth = position43, tokenIndex43, depth43
+		l64:
+			position, tokenIndex, depth = position64, tokenIndex64, depth64
 			return false
 		},
-		/* 6 FieldData <- <((FieldStatement EOL)* FieldStatement?)> */
+		/* 7 FieldData <- <((FieldStatement EOL)* FieldStatement?)> */
 		func() bool {
 			{
-				position59 := position
+				position80 := position
 				depth++
-			l60:
+			l81:
 				{
-					position61, tokenIndex61, depth61 := position, tokenIndex, depth
+					position82, tokenIndex82, depth82 := position, tokenIndex, depth
 					if !_rules[ruleFieldStatement]() {
-						goto l61
+						goto l82
 					}
 					if !_rules[ruleEOL]() {
-						goto l61
+						goto l82
 					}
-					goto l60
-				l61:
-					position, tokenIndex, depth = position61, tokenIndex61, depth61
+					goto l81
+				l82:
+					position, tokenIndex, depth = position82, tokenIndex82, depth82
 				}
 				{
-					position62, tokenIndex62, depth62 := position, tokenIndex, depth
+					position83, tokenIndex83, depth83 := position, tokenIndex, depth
 					if !_rules[ruleFieldStatement]() {
-						goto l62
+						goto l83
 					}
-					goto l63
-				l62:
-					position, tokenIndex, depth = position62, tokenIndex62, depth62
+					goto l84
+				l83:
+					position, tokenIndex, depth = position83, tokenIndex83, depth83
 				}
-			l63:
+			l84:
 				depth--
-				add(ruleFieldData, position59)
+				add(ruleFieldData, position80)
 			}
 			return true
 		},
-		/* 7 FieldStatement <- <(Statement? s Comment?)> */
+		/* 8 FieldStatement <- <(Statement? s Comment?)> */
 		func() bool {
-			position64, tokenIndex64, depth64 := position, tokenIndex, depth
+			position85, tokenIndex85, depth85 := position, tokenIndex, depth
 			{
-				position65 := position
+				position86 := position
 				depth++
 				{
-					position66, tokenIndex66, depth66 := position, tokenIndex, depth
+					position87, tokenIndex87, depth87 := position, tokenIndex, depth
 					if !_rules[ruleStatement]() {
-						goto l66
+						goto l87
 					}
-					goto l67
-				l66:
-					position, tokenIndex, depth = position66, tokenIndex66, depth66
+					goto l88
+				l87:
+					position, tokenIndex, depth = position87, tokenIndex87, depth87
 				}
-			l67:
+			l88:
 				if !_rules[rules]() {
-					goto l64
+					goto l85
 				}
 				{
-					position68, tokenIndex68, depth68 := position, tokenIndex, depth
+					position89, tokenIndex89, depth89 := position, tokenIndex, depth
 					if !_rules[ruleComment]() {
-						goto l68
+						goto l89
 					}
-					goto l69
-				l68:
-					position, tokenIndex, depth = position68, tokenIndex68, depth68
+					goto l90
+				l89:
+					position, tokenIndex, depth = position89, tokenIndex89, depth89
 				}
-			l69:
+			l90:
 				depth--
-				add(ruleFieldStatement, position65)
+				add(ruleFieldStatement, position86)
 			}
 			return true
-		l64:
-			position, tokenIndex, depth = position64, tokenIndex64, depth64
+		l85:
+			position, tokenIndex, depth = position85, tokenIndex85, depth85
+			return false
+		},
+		/* 9 File <- <(s (('f' / 'F') ('i' / 'I') ('l' / 'L') ('e' / 'E')) s FileName s '{' FieldData '}' Action5)> */
+		func() bool {
+			position91, tokenIndex91, depth91 := position, tokenIndex, depth
+			{
+				position92 := position
+				depth++
+				if !_rules[rules]() {
+					goto l91
+				}
+				{
+					position93, tokenIndex93, depth93 := position, tokenIndex, depth
+					if buffer[position] != rune('f') {
+						goto l94
+					}
+					position++
+					goto l93
+				l94:
+					position, tokenIndex, depth = position93, tokenIndex93, depth93
+					if buffer[position] != rune('F') {
+						goto l91
+					}
+					position++
+				}
+			l93:
+				{
+					position95, tokenIndex95, depth95 := position, tokenIndex, depth
+					if buffer[position] != rune('i') {
+						goto l96
+					}
+					position++
+					goto l95
+				l96:
+					position, tokenIndex, depth = position95, tokenIndex95, depth95
+					if buffer[position] != rune('I') {
+						goto l91
+					}
+					position++
+				}
+			l95:
+				{
+					position97, tokenIndex97, depth97 := position, tokenIndex, depth
+					if buffer[position] != rune('l') {
+						goto l98
+					}
+					position++
+					goto l97
+				l98:
+					position, tokenIndex, depth = position97, tokenIndex97, depth97
+					if buffer[position] != rune('L') {
+						goto l91
+					}
+					position++
+				}
+			l97:
+				{
+					position99, tokenIndex99, depth99 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l100
+					}
+					position++
+					goto l99
+				l100:
+					position, tokenIndex, depth = position99, tokenIndex99, depth99
+					if buffer[position] != rune('E') {
+						goto l91
+					}
+					position++
+				}
+			l99:
+				if !_rules[rules]() {
+					goto l91
+				}
+				if !_rules[ruleFileName]() {
+					goto l91
+				}
+				if !_rules[rules]() {
+					goto l91
+				}
+				if buffer[position] != rune('{') {
+					goto l91
+				}
+				position++
+				if !_rules[ruleFieldData]() {
+					goto l91
+				}
+				if buffer[position] != rune('}') {
+					goto l91
+				}
+				position++
+				if !_rules[ruleAction5]() {
+					goto l91
+				}
+				depth--
+				add(ruleFile, position92)
+			}
+			return true
+		l91:
+			position, tokenIndex, depth = position91, tokenIndex91, depth91
 			return false
 		},
-		/* 8 Value <- <(List / String)> */
+		/* 10 FileName <- <(String Action6)> */
 		func() bool {
-			position70, tokenIndex70, depth70 := position, tokenIndex, depth
+			position101, tokenIndex101, depth101 := position, tokenIndex, depth
 			{
-				position71 := position
+				position102 := position
 				depth++
+				if !_rules[ruleString]() {
+					goto l101
+				}
+				if !_rules[ruleAction6]() {
+					goto l101
+				}
+				depth--
+				add(ruleFileName, position102)
+			}
+			return true
+		l101:
+			position, tokenIndex, depth = position101, tokenIndex101, depth101
+			return false
+		},
+		/* 11 Override <- <(s (('o' / 'O') ('v' / 'V') ('e' / 'E') ('r' / 'R') ('r' / 'R') ('i' / 'I') ('d' / 'D') ('e' / 'E')) s OverrideName s '{' FieldData '}' Action7)> */
+		func() bool {
+			position103, tokenIndex103, depth103 := position, tokenIndex, depth
+			{
+				position104 := position
+				depth++
+				if !_rules[rules]() {
+					goto l103
+				}
+				{
+					position105, tokenIndex105, depth105 := position, tokenIndex, depth
+					if buffer[position] != rune('o') {
+						goto l106
+					}
+					position++
+					goto l105
+				l106:
+					position, tokenIndex, depth = position105, tokenIndex105, depth105
+					if buffer[position] != rune('O') {
+						goto l103
+					}
+					position++
+				}
+			l105:
+				{
+					position107, tokenIndex107, depth107 := position, tokenIndex, depth
+					if buffer[position] != rune('v') {
+						goto l108
+					}
+					position++
+					goto l107
+				l108:
+					position, tokenIndex, depth = position107, tokenIndex107, depth107
+					if buffer[position] != rune('V') {
+						goto l103
+					}
+					position++
+				}
+			l107:
+				{
+					position109, tokenIndex109, depth109 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l110
+					}
+					position++
+					goto l109
+				l110:
+					position, tokenIndex, depth = position109, tokenIndex109, depth109
+					if buffer[position] != rune('E') {
+						goto l103
+					}
+					position++
+				}
+			l109:
+				{
+					position111, tokenIndex111, depth111 := position, tokenIndex, depth
+					if buffer[position] != rune('r') {
+						goto l112
+					}
+					position++
+					goto l111
+				l112:
+					position, tokenIndex, depth = position111, tokenIndex111, depth111
+					if buffer[position] != rune('R') {
+						goto l103
+					}
+					position++
+				}
+			l111:
+				{
+					position113, tokenIndex113, depth113 := position, tokenIndex, depth
+					if buffer[position] != rune('r') {
+						goto l114
+					}
+					position++
+					goto l113
+				l114:
+					position, tokenIndex, depth = position113, tokenIndex113, depth113
+					if buffer[position] != rune('R') {
+						goto l103
+					}
+					position++
+				}
+			l113:
 				{
-					position72, tokenIndex72, depth72 := position, tokenIndex, depth
+					position115, tokenIndex115, depth115 := position, tokenIndex, depth
+					if buffer[position] != rune('i') {
+						goto l116
+					}
+					position++
+					goto l115
+				l116:
+					position, tokenIndex, depth = position115, tokenIndex115, depth115
+					if buffer[position] != rune('I') {
+						goto l103
+					}
+					position++
+				}
+			l115:
+				{
+					position117, tokenIndex117, depth117 := position, tokenIndex, depth
+					if buffer[position] != rune('d') {
+						goto l118
+					}
+					position++
+					goto l117
+				l118:
+					position, tokenIndex, depth = position117, tokenIndex117, depth117
+					if buffer[position] != rune('D') {
+						goto l103
+					}
+					position++
+				}
+			l117:
+				{
+					position119, tokenIndex119, depth119 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l120
+					}
+					position++
+					goto l119
+				l120:
+					position, tokenIndex, depth = position119, tokenIndex119, depth119
+					if buffer[position] != rune('E') {
+						goto l103
+					}
+					position++
+				}
+			l119:
+				if !_rules[rules]() {
+					goto l103
+				}
+				if !_rules[ruleOverrideName]() {
+					goto l103
+				}
+				if !_rules[rules]() {
+					goto l103
+				}
+				if buffer[position] != rune('{') {
+					goto l103
+				}
+				position++
+				if !_rules[ruleFieldData]() {
+					goto l103
+				}
+				if buffer[position] != rune('}') {
+					goto l103
+				}
+				position++
+				if !_rules[ruleAction7]() {
+					goto l103
+				}
+				depth--
+				add(ruleOverride, position104)
+			}
+			return true
+		l103:
+			position, tokenIndex, depth = position103, tokenIndex103, depth103
+			return false
+		},
+		/* 12 OverrideName <- <(String Action8)> */
+		func() bool {
+			position121, tokenIndex121, depth121 := position, tokenIndex, depth
+			{
+				position122 := position
+				depth++
+				if !_rules[ruleString]() {
+					goto l121
+				}
+				if !_rules[ruleAction8]() {
+					goto l121
+				}
+				depth--
+				add(ruleOverrideName, position122)
+			}
+			return true
+		l121:
+			position, tokenIndex, depth = position121, tokenIndex121, depth121
+			return false
+		},
+		/* 13 Value <- <(List / String)> */
+		func() bool {
+			position123, tokenIndex123, depth123 := position, tokenIndex, depth
+			{
+				position124 := position
+				depth++
+				{
+					position125, tokenIndex125, depth125 := position, tokenIndex, depth
 					if !_rules[ruleList]() {
-						goto l73
+						goto l126
 					}
-					goto l72
-				l73:
-					position, tokenIndex, depth = position72, tokenIndex72, depth72
+					goto l125
+				l126:
+					position, tokenIndex, depth = position125, tokenIndex125, depth125
 					if !_rules[ruleString]() {
-						goto l70
+						goto l123
 					}
 				}
-			l72:
+			l125:
 				depth--
-				add(ruleValue, position71)
+				add(ruleValue, position124)
 			}
 			return true
-		l70:
-			position, tokenIndex, depth = position70, tokenIndex70, depth70
+		l123:
+			position, tokenIndex, depth = position123, tokenIndex123, depth123
 			return false
 		},
-		/* 9 String <- <(DoubleQuotedString / SingleQuotedString / RawString)> */
+		/* 14 String <- <(DoubleQuotedString / SingleQuotedString / RawString)> */
 		func() bool {
-			position74, tokenIndex74, depth74 := position, tokenIndex, depth
+			position127, tokenIndex127, depth127 := position, tokenIndex, depth
 			{
-				position75 := position
+				position128 := position
 				depth++
 				{
-					position76, tokenIndex76, depth76 := position, tokenIndex, depth
+					position129, tokenIndex129, depth129 := position, tokenIndex, depth
 					if !_rules[ruleDoubleQuotedString]() {
-						goto l77
+						goto l130
 					}
-					goto l76
-				l77:
-					position, tokenIndex, depth = position76, tokenIndex76, depth76
+					goto l129
+				l130:
+					position, tokenIndex, depth = position129, tokenIndex129, depth129
 					if !_rules[ruleSingleQuotedString]() {
-						goto l78
+						goto l131
 					}
-					goto l76
-				l78:
-					position, tokenIndex, depth = position76, tokenIndex76, depth76
+					goto l129
+				l131:
+					position, tokenIndex, depth = position129, tokenIndex129, depth129
 					if !_rules[ruleRawString]() {
-						goto l74
+						goto l127
 					}
 				}
-			l76:
+			l129:
 				depth--
-				add(ruleString, position75)
+				add(ruleString, position128)
 			}
 			return true
-		l74:
-			position, tokenIndex, depth = position74, tokenIndex74, depth74
+		l127:
+			position, tokenIndex, depth = position127, tokenIndex127, depth127
 			return false
 		},
-		/* 10 List <- <(<('[' s (s String s ',' s)* s String s ']')> Action4)> */
+		/* 15 List <- <(<('[' s (s String s ',' s)* s String s ']')> Action9)> */
 		func() bool {
-			position79, tokenIndex79, depth79 := position, tokenIndex, depth
+			position132, tokenIndex132, depth132 := position, tokenIndex, depth
 			{
-				position80 := position
+				position133 := position
 				depth++
 				{
-					position81 := position
+					position134 := position
 					depth++
 					if buffer[position] != rune('[') {
-						goto l79
+						goto l132
 					}
 					position++
 					if !_rules[rules]() {
-						goto l79
+						goto l132
 					}
-				l82:
+				l135:
 					{
-						position83, tokenIndex83, depth83 := position, tokenIndex, depth
+						position136, tokenIndex136, depth136 := position, tokenIndex, depth
 						if !_rules[rules]() {
-							goto l83
+							goto l136
 						}
 						if !_rules[ruleString]() {
-							goto l83
+							goto l136
 						}
 						if !_rules[rules]() {
-							goto l83
+							goto l136
 						}
 						if buffer[position] != rune(',') {
-							goto l83
+							goto l136
 						}
 						position++
 						if !_rules[rules]() {
-							goto l83
+							goto l136
 						}
-						goto l82
-					l83:
-						position, tokenIndex, depth = position83, tokenIndex83, depth83
+						goto l135
+					l136:
+						position, tokenIndex, depth = position136, tokenIndex136, depth136
 					}
 					if !_rules[rules]() {
-						goto l79
+						goto l132
 					}
 					if !_rules[ruleString]() {
-						goto l79
+						goto l132
 					}
 					if !_rules[rules]() {
-						goto l79
+						goto l132
 					}
 					if buffer[position] != rune(']') {
-						goto l79
+						goto l132
 					}
 					position++
 					depth--
-					add(rulePegText, position81)
+					add(rulePegText, position134)
 				}
-				if !_rules[ruleAction4]() {
-					goto l79
+				if !_rules[ruleAction9]() {
+					goto l132
 				}
 				depth--
-				add(ruleList, position80)
+				add(ruleList, position133)
 			}
 			return true
-		l79:
-			position, tokenIndex, depth = position79, tokenIndex79, depth79
+		l132:
+			position, tokenIndex, depth = position132, tokenIndex132, depth132
 			return false
 		},
-		/* 11 SingleQuotedString <- <(<('\'' (('\\' '\'') / (!EOL !'\'' .))* '\'')> Action5)> */
+		/* 16 SingleQuotedString <- <(<('\'' (('\\' '\'') / (!EOL !'\'' .))* '\'')> Action10)> */
 		func() bool {
-			position84, tokenIndex84, depth84 := position, tokenIndex, depth
+			position137, tokenIndex137, depth137 := position, tokenIndex, depth
 			{
-				position85 := position
+				position138 := position
 				depth++
 				{
-					position86 := position
+					position139 := position
 					depth++
 					if buffer[position] != rune('\'') {
-						goto l84
+						goto l137
 					}
 					position++
-				l87:
+				l140:
 					{
-						position88, tokenIndex88, depth88 := position, tokenIndex, depth
+						position141, tokenIndex141, depth141 := position, tokenIndex, depth
 						{
-							position89, tokenIndex89, depth89 := position, tokenIndex, depth
+							position142, tokenIndex142, depth142 := position, tokenIndex, depth
 							if buffer[position] != rune('\\') {
-								goto l90
+								goto l143
 							}
 							position++
 							if buffer[position] != rune('\'') {
-								goto l90
+								goto l143
 							}
 							position++
-							goto l89
-						l90:
-							position, tokenIndex, depth = position89, tokenIndex89, depth89
+							goto l142
+						l143:
+							position, tokenIndex, depth = position142, tokenIndex142, depth142
 							{
-								position91, tokenIndex91, depth91 := position, tokenIndex, depth
+								position144, tokenIndex144, depth144 := position, tokenIndex, depth
 								if !_rules[ruleEOL]() {
-									goto l91
+									goto l144
 								}
-								goto l88
-							l91:
-								position, tokenIndex, depth = position91, tokenIndex91, depth91
+								goto l141
+							l144:
+								position, tokenIndex, depth = position144, tokenIndex144, depth144
 							}
 							{
-								position92, tokenIndex92, depth92 := position, tokenIndex, depth
+								position145, tokenIndex145, depth145 := position, tokenIndex, depth
 								if buffer[position] != rune('\'') {
-									goto l92
+									goto l145
 								}
 								position++
-								goto l88
-							l92:
-								position, tokenIndex, depth = position92, tokenIndex92, depth92
+								goto l141
+							l145:
+								position, tokenIndex, depth = position145, tokenIndex145, depth145
 							}
 							if !matchDot() {
-								goto l88
+								goto l141
 							}
 						}
-					l89:
-						goto l87
-					l88:
-						position, tokenIndex, depth = position88, tokenIndex88, depth88
+					l142:
+						goto l140
+					l141:
+						position, tokenIndex, depth = position141, tokenIndex141, depth141
 					}
 					if buffer[position] != rune('\'') {
-						goto l84
+						goto l137
 					}
 					position++
 					depth--
-					add(rulePegText, position86)
+					add(rulePegText, position139)
 				}
-				if !_rules[ruleAction5]() {
-					goto l84
+				if !_rules[ruleAction10]() {
+					goto l137
 				}
 				depth--
-				add(ruleSingleQuotedString, position85)
+				add(ruleSingleQuotedString, position138)
 			}
 			return true
-		l84:
-			position, tokenIndex, depth = position84, tokenIndex84, depth84
+		l137:
+			position, tokenIndex, depth = position137, tokenIndex137, depth137
 			return false
 		},
-		/* 12 DoubleQuotedString <- <(<('"' (('\\' '"') / (!EOL !'"' .))* '"')> Action6)> */
+		/* 17 DoubleQuotedString <- <(<('"' (('\\' '"') / (!EOL !'"' .))* '"')> Action11)> */
 		func() bool {
-			position93, tokenIndex93, depth93 := position, tokenIndex, depth
+			position146, tokenIndex146, depth146 := position, tokenIndex, depth
 			{
-				position94 := position
+				position147 := position
 				depth++
 				{
-					position95 := position
+					position148 := position
 					depth++
 					if buffer[position] != rune('"') {
-						goto l93
+						goto l146
 					}
 					position++
-				l96:
+				l149:
 					{
-						position97, tokenIndex97, depth97 := position, tokenIndex, depth
+						position150, tokenIndex150, depth150 := position, tokenIndex, depth
 						{
-							position98, tokenIndex98, depth98 := position, tokenIndex, depth
+							position151, tokenIndex151, depth151 := position, tokenIndex, depth
 							if buffer[position] != rune('\\') {
-								goto l99
+								goto l152
 							}
 							position++
 							if buffer[position] != rune('"') {
-								goto l99
+								goto l152
 							}
 							position++
-							goto l98
-						l99:
-							position, tokenIndex, depth = position98, tokenIndex98, depth98
+							goto l151
+						l152:
+							position, tokenIndex, depth = position151, tokenIndex151, depth151
 							{
-								position100, tokenIndex100, depth100 := position, tokenIndex, depth
+								position153, tokenIndex153, depth153 := position, tokenIndex, depth
 								if !_rules[ruleEOL]() {
-									goto l100
+									goto l153
 								}
-								goto l97
-							l100:
-								position, tokenIndex, depth = position100, tokenIndex100, depth100
+								goto l150
+							l153:
+								position, tokenIndex, depth = position153, tokenIndex153, depth153
 							}
 							{
-								position101, tokenIndex101, depth101 := position, tokenIndex, depth
+								position154, tokenIndex154, depth154 := position, tokenIndex, depth
 								if buffer[position] != rune('"') {
-									goto l101
+									goto l154
 								}
 								position++
-								goto l97
-							l101:
-								position, tokenIndex, depth = position101, tokenIndex101, depth101
+								goto l150
+							l154:
+								position, tokenIndex, depth = position154, tokenIndex154, depth154
 							}
 							if !matchDot() {
-								goto l97
+								goto l150
 							}
 						}
-					l98:
-						goto l96
-					l97:
-						position, tokenIndex, depth = position97, tokenIndex97, depth97
+					l151:
+						goto l149
+					l150:
+						position, tokenIndex, depth = position150, tokenIndex150, depth150
 					}
 					if buffer[position] != rune('"') {
-						goto l93
+						goto l146
 					}
 					position++
 					depth--
-					add(rulePegText, position95)
+					add(rulePegText, position148)
 				}
-				if !_rules[ruleAction6]() {
-					goto l93
+				if !_rules[ruleAction11]() {
+					goto l146
 				}
 				depth--
-				add(ruleDoubleQuotedString, position94)
+				add(ruleDoubleQuotedString, position147)
 			}
 			return true
-		l93:
-			position, tokenIndex, depth = position93, tokenIndex93, depth93
+		l146:
+			position, tokenIndex, depth = position146, tokenIndex146, depth146
 			return false
 		},
-		/* 13 RawString <- <(<('`' (!'`' .)* '`')> Action7)> */
+		/* 18 RawString <- <(<('`' (!'`' .)* '`')> Action12)> */
 		func() bool {
-			position102, tokenIndex102, depth102 := position, tokenIndex, depth
+			position155, tokenIndex155, depth155 := position, tokenIndex, depth
 			{
-				position103 := position
+				position156 := position
 				depth++
 				{
-					position104 := position
+					position157 := position
 					depth++
 					if buffer[position] != rune('`') {
-						goto l102
+						goto l155
 					}
 					position++
-				l105:
+				l158:
 					{
-						position106, tokenIndex106, depth106 := position, tokenIndex, depth
+						position159, tokenIndex159, depth159 := position, tokenIndex, depth
 						{
-							position107, tokenIndex107, depth107 := position, tokenIndex, depth
+							position160, tokenIndex160, depth160 := position, tokenIndex, depth
 							if buffer[position] != rune('`') {
-								goto l107
+								goto l160
 							}
 							position++
-							goto l106
-						l107:
-							position, tokenIndex, depth = position107, tokenIndex107, depth107
+							goto l159
+						l160:
+							position, tokenIndex, depth = position160, tokenIndex160, depth160
 						}
 						if !matchDot() {
-							goto l106
+							goto l159
 						}
-						goto l105
-					l106:
-						position, tokenIndex, depth = position106, tokenIndex106, depth106
+						goto l158
+					l159:
+						position, tokenIndex, depth = position159, tokenIndex159, depth159
 					}
 					if buffer[position] != rune('`') {
-						goto l102
+						goto l155
 					}
 					position++
 					depth--
-					add(rulePegText, position104)
+					add(rulePegText, position157)
 				}
-				if !_rules[ruleAction7]() {
-					goto l102
+				if !_rules[ruleAction12]() {
+					goto l155
 				}
 				depth--
-				add(ruleRawString, position103)
+				add(ruleRawString, position156)
 			}
 			return true
-		l102:
-			position, tokenIndex, depth = position102, tokenIndex102, depth102
+		l155:
+			position, tokenIndex, depth = position155, tokenIndex155, depth155
 			return false
 		},
-		/* 14 Comment <- <(s '#' (!EOL .)*)> */
+		/* 19 Comment <- <(s '#' (!EOL .)*)> */
 		func() bool {
-			position108, tokenIndex108, depth108 := position, tokenIndex, depth
+			position161, tokenIndex161, depth161 := position, tokenIndex, depth
 			{
-				position109 := position
+				position162 := position
 				depth++
 				if !_rules[rules]() {
-					goto l108
+					goto l161
 				}
 				if buffer[position] != rune('#') {
-					goto l108
+					goto l161
 				}
 				position++
-			l110:
+			l163:
 				{
-					position111, tokenIndex111, depth111 := position, tokenIndex, depth
+					position164, tokenIndex164, depth164 := position, tokenIndex, depth
 					{
-						position112, tokenIndex112, depth112 := position, tokenIndex, depth
+						position165, tokenIndex165, depth165 := position, tokenIndex, depth
 						if !_rules[ruleEOL]() {
-							goto l112
+							goto l165
 						}
-						goto l111
-					l112:
-						position, tokenIndex, depth = position112, tokenIndex112, depth112
+						goto l164
+					l165:
+						position, tokenIndex, depth = position165, tokenIndex165, depth165
 					}
 					if !matchDot() {
-						goto l111
+						goto l164
 					}
-					goto l110
-				l111:
-					position, tokenIndex, depth = position111, tokenIndex111, depth111
+					goto l163
+				l164:
+					position, tokenIndex, depth = position164, tokenIndex164, depth164
 				}
 				depth--
-				add(ruleComment, position109)
+				add(ruleComment, position162)
 			}
 			return true
-		l108:
-			position, tokenIndex, depth = position108, tokenIndex108, depth108
+		l161:
+			position, tokenIndex, depth = position161, tokenIndex161, depth161
 			return false
 		},
-		/* 15 EOF <- <!.> */
+		/* 20 EOF <- <!.> */
 		func() bool {
-			position113, tokenIndex113, depth113 := position, tokenIndex, depth
+			position166, tokenIndex166, depth166 := position, tokenIndex, depth
 			{
-				position114 := position
+				position167 := position
 				depth++
 				{
-					position115, tokenIndex115, depth115 := position, tokenIndex, depth
+					position168, tokenIndex168, depth168 := position, tokenIndex, depth
 					if !matchDot() {
-						goto l115
+						goto l168
 					}
-					goto l113
-				l115:
-					position, tokenIndex, depth = position115, tokenIndex115, depth115
+					goto l166
+				l168:
+					position, tokenIndex, depth = position168, tokenIndex168, depth168
 				}
 				depth--
-				add(ruleEOF, position114)
+				add(ruleEOF, position167)
 			}
 			return true
-		l113:
-			position, tokenIndex, depth = position113, tokenIndex113, depth113
+		l166:
+			position, tokenIndex, depth = position166, tokenIndex166, depth166
 			return false
 		},
-		/* 16 EOL <- <('\r' / '\n')> */
+		/* 21 EOL <- <('\r' / '\n')> */
 		func() bool {
-			position116, tokenIndex116, depth116 := position, tokenIndex, depth
+			position169, tokenIndex169, depth169 := position, tokenIndex, depth
 			{
-				position117 := position
+				position170 := position
 				depth++
 				{
-					position118, tokenIndex118, depth118 := position, tokenIndex, depth
+					position171, tokenIndex171, depth171 := position, tokenIndex, depth
 					if buffer[position] != rune('\r') {
-						goto l119
+						goto l172
 					}
 					position++
-					goto l118
-				l119:
-					position, tokenIndex, depth = position118, tokenIndex118, depth118
+					goto l171
+				l172:
+					position, tokenIndex, depth = position171, tokenIndex171, depth171
 					if buffer[position] != rune('\n') {
-						goto l116
+						goto l169
 					}
 					position++
 				}
-			l118:
+			l171:
 				depth--
-				add(ruleEOL, position117)
+				add(ruleEOL, position170)
 			}
 			return true
-		l116:
-			position, tokenIndex, depth = position116, tokenIndex116, depth116
+		l169:
+			position, tokenIndex, depth = position169, tokenIndex169, depth169
 			return false
 		},
-		/* 17 s <- <(' ' / '\t')*> */
+		/* 22 s <- <(' ' / '\t')*> */
 		func() bool {
 			{
-				position121 := position
+				position174 := position
 				depth++
-			l122:
+			l175:
 				{
-					position123, tokenIndex123, depth123 := position, tokenIndex, depth
+					position176, tokenIndex176, depth176 := position, tokenIndex, depth
 					{
-						position124, tokenIndex124, depth124 := position, tokenIndex, depth
+						position177, tokenIndex177, depth177 := position, tokenIndex, depth
 						if buffer[position] != rune(' ') {
-							goto l125
+							goto l178
 						}
 						position++
-						goto l124
-					l125:
-						position, tokenIndex, depth = position124, tokenIndex124, depth124
+						goto l177
+					l178:
+						position, tokenIndex, depth = position177, tokenIndex177, depth177
 						if buffer[position] != rune('\t') {
-							goto l123
+							goto l176
 						}
 						position++
 					}
-				l124:
-					goto l122
-				l123:
-					position, tokenIndex, depth = position123, tokenIndex123, depth123
+				l177:
+					goto l175
+				l176:
+					position, tokenIndex, depth = position176, tokenIndex176, depth176
 				}
 				depth--
-				add(rules, position121)
+				add(rules, position174)
 			}
 			return true
 		},
 		nil,
-		/* 20 Action0 <- <{ p.name = buffer[begin:end] }> */
+		/* 25 Action0 <- <{ p.name = buffer[begin:end] }> */
 		func() bool {
 			{
 				add(ruleAction0, position)
 			}
 			return true
 		},
-		/* 21 Action1 <- <{ p.set(p.name, p.value) }> */
+		/* 26 Action1 <- <{ p.set(p.name, p.value) }> */
 		func() bool {
 			{
 				add(ruleAction1, position)
 			}
 			return true
 		},
-		/* 22 Action2 <- <{ p.inField = false }> */
+		/* 27 Action2 <- <{ p.addInclude(p.value) }> */
 		func() bool {
 			{
 				add(ruleAction2, position)
 			}
 			return true
 		},
-		/* 23 Action3 <- <{ p.inField = true; p.newField(buffer[begin:end]) }> */
+		/* 28 Action3 <- <{ p.inField = false }> */
 		func() bool {
 			{
 				add(ruleAction3, position)
 			}
 			return true
 		},
-		/* 24 Action4 <- <{ p.value = buffer[begin:end] }> */
+		/* 29 Action4 <- <{ p.inField = true; p.newField(buffer[begin:end]) }> */
 		func() bool {
 			{
 				add(ruleAction4, position)
 			}
 			return true
 		},
-		/* 25 Action5 <- <{ p.value = buffer[begin:end] }> */
+		/* 30 Action5 <- <{ p.inField = false }> */
 		func() bool {
 			{
 				add(ruleAction5, position)
 			}
 			return true
 		},
-		/* 26 Action6 <- <{ p.value = buffer[begin:end] }> */
+		/* 31 Action6 <- <{ p.inField = true; p.newFile(p.value) }> */
 		func() bool {
 			{
 				add(ruleAction6, position)
 			}
 			return true
 		},
-		/* 27 Action7 <- <{ p.value = buffer[begin:end] }> */
+		/* 32 Action7 <- <{ p.inField = false }> */
 		func() bool {
 			{
 				add(ruleAction7, position)
 			}
 			return true
 		},
+		/* 33 Action8 <- <{ p.inField = true; p.newOverride(p.value) }> */
+		func() bool {
+			{
+				add(ruleAction8, position)
+			}
+			return true
+		},
+		/* 34 Action9 <- <{ p.value = buffer[begin:end] }> */
+		func() bool {
+			{
+				add(ruleAction9, position)
+			}
+			return true
+		},
+		/* 35 Action10 <- <{ p.value = buffer[begin:end] }> */
+		func() bool {
+			{
+				add(ruleAction10, position)
+			}
+			return true
+		},
+		/* 36 Action11 <- <{ p.value = buffer[begin:end] }> */
+		func() bool {
+			{
+				add(ruleAction11, position)
+			}
+			return true
+		},
+		/* 37 Action12 <- <{ p.value = buffer[begin:end] }> */
+		func() bool {
+			{
+				add(ruleAction12, position)
+			}
+			return true
+		},
 	}
 	p.rules = _rules
 }
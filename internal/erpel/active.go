@@ -0,0 +1,226 @@
+package erpel
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ActiveSchedule restricts rules to certain days of the week and times of the
+// day, like "Mon-Fri 02:00-04:00". Several periods can be separated by ";".
+type ActiveSchedule struct {
+	text    string
+	periods []activePeriod
+}
+
+func (a *ActiveSchedule) String() string {
+	return a.text
+}
+
+// activePeriod is a time range on some days of the week, from and to are the
+// minutes since midnight. If to is before from, the period extends into the
+// next day.
+type activePeriod struct {
+	days     [7]bool
+	from, to int
+}
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday,
+	"mon": time.Monday,
+	"tue": time.Tuesday,
+	"wed": time.Wednesday,
+	"thu": time.Thursday,
+	"fri": time.Friday,
+	"sat": time.Saturday,
+}
+
+// parseWeekday parses the abbreviated name of a day of the week.
+func parseWeekday(s string) (time.Weekday, error) {
+	d, ok := weekdays[strings.ToLower(s)]
+	if !ok {
+		return 0, errors.Errorf("invalid day of the week %q", s)
+	}
+
+	return d, nil
+}
+
+// parseDays parses a list of days and ranges of days like "Mon-Fri,Sun".
+func parseDays(s string) (days [7]bool, err error) {
+	for _, item := range strings.Split(s, ",") {
+		r := strings.SplitN(item, "-", 2)
+
+		from, err := parseWeekday(r[0])
+		if err != nil {
+			return days, err
+		}
+
+		to := from
+		if len(r) == 2 {
+			if to, err = parseWeekday(r[1]); err != nil {
+				return days, err
+			}
+		}
+
+		// ranges like Fri-Mon extend over the end of the week
+		for d := from; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == to {
+				break
+			}
+		}
+	}
+
+	return days, nil
+}
+
+// parseClock parses a time of the day like "02:30" and returns the minutes
+// since midnight, "24:00" is accepted as the end of the day.
+func parseClock(s string) (int, error) {
+	data := strings.SplitN(s, ":", 2)
+	if len(data) != 2 || len(data[1]) != 2 {
+		return 0, errors.Errorf("invalid time %q, format is HH:MM", s)
+	}
+
+	h, err1 := strconv.Atoi(data[0])
+	m, err2 := strconv.Atoi(data[1])
+	if err1 != nil || err2 != nil || h < 0 || m < 0 || m > 59 || h*60+m > 24*60 {
+		return 0, errors.Errorf("invalid time %q", s)
+	}
+
+	return h*60 + m, nil
+}
+
+// parsePeriod parses a period like "Mon-Fri 02:00-04:00", either the days or
+// the time range may be omitted.
+func parsePeriod(s string) (activePeriod, error) {
+	p := activePeriod{from: 0, to: 24 * 60}
+	for i := range p.days {
+		p.days[i] = true
+	}
+
+	fields := strings.Fields(s)
+	if len(fields) == 0 || len(fields) > 2 {
+		return p, errors.Errorf("invalid period %q, format is 'Mon-Fri 02:00-04:00'", s)
+	}
+
+	if !strings.Contains(fields[0], ":") {
+		days, err := parseDays(fields[0])
+		if err != nil {
+			return p, err
+		}
+		p.days = days
+		fields = fields[1:]
+	}
+
+	if len(fields) == 0 {
+		return p, nil
+	}
+
+	r := strings.SplitN(fields[0], "-", 2)
+	if len(r) != 2 {
+		return p, errors.Errorf("invalid time range %q, format is 02:00-04:00", fields[0])
+	}
+
+	var err error
+	if p.from, err = parseClock(r[0]); err != nil {
+		return p, err
+	}
+
+	if p.to, err = parseClock(r[1]); err != nil {
+		return p, err
+	}
+
+	if p.from == p.to {
+		return p, errors.Errorf("empty time range %q", fields[0])
+	}
+
+	return p, nil
+}
+
+// parseActive parses a schedule like "Sat,Sun; Mon-Fri 02:00-04:00".
+func parseActive(s string) (*ActiveSchedule, error) {
+	a := &ActiveSchedule{text: s}
+	for _, item := range strings.Split(s, ";") {
+		p, err := parsePeriod(item)
+		if err != nil {
+			return nil, err
+		}
+
+		a.periods = append(a.periods, p)
+	}
+
+	return a, nil
+}
+
+// Active returns true if t is within one of the periods of the schedule.
+func (a *ActiveSchedule) Active(t time.Time) bool {
+	day := t.Weekday()
+	prev := (day + 6) % 7
+	m := t.Hour()*60 + t.Minute()
+
+	for _, p := range a.periods {
+		if p.from < p.to {
+			if p.days[day] && m >= p.from && m < p.to {
+				return true
+			}
+			continue
+		}
+
+		// the period starts on one day and ends on the next
+		if (p.days[day] && m >= p.from) || (p.days[prev] && m < p.to) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTimestamp parses the value of a field with a time format. Timestamps
+// without a year (like in syslog) are assumed to be within the last year
+// relative to now.
+func parseTimestamp(layout, s string, now time.Time) (time.Time, bool) {
+	t, err := time.ParseInLocation(layout, s, now.Location())
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	if t.Year() == 0 {
+		t = t.AddDate(now.Year(), 0, 0)
+		if t.After(now.Add(24 * time.Hour)) {
+			t = t.AddDate(-1, 0, 0)
+		}
+	}
+
+	return t, true
+}
+
+// timestamp returns the time of the message from the first field with a time
+// format within the match, or now if there is none.
+func (ct compiledTemplate) timestamp(values Values, now time.Time) time.Time {
+	for _, f := range ct.fields() {
+		if f.TimeFormat == "" {
+			continue
+		}
+
+		for _, v := range values[f.Name] {
+			if t, ok := parseTimestamp(f.TimeFormat, v, now); ok {
+				return t
+			}
+		}
+	}
+
+	return now
+}
+
+// active returns the schedule for the template, the one from the rules is
+// used if the template has none.
+func (r *Rules) active(t Template) *ActiveSchedule {
+	if t.Active != nil {
+		return t.Active
+	}
+
+	return r.Active
+}
@@ -0,0 +1,162 @@
+package erpel
+
+import (
+	"regexp"
+	"testing"
+	"time"
+)
+
+var activeTests = []struct {
+	schedule string
+	times    map[string]bool
+}{
+	{"Mon-Fri 02:00-04:00", map[string]bool{
+		"2026-10-19 02:00": true, // Monday
+		"2026-10-19 03:59": true,
+		"2026-10-19 04:00": false,
+		"2026-10-19 01:59": false,
+		"2026-10-18 03:00": false, // Sunday
+		"2026-10-23 03:00": true,  // Friday
+	}},
+	{"Sun", map[string]bool{
+		"2026-10-18 00:00": true,
+		"2026-10-18 23:59": true,
+		"2026-10-19 00:00": false,
+	}},
+	{"22:00-06:00", map[string]bool{
+		"2026-10-19 22:00": true,
+		"2026-10-19 05:59": true,
+		"2026-10-19 06:00": false,
+		"2026-10-19 12:00": false,
+	}},
+	{"Fri 22:00-02:00", map[string]bool{
+		"2026-10-23 23:00": true,  // Friday
+		"2026-10-24 01:00": true,  // Saturday morning
+		"2026-10-23 01:00": false, // Friday morning
+	}},
+	{"Sat,Sun; Fri-Mon 12:00-24:00", map[string]bool{
+		"2026-10-24 08:00": true,
+		"2026-10-19 13:00": true,
+		"2026-10-19 08:00": false,
+		"2026-10-21 13:00": false,
+	}},
+}
+
+func TestActiveSchedule(t *testing.T) {
+	for i, test := range activeTests {
+		a, err := parseActive(test.schedule)
+		if err != nil {
+			t.Errorf("test %d: parse %q failed: %v", i, test.schedule, err)
+			continue
+		}
+
+		for s, want := range test.times {
+			if a.Active(parseTime(t, s)) != want {
+				t.Errorf("test %d: %v: wrong result for %v, want %v", i, test.schedule, s, want)
+			}
+		}
+	}
+}
+
+var invalidActive = []string{
+	"",
+	"Monday",
+	"Mon-Funday",
+	"02:00",
+	"2:00-3",
+	"02:00-25:00",
+	"02:60-03:00",
+	"02:00-02:00",
+	"Mon 02:00-04:00 extra",
+	"Mon;",
+}
+
+func TestParseActiveInvalid(t *testing.T) {
+	for i, s := range invalidActive {
+		if _, err := parseActive(s); err == nil {
+			t.Errorf("test %d: expected error for %q not found", i, s)
+		}
+	}
+}
+
+func TestParseTimestamp(t *testing.T) {
+	now := parseTime(t, "2026-01-02 10:00")
+
+	var tests = []struct {
+		layout, s string
+		want      string
+	}{
+		{"Jan _2 15:04:05", "Jan  2 03:00:00", "2026-01-02 03:00"},
+		// timestamps without a year are never in the future
+		{"Jan _2 15:04:05", "Dec 31 23:00:00", "2025-12-31 23:00"},
+		{"2006-01-02T15:04:05", "2024-05-06T07:08:09", "2024-05-06 07:08"},
+	}
+
+	for i, test := range tests {
+		ts, ok := parseTimestamp(test.layout, test.s, now)
+		if !ok {
+			t.Errorf("test %d: parsing %q failed", i, test.s)
+			continue
+		}
+
+		if ts.Truncate(time.Minute) != parseTime(t, test.want) {
+			t.Errorf("test %d: want %v, got %v", i, test.want, ts)
+		}
+	}
+}
+
+func TestRulesMatchAt(t *testing.T) {
+	global := map[string]Field{
+		"timestamp": {
+			Name:       "timestamp",
+			Template:   "Jan  1 11:22:33",
+			Pattern:    regexp.MustCompile(`\w{3}  ?\d{1,2} \d{2}:\d{2}:\d{2}`),
+			TimeFormat: "Jan _2 15:04:05",
+		},
+	}
+
+	rules, err := ParseRules(global, "prefix = 'Jan  1 11:22:33 '\nactive = 'Sun'\n---\n"+
+		"@active = '02:00-04:00'\nbackup started\nscrub started\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Monday
+	now := parseTime(t, "2026-10-19 12:00")
+
+	var tests = []struct {
+		line string
+		ok   bool
+	}{
+		{"Oct 19 03:00:00 backup started", true},
+		{"Oct 19 05:00:00 backup started", false},
+		{"Oct 18 03:00:00 scrub started", true},
+		{"Oct 19 03:00:00 scrub started", false},
+	}
+
+	for i, test := range tests {
+		_, _, ok := rules.MatchAt(test.line, now)
+		if ok != test.ok {
+			t.Errorf("test %d: %q: want %v, got %v", i, test.line, test.ok, ok)
+		}
+
+		// Match does not depend on the time
+		if !rules.Match(test.line) {
+			t.Errorf("test %d: %q is not matched regardless of the time", i, test.line)
+		}
+	}
+}
+
+func TestRulesMatchAtProcessingTime(t *testing.T) {
+	rules, err := ParseRules(nil, "---\n@active = 'Mon 02:00-04:00'\nbackup started\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// without a timestamp field, the processing time is used
+	for s, want := range map[string]bool{"2026-10-19 03:00": true, "2026-10-19 12:00": false} {
+		if _, _, ok := rules.MatchAt("backup started", parseTime(t, s)); ok != want {
+			t.Errorf("at %v: want %v, got %v", s, want, ok)
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package erpel
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// charset decodes text in a single-byte character set to UTF-8.
+type charset struct {
+	// high maps the bytes 0x80 to 0xff to runes, lower bytes are ASCII.
+	high [128]rune
+}
+
+// decode returns buf converted to UTF-8.
+func (c *charset) decode(buf []byte) string {
+	var sb strings.Builder
+	sb.Grow(len(buf))
+
+	for _, b := range buf {
+		if b < utf8.RuneSelf {
+			sb.WriteByte(b)
+			continue
+		}
+
+		sb.WriteRune(c.high[b-utf8.RuneSelf])
+	}
+
+	return sb.String()
+}
+
+// newCharset returns a charset based on ISO-8859-1 with the exceptions from
+// the map applied.
+func newCharset(exceptions map[byte]rune) *charset {
+	var c charset
+	for i := range c.high {
+		c.high[i] = rune(i + utf8.RuneSelf)
+	}
+
+	for b, r := range exceptions {
+		c.high[b-utf8.RuneSelf] = r
+	}
+
+	return &c
+}
+
+var (
+	latin1 = newCharset(nil)
+
+	latin9 = newCharset(map[byte]rune{
+		0xa4: '€', 0xa6: 'Š', 0xa8: 'š', 0xb4: 'Ž',
+		0xb8: 'ž', 0xbc: 'Œ', 0xbd: 'œ', 0xbe: 'Ÿ',
+	})
+
+	// undefined bytes are mapped to the C1 control characters
+	windows1252 = newCharset(map[byte]rune{
+		0x80: '€', 0x82: '‚', 0x83: 'ƒ', 0x84: '„', 0x85: '…', 0x86: '†',
+		0x87: '‡', 0x88: 'ˆ', 0x89: '‰', 0x8a: 'Š', 0x8b: '‹', 0x8c: 'Œ',
+		0x8e: 'Ž', 0x91: '‘', 0x92: '’', 0x93: '“', 0x94: '”', 0x95: '•',
+		0x96: '–', 0x97: '—', 0x98: '˜', 0x99: '™', 0x9a: 'š', 0x9b: '›',
+		0x9c: 'œ', 0x9e: 'ž', 0x9f: 'Ÿ',
+	})
+)
+
+var charsets = map[string]*charset{
+	"utf8":        nil,
+	"latin1":      latin1,
+	"iso88591":    latin1,
+	"latin9":      latin9,
+	"iso885915":   latin9,
+	"windows1252": windows1252,
+	"cp1252":      windows1252,
+}
+
+// lookupCharset returns the charset for name. For UTF-8 (and the empty
+// string), nil is returned, the input does not need to be decoded.
+func lookupCharset(name string) (*charset, error) {
+	if name == "" {
+		return nil, nil
+	}
+
+	norm := strings.ToLower(name)
+	norm = strings.Replace(norm, "-", "", -1)
+	norm = strings.Replace(norm, "_", "", -1)
+
+	c, ok := charsets[norm]
+	if !ok {
+		return nil, fmt.Errorf("unknown charset %q", name)
+	}
+
+	return c, nil
+}
@@ -0,0 +1,42 @@
+package erpel
+
+import "testing"
+
+var charsetTests = []struct {
+	charset string
+	data    []byte
+	result  string
+}{
+	{"utf-8", []byte("foo bar"), "foo bar"},
+	{"latin1", []byte("foo bar"), "foo bar"},
+	{"latin1", []byte("gr\xfc\xdfe"), "grüße"},
+	{"ISO-8859-1", []byte("\xa4"), "¤"},
+	{"iso-8859-15", []byte("\xa4 \xbd"), "€ œ"},
+	{"windows-1252", []byte("\x93x\x94 \x80 \x81"), "“x” € \u0081"},
+}
+
+func TestCharset(t *testing.T) {
+	for i, test := range charsetTests {
+		c, err := lookupCharset(test.charset)
+		if err != nil {
+			t.Errorf("test %d: %v", i, err)
+			continue
+		}
+
+		res := string(test.data)
+		if c != nil {
+			res = c.decode(test.data)
+		}
+
+		if res != test.result {
+			t.Errorf("test %d: wrong result, want %q, got %q", i, test.result, res)
+		}
+	}
+}
+
+func TestCharsetUnknown(t *testing.T) {
+	_, err := lookupCharset("ebcdic")
+	if err == nil {
+		t.Fatal("no error returned for unknown charset")
+	}
+}
@@ -3,8 +3,10 @@ package erpel
 import (
 	"fmt"
 	"io/ioutil"
+	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/fd0/erpel/internal/config"
@@ -16,11 +18,97 @@ import (
 type Config struct {
 	Options map[string]string
 	Fields  map[string]Field
+
+	// Lists holds the options set to a list of strings.
+	Lists map[string][]string
+
+	// Files holds options for log files, indexed by file name or glob
+	// pattern.
+	Files map[string]FileOptions
+
+	// Overrides change rules files, indexed by the name of the rules file.
+	// They are defined in the config file or in files within the
+	// override_dir.
+	Overrides map[string]Override
+
+	// Sources records the config files the values were defined in.
+	Sources ConfigSources
+
+	// state is the merged state of all config files, it is used to print
+	// the configuration.
+	state config.State
+}
+
+// FileOptions configure how a log file is read.
+type FileOptions struct {
+	Charset string
+}
+
+// FileOptions returns the options for the log file, relative file names are
+// also matched as absolute paths. An exact match of the file name is
+// preferred, otherwise the first glob pattern (in lexical order) that matches
+// is used.
+func (c Config) FileOptions(filename string) FileOptions {
+	names := []string{filename}
+	if abs, err := filepath.Abs(filename); err == nil && abs != filename {
+		names = append(names, abs)
+	}
+
+	for _, name := range names {
+		if opts, ok := c.Files[name]; ok {
+			return opts
+		}
+	}
+
+	var patterns []string
+	for pattern := range c.Files {
+		patterns = append(patterns, pattern)
+	}
+	sort.Strings(patterns)
+
+	for _, pattern := range patterns {
+		for _, name := range names {
+			if ok, _ := filepath.Match(pattern, name); ok {
+				return c.Files[pattern]
+			}
+		}
+	}
+
+	return FileOptions{}
+}
+
+// parseFileOptions returns the file options from the data.
+func parseFileOptions(data map[string]string) (opts FileOptions, err error) {
+	for key, value := range data {
+		v, err := unquoteString(value)
+		if err != nil {
+			return opts, errors.WithMessage(err, value)
+		}
+
+		switch key {
+		case "charset":
+			if _, err := lookupCharset(v); err != nil {
+				return opts, err
+			}
+			opts.Charset = v
+		default:
+			return opts, errors.Errorf("unknown key %q", key)
+		}
+	}
+
+	return opts, nil
 }
 
 var validOptions = map[string]struct{}{
-	"rules_dir": struct{}{},
-	"state_dir": struct{}{},
+	"rules_dir":       struct{}{},
+	"state_dir":       struct{}{},
+	"expiry_warning":  struct{}{},
+	"override_dir":    struct{}{},
+	"rules_recursive": struct{}{},
+	"rules_include":   struct{}{},
+	"rules_exclude":   struct{}{},
+	"tags":            struct{}{},
+	"exclude_tags":    struct{}{},
 }
 
 // fieldForName returns the field matching the name, either directly (via
@@ -112,11 +200,41 @@ func compileRegexp(data map[string]string) (map[string]*regexp.Regexp, error) {
 	return m, nil
 }
 
+// parseOptionValue returns the value of an option, list is not nil if the
+// value is a list of strings. Environment variables like ${HOME} are expanded.
+func parseOptionValue(value string) (s string, list []string, err error) {
+	if !strings.HasPrefix(value, "[") {
+		s, err = unquoteString(value)
+		if err != nil {
+			return "", nil, errors.WithMessage(err, value)
+		}
+
+		s, err = expandEnv(s)
+		return s, nil, err
+	}
+
+	list, err = unquoteList(value)
+	if err != nil {
+		return "", nil, errors.WithMessage(err, value)
+	}
+
+	for i := range list {
+		if list[i], err = expandEnv(list[i]); err != nil {
+			return "", nil, err
+		}
+	}
+
+	return "", list, nil
+}
+
 // parseState returns a Config struct from a state.
 func parseState(state config.State) (c Config, err error) {
 	cfg := Config{
-		Options: make(map[string]string),
-		Fields:  make(map[string]Field),
+		Options:   make(map[string]string),
+		Lists:     make(map[string][]string),
+		Fields:    make(map[string]Field),
+		Files:     make(map[string]FileOptions),
+		Overrides: make(map[string]Override),
 	}
 
 	for name, value := range state.Fields {
@@ -127,28 +245,76 @@ func parseState(state config.State) (c Config, err error) {
 		cfg.Fields[name] = f
 	}
 
+	if err = resolveFields(cfg.Fields, nil); err != nil {
+		return c, err
+	}
+
+	for name, data := range state.Files {
+		filename, err := unquoteString(name)
+		if err != nil {
+			return c, errors.WithMessage(err, name)
+		}
+
+		if _, err = filepath.Match(filename, ""); err != nil {
+			return c, errors.Errorf("invalid file name pattern %q: %v", filename, err)
+		}
+
+		opts, err := parseFileOptions(data)
+		if err != nil {
+			return c, errors.Errorf("file %v: %v", filename, err)
+		}
+
+		cfg.Files[filename] = opts
+	}
+
+	for name, data := range state.Overrides {
+		rulesName, err := unquoteString(name)
+		if err != nil {
+			return c, errors.WithMessage(err, name)
+		}
+
+		o, err := parseOverride(data)
+		if err != nil {
+			return c, errors.Errorf("override %v: %v", rulesName, err)
+		}
+
+		cfg.Overrides[rulesName] = o
+	}
+
 	for name, value := range state.Global {
 		if _, ok := validOptions[name]; !ok {
 			return c, errors.WithStack(fmt.Errorf("unknown configuration option %q", name))
 		}
 
-		s, err := unquoteString(value)
+		s, list, err := parseOptionValue(value)
 		if err != nil {
-			return c, errors.WithMessage(err, value)
+			return c, errors.Errorf("option %v: %v", name, err)
+		}
+
+		if list != nil {
+			cfg.Lists[name] = list
+			continue
 		}
 		cfg.Options[name] = s
 	}
 
+	cfg.state = state
+
 	return cfg, nil
 }
 
-// ParseConfig parses data as an erpel config file.
+// ParseConfig parses data as an erpel config file. Includes are only
+// supported for config files, see ParseConfigFile.
 func ParseConfig(data string) (Config, error) {
 	state, err := config.Parse(data)
 	if err != nil {
 		return Config{}, errors.WithStack(err)
 	}
 
+	if len(state.Includes) > 0 {
+		return Config{}, errors.New("include is only supported in config files")
+	}
+
 	cfg, err := parseState(state)
 	if err != nil {
 		return Config{}, errors.WithStack(err)
@@ -157,12 +323,71 @@ func ParseConfig(data string) (Config, error) {
 	return cfg, nil
 }
 
-// ParseConfigFile loads a config from a file.
+// ParseConfigFile loads a config from a file together with the included
+// files, see configLoader for how they are merged. Overrides from the files
+// in the override_dir are loaded as well.
 func ParseConfigFile(filename string) (Config, error) {
-	buf, err := ioutil.ReadFile(filename)
+	l := newConfigLoader()
+	if err := l.load(filename, nil); err != nil {
+		return Config{}, err
+	}
+
+	cfg, err := parseState(l.state)
 	if err != nil {
 		return Config{}, err
 	}
 
-	return ParseConfig(string(buf))
+	cfg.Sources = l.sources
+	for name, o := range cfg.Overrides {
+		o.Source = l.overrides[name]
+		cfg.Overrides[name] = o
+	}
+
+	if dir, ok := cfg.Options["override_dir"]; ok {
+		if err = cfg.loadOverrides(dir); err != nil {
+			return Config{}, err
+		}
+	}
+
+	return cfg, nil
+}
+
+// loadOverrides adds the overrides from all files in dir. The files must only
+// contain override sections, a rules file may only be overridden once.
+func (c *Config) loadOverrides(dir string) error {
+	files, err := RulesFiles(dir)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		buf, err := ioutil.ReadFile(file)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		other, err := ParseConfig(string(buf))
+		if err != nil {
+			return errors.WithMessage(err, file)
+		}
+
+		if len(other.Options) > 0 || len(other.Lists) > 0 || len(other.Fields) > 0 || len(other.Files) > 0 {
+			return errors.Errorf("%v: only override sections are allowed in the override_dir", file)
+		}
+
+		for name, o := range other.Overrides {
+			if prev, ok := c.Overrides[name]; ok {
+				return errors.Errorf("%v: override for %v already defined in %v", file, name, prev.Source)
+			}
+
+			o.Source = file
+			c.Overrides[name] = o
+		}
+
+		for name, data := range other.state.Overrides {
+			c.state.Overrides[normalizeName(name)] = data
+		}
+	}
+
+	return nil
 }
@@ -0,0 +1,124 @@
+package erpel
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/fd0/erpel/internal/rules"
+)
+
+// sortedKeys returns the keys of m in lexical order.
+func sortedKeys(m map[string]rules.Field) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// source returns a comment naming the file, if known.
+func source(filename string) string {
+	if filename == "" {
+		return ""
+	}
+
+	return "  # " + filename
+}
+
+// quoteList returns list in the syntax of the config file.
+func quoteList(list []string) string {
+	items := make([]string, 0, len(list))
+	for _, s := range list {
+		items = append(items, strconv.Quote(s))
+	}
+
+	return "[" + strings.Join(items, ", ") + "]"
+}
+
+// dumpSection writes a field, file or override section.
+func dumpSection(wr io.Writer, header, src string, data rules.Field) error {
+	if _, err := fmt.Fprintf(wr, "\n%v {%v\n", header, source(src)); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(data))
+	for key := range data {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, err := fmt.Fprintf(wr, "    %v = %v\n", key, data[key]); err != nil {
+			return err
+		}
+	}
+
+	_, err := fmt.Fprintf(wr, "}\n")
+	return err
+}
+
+// Dump writes the effective configuration in the syntax of the config file,
+// each option and section is followed by a comment naming the file it was
+// defined in. Option values are written with environment variables expanded.
+func (c Config) Dump(wr io.Writer) error {
+	for _, file := range c.Sources.ConfigFiles {
+		if _, err := fmt.Fprintf(wr, "# loaded %v\n", file); err != nil {
+			return err
+		}
+	}
+
+	values := make(map[string]string, len(c.Options)+len(c.Lists))
+	for name, value := range c.Options {
+		values[name] = strconv.Quote(value)
+	}
+	for name, list := range c.Lists {
+		values[name] = quoteList(list)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) > 0 {
+		fmt.Fprintln(wr)
+	}
+
+	for _, name := range names {
+		_, err := fmt.Fprintf(wr, "%v = %v%v\n", name, values[name], source(c.Sources.Options[name]))
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(c.state.Fields) {
+		err := dumpSection(wr, "field "+name, c.Sources.Fields[name], c.state.Fields[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(c.state.Files) {
+		s, _ := strconv.Unquote(name)
+		err := dumpSection(wr, "file "+name, c.Sources.Files[s], c.state.Files[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, name := range sortedKeys(c.state.Overrides) {
+		s, _ := strconv.Unquote(name)
+		err := dumpSection(wr, "override "+name, c.Overrides[s].Source, c.state.Overrides[name])
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
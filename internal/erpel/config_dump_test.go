@@ -0,0 +1,44 @@
+package erpel
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+var testConfigDump = []string{
+	`state_dir = '/var/lib/erpel'`,
+	`rules_dir = ['/etc/erpel/rules.d']`,
+	`rules_dir = ["/srv/rules,local.d", '/srv/it\'s here', "/srv/\"quoted\""]`,
+	"rules_include = [`*.rules`, '*.conf']\nexclude_tags = ['noisy', 'debug']\nrules_recursive = 'true'",
+}
+
+func TestConfigDump(t *testing.T) {
+	for i, data := range testConfigDump {
+		cfg, err := ParseConfig(data)
+		if err != nil {
+			t.Errorf("test %v: parse failed: %v", i, err)
+			continue
+		}
+
+		buf := bytes.NewBuffer(nil)
+		if err = cfg.Dump(buf); err != nil {
+			t.Errorf("test %v: dump failed: %v", i, err)
+			continue
+		}
+
+		dumped, err := ParseConfig(buf.String())
+		if err != nil {
+			t.Errorf("test %v: parsing the dump failed: %v\n%s", i, err, buf.String())
+			continue
+		}
+
+		if !reflect.DeepEqual(cfg.Options, dumped.Options) {
+			t.Errorf("test %v: options differ, want %v, got %v", i, cfg.Options, dumped.Options)
+		}
+
+		if !reflect.DeepEqual(cfg.Lists, dumped.Lists) {
+			t.Errorf("test %v: lists differ, want %q, got %q", i, cfg.Lists, dumped.Lists)
+		}
+	}
+}
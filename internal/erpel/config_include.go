@@ -0,0 +1,211 @@
+package erpel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/fd0/erpel/internal/config"
+	"github.com/fd0/erpel/internal/rules"
+	"github.com/pkg/errors"
+)
+
+// ConfigSources records the config files the values were defined in.
+type ConfigSources struct {
+	// ConfigFiles lists all config files in the order they were loaded.
+	ConfigFiles []string
+
+	// Options, Fields and Files map the names of the options, fields and
+	// file sections to the config file they were last defined in.
+	Options map[string]string
+	Fields  map[string]string
+	Files   map[string]string
+}
+
+// envVariable matches references to environment variables like ${HOME}.
+var envVariable = regexp.MustCompile(`\$\{([a-zA-Z_][a-zA-Z0-9_]*)\}`)
+
+// expandEnv replaces references to environment variables like ${HOME} in s
+// with their values. Variables which are not set are an error.
+func expandEnv(s string) (string, error) {
+	var err error
+	s = envVariable.ReplaceAllStringFunc(s, func(ref string) string {
+		name := envVariable.FindStringSubmatch(ref)[1]
+		value, ok := os.LookupEnv(name)
+		if !ok && err == nil {
+			err = errors.Errorf("environment variable %v is not set", name)
+		}
+		return value
+	})
+
+	return s, err
+}
+
+// normalizeName returns the quoted name of a file or override section in
+// double quotes, so that sections from different files with the same name
+// are merged.
+func normalizeName(name string) string {
+	s, err := unquoteString(name)
+	if err != nil {
+		// the error is reported when the state is parsed
+		return name
+	}
+
+	return strconv.Quote(s)
+}
+
+// configLoader merges config files with their includes. The files are merged
+// in the order they are loaded: first the including file, then the included
+// files in the order of the include statements, the files matched by a glob
+// pattern sorted by name. Options, fields and file sections from later files
+// replace the ones from earlier files (fields and file sections as a whole),
+// a rules file may only be overridden once.
+type configLoader struct {
+	state   config.State
+	sources ConfigSources
+
+	// overrides maps the names of the rules files to the config file the
+	// override is defined in
+	overrides map[string]string
+}
+
+func newConfigLoader() *configLoader {
+	return &configLoader{
+		state: config.State{
+			Global:    make(map[string]string),
+			Fields:    make(map[string]rules.Field),
+			Files:     make(map[string]rules.Field),
+			Overrides: make(map[string]rules.Field),
+		},
+		sources: ConfigSources{
+			Options: make(map[string]string),
+			Fields:  make(map[string]string),
+			Files:   make(map[string]string),
+		},
+		overrides: make(map[string]string),
+	}
+}
+
+// merge adds the state parsed from the file.
+func (l *configLoader) merge(state config.State, filename string) error {
+	for name, value := range state.Global {
+		if _, ok := validOptions[name]; !ok {
+			return errors.Errorf("unknown configuration option %q", name)
+		}
+
+		if _, _, err := parseOptionValue(value); err != nil {
+			return errors.Errorf("option %v: %v", name, err)
+		}
+
+		l.state.Global[name] = value
+		l.sources.Options[name] = filename
+	}
+
+	for name, f := range state.Fields {
+		l.state.Fields[name] = f
+		l.sources.Fields[name] = filename
+	}
+
+	for name, data := range state.Files {
+		name = normalizeName(name)
+		l.state.Files[name] = data
+		if s, err := strconv.Unquote(name); err == nil {
+			l.sources.Files[s] = filename
+		}
+	}
+
+	for name, data := range state.Overrides {
+		name = normalizeName(name)
+		s, _ := strconv.Unquote(name)
+		// a file included twice is fine
+		if prev, ok := l.overrides[s]; ok && prev != filename {
+			return errors.Errorf("override for %v already defined in %v", s, prev)
+		}
+
+		l.state.Overrides[name] = data
+		l.overrides[s] = filename
+	}
+
+	return nil
+}
+
+// includeFiles returns the files for the include statement in the file
+// filename. Relative names are resolved to the directory of that file.
+func includeFiles(filename, include string) ([]string, error) {
+	pattern, err := unquoteString(include)
+	if err != nil {
+		return nil, errors.WithMessage(err, include)
+	}
+
+	if !filepath.IsAbs(pattern) {
+		pattern = filepath.Join(filepath.Dir(filename), pattern)
+	}
+
+	if !strings.ContainsAny(pattern, "*?[") {
+		return []string{pattern}, nil
+	}
+
+	files, err := filepath.Glob(pattern)
+	if err != nil {
+		return nil, errors.Errorf("invalid pattern %q", pattern)
+	}
+
+	return files, nil
+}
+
+// load merges the config file and the files it includes. The files including
+// each other so far are listed in chain.
+func (l *configLoader) load(filename string, chain []string) error {
+	filename, err := filepath.Abs(filename)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	next := append(chain[:len(chain):len(chain)], filename)
+	for _, f := range chain {
+		if f == filename {
+			return errors.Errorf("include cycle: %v", includeChain(next))
+		}
+	}
+
+	// errors in included files name the include chain
+	wrap := func(err error) error {
+		if len(chain) == 0 {
+			return err
+		}
+		return errors.Errorf("%v: %v", includeChain(next), err)
+	}
+
+	buf, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return wrap(errors.WithStack(err))
+	}
+
+	state, err := config.Parse(string(buf))
+	if err != nil {
+		return wrap(errors.WithStack(err))
+	}
+
+	if err = l.merge(state, filename); err != nil {
+		return wrap(err)
+	}
+	l.sources.ConfigFiles = append(l.sources.ConfigFiles, filename)
+
+	for _, include := range state.Includes {
+		files, err := includeFiles(filename, include)
+		if err != nil {
+			return wrap(err)
+		}
+
+		for _, file := range files {
+			if err = l.load(file, next); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
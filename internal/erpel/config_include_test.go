@@ -0,0 +1,200 @@
+package erpel
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testExpandEnv = []struct {
+	s, result string
+	err       bool
+}{
+	{"/var/lib/erpel", "/var/lib/erpel", false},
+	{"${ERPEL_TEST_DIR}/rules.d", "/srv/erpel/rules.d", false},
+	{"${ERPEL_TEST_DIR}:${ERPEL_TEST_DIR}", "/srv/erpel:/srv/erpel", false},
+	{"$ERPEL_TEST_DIR/rules.d", "$ERPEL_TEST_DIR/rules.d", false},
+	{"${ERPEL_TEST_UNSET}/rules.d", "", true},
+}
+
+func TestExpandEnv(t *testing.T) {
+	os.Setenv("ERPEL_TEST_DIR", "/srv/erpel")
+	defer os.Unsetenv("ERPEL_TEST_DIR")
+
+	for i, test := range testExpandEnv {
+		s, err := expandEnv(test.s)
+		if test.err {
+			if err == nil {
+				t.Errorf("test %d: expected error not found", i)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+
+		if s != test.result {
+			t.Errorf("test %d: want %q, got %q", i, test.result, s)
+		}
+	}
+}
+
+func TestConfigInclude(t *testing.T) {
+	dir, err := ioutil.TempDir("", "erpel-config-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	os.Setenv("ERPEL_TEST_DIR", "/srv/erpel")
+	defer os.Unsetenv("ERPEL_TEST_DIR")
+
+	writeFiles(t, dir, map[string]string{
+		"erpel.conf": "state_dir = '/var/lib/erpel'\nrules_dir = '/etc/erpel/rules.d'\n" +
+			"include = 'conf.d/*.conf'\n" +
+			"field num {\n  pattern = '\\d+'\n}\n" +
+			"file '/var/log/a' {\n  charset = 'latin1'\n}\n",
+		"conf.d/10-dirs.conf": "rules_dir = ['${ERPEL_TEST_DIR}/rules.d', '/etc/erpel/rules,local.d']\n" +
+			"field ports {\n  pattern = '{{num}}(,{{num}})*'\n}\n",
+		"conf.d/20-local.conf": "state_dir = '${ERPEL_TEST_DIR}/state'\n" +
+			"file \"/var/log/a\" {\n  charset = 'utf-8'\n}\n" +
+			"override 'dovecot' {\n  disable = 'true'\n}\n",
+		"conf.d/30-local.conf~": "state_dir = '/tmp'\n",
+	})
+
+	cfg, err := ParseConfigFile(filepath.Join(dir, "erpel.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	equalMap(t, "options", map[string]string{
+		"state_dir": "/srv/erpel/state",
+	}, cfg.Options)
+
+	wantLists := map[string][]string{
+		"rules_dir": {"/srv/erpel/rules.d", "/etc/erpel/rules,local.d"},
+	}
+	if !reflect.DeepEqual(cfg.Lists, wantLists) {
+		t.Errorf("wrong lists, want %v, got %v", wantLists, cfg.Lists)
+	}
+
+	if cfg.Files["/var/log/a"].Charset != "utf-8" {
+		t.Errorf("file section was not replaced: %v", cfg.Files["/var/log/a"])
+	}
+
+	if !cfg.Fields["ports"].Pattern.MatchString("22,80") {
+		t.Errorf("composed field from included file not resolved: %v", cfg.Fields["ports"].Pattern)
+	}
+
+	name := func(s string) string { return filepath.Join(dir, filepath.FromSlash(s)) }
+
+	wantFiles := []string{name("erpel.conf"), name("conf.d/10-dirs.conf"), name("conf.d/20-local.conf")}
+	if !reflect.DeepEqual(cfg.Sources.ConfigFiles, wantFiles) {
+		t.Errorf("wrong config files, want %v, got %v", wantFiles, cfg.Sources.ConfigFiles)
+	}
+
+	equalMap(t, "option sources", map[string]string{
+		"state_dir": name("conf.d/20-local.conf"),
+		"rules_dir": name("conf.d/10-dirs.conf"),
+	}, cfg.Sources.Options)
+
+	if cfg.Overrides["dovecot"].Source != name("conf.d/20-local.conf") {
+		t.Errorf("wrong source for override: %v", cfg.Overrides["dovecot"].Source)
+	}
+
+	// the dump can be parsed again and yields the same config
+	buf := bytes.NewBuffer(nil)
+	if err = cfg.Dump(buf); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(buf.String(), "state_dir = \"/srv/erpel/state\"  # "+name("conf.d/20-local.conf")) {
+		t.Errorf("source of option not found in dump:\n%s", buf.String())
+	}
+
+	dumped, err := ParseConfig(buf.String())
+	if err != nil {
+		t.Fatalf("parsing the dump failed: %v\n%s", err, buf.String())
+	}
+
+	equalMap(t, "dumped options", cfg.Options, dumped.Options)
+	if !reflect.DeepEqual(cfg.Lists, dumped.Lists) {
+		t.Errorf("dumped lists differ, want %v, got %v", cfg.Lists, dumped.Lists)
+	}
+	if len(dumped.Fields) != len(cfg.Fields) || len(dumped.Files) != len(cfg.Files) ||
+		len(dumped.Overrides) != len(cfg.Overrides) {
+		t.Errorf("dump is incomplete:\n%s", buf.String())
+	}
+}
+
+var testInvalidConfigIncludes = []struct {
+	files map[string]string
+	err   string
+}{
+	{
+		files: map[string]string{"erpel.conf": "include = 'missing.conf'\n"},
+		err:   "DIR/erpel.conf -> DIR/missing.conf: open",
+	},
+	{
+		files: map[string]string{
+			"erpel.conf":    "include = 'conf.d/*'\n",
+			"conf.d/a.conf": "include = '../erpel.conf'\n",
+		},
+		err: "include cycle: DIR/erpel.conf -> DIR/conf.d/a.conf -> DIR/erpel.conf",
+	},
+	{
+		files: map[string]string{
+			"erpel.conf":    "include = 'conf.d/*'\n",
+			"conf.d/a.conf": "unknown = 'foo'\n",
+		},
+		err: "DIR/erpel.conf -> DIR/conf.d/a.conf: unknown configuration option",
+	},
+	{
+		files: map[string]string{
+			"erpel.conf":    "include = 'conf.d/*'\n",
+			"conf.d/a.conf": "state_dir = '${ERPEL_TEST_UNSET}'\n",
+		},
+		err: "DIR/erpel.conf -> DIR/conf.d/a.conf: option state_dir: environment variable ERPEL_TEST_UNSET is not set",
+	},
+	{
+		files: map[string]string{
+			"erpel.conf":    "include = 'conf.d/*'\noverride 'dovecot' {\n  disable = 'true'\n}\n",
+			"conf.d/a.conf": "override \"dovecot\" {\n  disable = 'false'\n}\n",
+		},
+		err: "override for dovecot already defined in DIR/erpel.conf",
+	},
+}
+
+func TestConfigIncludeInvalid(t *testing.T) {
+	for i, test := range testInvalidConfigIncludes {
+		dir, err := ioutil.TempDir("", "erpel-config-")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		writeFiles(t, dir, test.files)
+
+		_, err = ParseConfigFile(filepath.Join(dir, "erpel.conf"))
+		os.RemoveAll(dir)
+
+		if err == nil {
+			t.Errorf("test %d: expected error not found", i)
+			continue
+		}
+
+		msg := strings.Replace(test.err, "DIR", dir, -1)
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("test %d: error %q does not contain %q", i, err, msg)
+		}
+	}
+
+	if _, err := ParseConfig("include = 'foo.conf'\n"); err == nil {
+		t.Errorf("include without a config file: expected error not found")
+	}
+}
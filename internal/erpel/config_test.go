@@ -2,7 +2,9 @@ package erpel
 
 import (
 	"io/ioutil"
+	"os"
 	"path/filepath"
+	"reflect"
 	"regexp"
 	"testing"
 
@@ -30,6 +32,20 @@ state_dir = '/foo'
 	},
 	{
 		data: `
+rules_dir = ['/usr/share/erpel/rules.d', "/etc/erpel/rules.d"]
+rules_exclude = ['*~']
+`,
+		cfg: Config{
+			Options: map[string]string{},
+			Lists: map[string][]string{
+				"rules_dir":     {"/usr/share/erpel/rules.d", "/etc/erpel/rules.d"},
+				"rules_exclude": {"*~"},
+			},
+			Fields: map[string]Field{},
+		},
+	},
+	{
+		data: `
 # load ignore rules from all files in this directory
 rules_dir = "/etc/erpel/rules.d"
 
@@ -102,6 +118,9 @@ func TestParseConfig(t *testing.T) {
 		}
 
 		equalMap(t, "config", test.cfg.Options, cfg.Options)
+		if len(test.cfg.Lists) > 0 && !reflect.DeepEqual(test.cfg.Lists, cfg.Lists) {
+			t.Errorf("test %v: wrong lists, want %v, got %v", i, test.cfg.Lists, cfg.Lists)
+		}
 
 		var fields []string
 		for name := range test.cfg.Fields {
@@ -142,3 +161,91 @@ func TestParseSampleConfig(t *testing.T) {
 		t.Fatalf("parsing sample config failed: %v", err)
 	}
 }
+
+func TestConfigFileOptions(t *testing.T) {
+	cfg, err := ParseConfig(`
+file '/var/log/legacy.log' {
+	charset = 'latin1'
+}
+
+file '/var/log/*.log' {
+	charset = "windows-1252"
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		filename string
+		charset  string
+	}{
+		{"/var/log/legacy.log", "latin1"},
+		{"/var/log/other.log", "windows-1252"},
+		{"/var/log/messages", ""},
+	}
+
+	for _, test := range tests {
+		opts := cfg.FileOptions(test.filename)
+		if opts.Charset != test.charset {
+			t.Errorf("wrong charset for %v: want %q, got %q", test.filename, test.charset, opts.Charset)
+		}
+	}
+
+	// relative names are matched as absolute paths
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Files[filepath.Join(wd, "legacy.log")] = FileOptions{Charset: "latin1"}
+
+	if opts := cfg.FileOptions("legacy.log"); opts.Charset != "latin1" {
+		t.Errorf("wrong charset for relative name: want %q, got %q", "latin1", opts.Charset)
+	}
+}
+
+var testInvalidFileOptions = []string{
+	`file '/var/log/foo' { charset = 'ebcdic' }`,
+	`file '/var/log/foo' { foo = 'bar' }`,
+	`file '/var/log/[' { charset = 'latin1' }`,
+}
+
+func TestConfigInvalidFileOptions(t *testing.T) {
+	for i, data := range testInvalidFileOptions {
+		_, err := ParseConfig(data)
+		if err == nil {
+			t.Errorf("test %d: expected error not found", i)
+		}
+	}
+}
+
+func TestConfigComposedFields(t *testing.T) {
+	cfg, err := ParseConfig(`
+field hostname {
+	pattern = '[a-z0-9-]+(\.[a-z0-9-]+)*'
+}
+
+field hostport {
+	pattern = '{{hostname}}:\d+'
+	samples = ['mail.example.com:25']
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := cfg.Fields["hostport"]
+	if f.Expression != `{{hostname}}:\d+` {
+		t.Errorf("wrong expression %q", f.Expression)
+	}
+
+	want := `(?:[a-z0-9-]+(\.[a-z0-9-]+)*):\d+`
+	if f.Pattern.String() != want {
+		t.Errorf("wrong pattern, want %q, got %q", want, f.Pattern)
+	}
+
+	_, err = ParseConfig("field a {\npattern = '{{b}}'\n}\nfield b {\npattern = '{{a}}'\n}")
+	if err == nil {
+		t.Errorf("expected error for cyclic references not found")
+	}
+}
@@ -0,0 +1,286 @@
+package erpel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Window is the time or the number of lines within which a sequence of
+// messages needs to be completed.
+type Window struct {
+	Duration time.Duration
+	Lines    int
+}
+
+func (w Window) String() string {
+	if w.Lines > 0 {
+		return fmt.Sprintf("%d lines", w.Lines)
+	}
+
+	return w.Duration.String()
+}
+
+// parseWindow parses a window like "10m" or "100 lines".
+func parseWindow(s string) (Window, error) {
+	if strings.HasSuffix(s, "lines") {
+		n, err := strconv.Atoi(strings.TrimSpace(strings.TrimSuffix(s, "lines")))
+		if err != nil || n <= 0 {
+			return Window{}, errors.Errorf("invalid number of lines in window %q", s)
+		}
+
+		return Window{Lines: n}, nil
+	}
+
+	d, err := parseDuration(s)
+	if err != nil || d < time.Second {
+		return Window{}, errors.Errorf("invalid window %q", s)
+	}
+
+	return Window{Duration: d}, nil
+}
+
+// pending is a sequence which has been started, but not completed yet.
+type pending struct {
+	// Rules is the name of the rules file. Filename is only set for
+	// sequences saved before template IDs were used for the keys.
+	Rules    string `json:"rules"`
+	Filename string `json:"filename,omitempty"`
+	Sequence string `json:"sequence"`
+	Key      string `json:"key"`
+
+	// Step is the index of the next step expected
+	Step int `json:"step"`
+
+	// Source names the log the sequence was started in. Text is the message
+	// which started the sequence, Started its time and Line the number of
+	// lines processed from the source at that moment.
+	Source  string `json:"source"`
+	Text    string `json:"text"`
+	Started int64  `json:"started"`
+	Line    int64  `json:"line"`
+
+	// number is the line number within the current run, zero for sequences
+	// started in a previous run.
+	number int
+}
+
+// Correlations keep track of the sequences which have been started. They are
+// saved to the state directory, so that sequences can span several runs.
+type Correlations struct {
+	// Lines is the number of lines processed for each source.
+	Lines   map[string]int64    `json:"source_lines"`
+	Pending map[string]*pending `json:"pending"`
+}
+
+// NewCorrelations returns a new, empty Correlations.
+func NewCorrelations() *Correlations {
+	return &Correlations{
+		Lines:   make(map[string]int64),
+		Pending: make(map[string]*pending),
+	}
+}
+
+// LoadCorrelations reads the pending sequences from the file. If the file does
+// not exist, empty Correlations are returned.
+func LoadCorrelations(filename string) (*Correlations, error) {
+	buf, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return NewCorrelations(), nil
+	}
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	c := NewCorrelations()
+	if err = json.Unmarshal(buf, c); err != nil {
+		return nil, errors.WithMessage(err, filename)
+	}
+
+	if c.Lines == nil {
+		c.Lines = make(map[string]int64)
+	}
+
+	if c.Pending == nil {
+		c.Pending = make(map[string]*pending)
+	}
+
+	return c, nil
+}
+
+// Save writes the pending sequences to the file.
+func (c *Correlations) Save(filename string) error {
+	buf, err := json.Marshal(c)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(ioutil.WriteFile(filename, buf, 0600))
+}
+
+// expired returns true if the window for the sequence has passed, lines are
+// counted within the source the sequence was started in.
+func (c *Correlations) expired(p *pending, w Window, now time.Time) bool {
+	if w.Lines > 0 {
+		return c.Lines[p.Source]-p.Line > int64(w.Lines)
+	}
+
+	return now.Unix()-p.Started > int64(w.Duration/time.Second)
+}
+
+// sequenceKey returns the key for a pending sequence, which consists of the
+// ID of the first step and the value of the key field.
+func sequenceKey(r *Rules, name, key string) string {
+	return r.TemplateID(r.first(name)) + "\x00" + key
+}
+
+// migrate renames the pending sequences saved with the keys used before
+// template IDs, which consisted of the filename, the sequence and the key.
+func (c *Correlations) migrate(rules []Rules) {
+	for i := range rules {
+		r := &rules[i]
+		for id, p := range c.Pending {
+			if p.Rules != "" || p.Filename != r.Filename || len(r.sequences[p.Sequence]) == 0 {
+				continue
+			}
+
+			delete(c.Pending, id)
+			p.Rules, p.Filename = r.name(), ""
+			c.Pending[sequenceKey(r, p.Sequence, p.Key)] = p
+		}
+	}
+}
+
+// first returns the first step of the sequence.
+func (r *Rules) first(name string) Template {
+	return r.Templates[r.sequences[name][0]]
+}
+
+// reportSequence sets the action for a line which is reported because a
+// sequence has not been completed.
+func reportSequence(r *Rules, t Template, line *Line, summary string) {
+	line.Action = ActionReport
+	line.Rule = r.Describe(t)
+	line.Category, line.Severity = r.Classify(t)
+	line.Summary = summary
+}
+
+// incomplete returns a line for a sequence which has not been completed.
+func incomplete(r *Rules, p *pending) Line {
+	steps := r.sequences[p.Sequence]
+	next := r.Templates[steps[p.Step]]
+
+	line := Line{Number: p.number, Text: p.Text}
+	reportSequence(r, r.first(p.Sequence), &line,
+		fmt.Sprintf("incomplete sequence %v for %v=%v, missing \"%v\"", p.Sequence, next.Key, p.Key, next.Text))
+	return line
+}
+
+// checkSequence tracks the step of a sequence matched by the line at the time
+// of the message. The line is reported if the step is not expected and it is
+// ignored otherwise, lines for sequences which are replaced or completed too
+// late are returned.
+func (m *matcher) checkSequence(r *Rules, f ruleMatch, line *Line) (reports []Line) {
+	c := m.correlations
+	t, now := f.t, f.ts
+	key := f.values.Get(t.Key)
+	id := sequenceKey(r, t.Sequence, key)
+	p, ok := c.Pending[id]
+	first := r.first(t.Sequence)
+
+	if ok && c.expired(p, *first.Within, now) {
+		reports = append(reports, incomplete(r, p))
+		delete(c.Pending, id)
+		ok = false
+	}
+
+	if t.step == 0 {
+		if ok {
+			reports = append(reports, incomplete(r, p))
+		}
+
+		c.Pending[id] = &pending{
+			Rules:    r.name(),
+			Sequence: t.Sequence,
+			Key:      key,
+			Step:     1,
+			Source:   m.source,
+			Text:     line.Text,
+			Started:  now.Unix(),
+			Line:     c.Lines[m.source],
+			number:   line.Number,
+		}
+		return reports
+	}
+
+	if !ok || p.Step != t.step {
+		if line.Action != ActionIgnore {
+			// the line is passed on anyway
+			return reports
+		}
+
+		reportSequence(r, t, line, fmt.Sprintf("unexpected step of sequence %v for %v=%v", t.Sequence, t.Key, key))
+		return reports
+	}
+
+	p.Step++
+	if p.Step == len(r.sequences[t.Sequence]) {
+		delete(c.Pending, id)
+	}
+
+	return reports
+}
+
+// expireSequences returns lines for all sequences which have not been
+// completed within their window. Sequences which do not exist any more within
+// their rules file are removed, those of rules files which are not loaded or
+// only partially are kept.
+func (m *matcher) expireSequences() (reports []Line) {
+	c := m.correlations
+	now := m.now()
+
+	rules := make(map[string]*Rules, len(m.rules))
+	for i := range m.rules {
+		rules[m.rules[i].name()] = &m.rules[i]
+	}
+
+	for id, p := range c.Pending {
+		r, ok := rules[p.Rules]
+		if !ok {
+			continue
+		}
+
+		if len(r.sequences[p.Sequence]) <= p.Step {
+			if !r.partial {
+				delete(c.Pending, id)
+			}
+			continue
+		}
+
+		if c.expired(p, *r.first(p.Sequence).Within, now) {
+			reports = append(reports, incomplete(r, p))
+			delete(c.Pending, id)
+		}
+	}
+
+	sortLines(reports)
+	return reports
+}
+
+// sortLines sorts the lines by number and text.
+func sortLines(lines []Line) {
+	sort.Slice(lines, func(i, j int) bool {
+		if lines[i].Number != lines[j].Number {
+			return lines[i].Number < lines[j].Number
+		}
+		return lines[i].Text < lines[j].Text
+	})
+}
@@ -0,0 +1,293 @@
+package erpel
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var windowTests = []struct {
+	s   string
+	w   Window
+	err bool
+}{
+	{s: "10m", w: Window{Duration: 10 * time.Minute}},
+	{s: "2d", w: Window{Duration: 48 * time.Hour}},
+	{s: "100 lines", w: Window{Lines: 100}},
+	{s: "5lines", w: Window{Lines: 5}},
+	{s: "0 lines", err: true},
+	{s: "x lines", err: true},
+	{s: "10", err: true},
+	{s: "10ms", err: true},
+}
+
+func TestParseWindow(t *testing.T) {
+	for i, test := range windowTests {
+		w, err := parseWindow(test.s)
+		if test.err {
+			if err == nil {
+				t.Errorf("test %d: expected error for %q not found", i, test.s)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+
+		if w != test.w {
+			t.Errorf("test %d: want %v, got %v", i, test.w, w)
+		}
+	}
+}
+
+const testSequenceRules = `---
+@sequence = 'session'
+@key = 'id'
+@within = '{{window}}'
+connect id=123
+@sequence = 'session'
+@key = 'id'
+login id=123
+@sequence = 'session'
+@key = 'id'
+logout id=123
+`
+
+func sequenceRules(t *testing.T, window string) Rules {
+	fields := map[string]Field{
+		"id": {Name: "id", Template: "123", Pattern: regexp.MustCompile(`\d+`)},
+	}
+
+	rules, err := ParseRules(fields, strings.Replace(testSequenceRules, "{{window}}", window, 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "seq"
+
+	return rules
+}
+
+func processSequences(t *testing.T, rules Rules, opts Options, data string) []string {
+	var res []string
+	handler := func(lines []Line) error {
+		for _, l := range lines {
+			res = append(res, fmt.Sprintf("%v %v: %v", l.Number, l.Text, l.Summary))
+		}
+		return nil
+	}
+
+	if err := Process([]Rules{rules}, strings.NewReader(data), opts, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	return res
+}
+
+func TestProcessSequenceLines(t *testing.T) {
+	rules := sequenceRules(t, "3 lines")
+
+	data := `connect id=1
+login id=1
+connect id=2
+logout id=1
+login id=3
+connect id=4
+foo
+bar
+baz
+login id=4`
+
+	want := []string{
+		"5 login id=3: unexpected step of sequence session for id=3",
+		"7 foo: ",
+		"8 bar: ",
+		"9 baz: ",
+		"6 connect id=4: incomplete sequence session for id=4, missing \"login id=123\"",
+		"10 login id=4: unexpected step of sequence session for id=4",
+		"3 connect id=2: incomplete sequence session for id=2, missing \"login id=123\"",
+	}
+
+	res := processSequences(t, rules, Options{}, data)
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("wrong lines returned:\n  want %q\n   got %q", want, res)
+	}
+}
+
+func TestProcessSequencePersistent(t *testing.T) {
+	dir, err := ioutil.TempDir("", "erpel-correlations-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "correlations.json")
+	rules := sequenceRules(t, "1h")
+
+	now := time.Unix(1000000, 0)
+	opts := Options{
+		Correlations: NewCorrelations(),
+		Now:          func() time.Time { return now },
+	}
+
+	res := processSequences(t, rules, opts, "connect id=1\nconnect id=2\nlogin id=1\n")
+	if len(res) != 0 {
+		t.Errorf("unexpected lines returned: %q", res)
+	}
+
+	if err = opts.Correlations.Save(filename); err != nil {
+		t.Fatal(err)
+	}
+
+	opts.Correlations, err = LoadCorrelations(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now = now.Add(30 * time.Minute)
+	res = processSequences(t, rules, opts, "logout id=1\n")
+	if len(res) != 0 {
+		t.Errorf("unexpected lines returned: %q", res)
+	}
+
+	now = now.Add(time.Hour)
+	res = processSequences(t, rules, opts, "")
+	want := []string{
+		"0 connect id=2: incomplete sequence session for id=2, missing \"login id=123\"",
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("wrong lines returned:\n  want %q\n   got %q", want, res)
+	}
+
+	if len(opts.Correlations.Pending) != 0 {
+		t.Errorf("pending sequences not removed: %v", opts.Correlations.Pending)
+	}
+}
+
+func TestProcessSequenceCleanup(t *testing.T) {
+	now := time.Unix(1000000, 0)
+	newOpts := func() Options {
+		opts := Options{
+			Correlations: NewCorrelations(),
+			Now:          func() time.Time { return now },
+		}
+		opts.Correlations.Pending["other"] = &pending{Rules: "other", Sequence: "session", Step: 1, Started: now.Unix()}
+		opts.Correlations.Pending["gone"] = &pending{Rules: "seq", Sequence: "gone", Step: 1, Started: now.Unix()}
+		return opts
+	}
+
+	// sequences of rules files which are not loaded are kept
+	rules := sequenceRules(t, "1h")
+	opts := newOpts()
+	processSequences(t, rules, opts, "")
+	if _, ok := opts.Correlations.Pending["gone"]; ok || len(opts.Correlations.Pending) != 1 {
+		t.Errorf("wrong pending sequences kept: %v", opts.Correlations.Pending)
+	}
+
+	// the sequence may only be deselected in a partial rules file
+	rules.partial = true
+	opts = newOpts()
+	processSequences(t, rules, opts, "")
+	if len(opts.Correlations.Pending) != 2 {
+		t.Errorf("wrong pending sequences kept: %v", opts.Correlations.Pending)
+	}
+}
+
+func TestProcessSequenceMigrate(t *testing.T) {
+	rules := sequenceRules(t, "1h")
+
+	// sequences were saved by filename and the name of the sequence before
+	now := time.Unix(1000000, 0)
+	opts := Options{
+		Correlations: NewCorrelations(),
+		Now:          func() time.Time { return now },
+	}
+	opts.Correlations.Pending["seq\x00session\x001"] = &pending{
+		Filename: "seq",
+		Sequence: "session",
+		Key:      "1",
+		Step:     1,
+		Text:     "connect id=1",
+		Started:  now.Unix(),
+	}
+
+	res := processSequences(t, rules, opts, "login id=1\nlogout id=1\n")
+	if len(res) != 0 {
+		t.Errorf("unexpected lines returned: %q", res)
+	}
+
+	if len(opts.Correlations.Pending) != 0 {
+		t.Errorf("pending sequence not migrated: %v", opts.Correlations.Pending)
+	}
+}
+
+func TestProcessSequenceSources(t *testing.T) {
+	rules := sequenceRules(t, "3 lines")
+	opts := Options{Correlations: NewCorrelations()}
+
+	opts.Source = "a.log"
+	res := processSequences(t, rules, opts, "connect id=1\n")
+
+	// lines from other logs do not count for the window
+	opts.Source = "b.log"
+	res = append(res, processSequences(t, rules, opts, "connect id=2\nlogin id=2\nlogout id=2\nconnect id=3\n")...)
+
+	opts.Source = "a.log"
+	res = append(res, processSequences(t, rules, opts, "login id=1\nlogout id=1\n")...)
+
+	if len(res) != 0 {
+		t.Errorf("unexpected lines returned: %q", res)
+	}
+
+	want := map[string]int64{"a.log": 3, "b.log": 4}
+	if !reflect.DeepEqual(opts.Correlations.Lines, want) {
+		t.Errorf("wrong number of lines, want %v, got %v", want, opts.Correlations.Lines)
+	}
+}
+
+func TestProcessSequenceTimestamp(t *testing.T) {
+	fields := map[string]Field{
+		"timestamp": {
+			Name:       "timestamp",
+			Template:   "Jan  1 11:22:33",
+			Pattern:    regexp.MustCompile(`\w{3}  ?\d{1,2} \d{2}:\d{2}:\d{2}`),
+			TimeFormat: "Jan _2 15:04:05",
+		},
+		"id": {Name: "id", Template: "123", Pattern: regexp.MustCompile(`\d+`)},
+	}
+
+	rules, err := ParseRules(fields, "prefix = 'Jan  1 11:22:33 '\n"+
+		strings.Replace(testSequenceRules, "{{window}}", "10m", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "seq"
+
+	// the window is checked against the time of the messages
+	now := parseTime(t, "2026-10-19 12:00")
+	opts := Options{Now: func() time.Time { return now }}
+
+	data := `Oct 19 11:00:00 connect id=1
+Oct 19 11:05:00 login id=1
+Oct 19 11:06:00 logout id=1
+Oct 19 11:10:00 connect id=2
+Oct 19 11:30:00 login id=2`
+
+	want := []string{
+		"4 Oct 19 11:10:00 connect id=2: incomplete sequence session for id=2, missing \"login id=123\"",
+		"5 Oct 19 11:30:00 login id=2: unexpected step of sequence session for id=2",
+	}
+
+	res := processSequences(t, rules, opts, data)
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("wrong lines returned:\n  want %q\n   got %q", want, res)
+	}
+}
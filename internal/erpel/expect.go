@@ -0,0 +1,479 @@
+package erpel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Schedule describes how often a message is expected, either at a fixed
+// interval or at the times given by a cron expression.
+type Schedule struct {
+	Interval time.Duration
+
+	text string
+	cron *cronSchedule
+}
+
+func (s Schedule) String() string {
+	return s.text
+}
+
+var namedIntervals = map[string]time.Duration{
+	"hourly": time.Hour,
+	"daily":  24 * time.Hour,
+	"weekly": 7 * 24 * time.Hour,
+}
+
+// parseSchedule parses a schedule like "daily", "every 6h" or a cron
+// expression like "0 3 * * *".
+func parseSchedule(s string) (Schedule, error) {
+	s = strings.TrimSpace(s)
+
+	if d, ok := namedIntervals[s]; ok {
+		return Schedule{Interval: d, text: s}, nil
+	}
+
+	if strings.HasPrefix(s, "every ") {
+		d, err := parseDuration(strings.TrimSpace(strings.TrimPrefix(s, "every ")))
+		if err != nil || d < time.Minute {
+			return Schedule{}, errors.Errorf("invalid interval in schedule %q", s)
+		}
+
+		return Schedule{Interval: d, text: s}, nil
+	}
+
+	c, err := parseCron(s)
+	if err != nil {
+		return Schedule{}, err
+	}
+
+	return Schedule{text: s, cron: c}, nil
+}
+
+// deadline returns the latest point in time before now by which the message
+// should have been seen at least once, given it has last been seen at
+// lastSeen. If the message is not missing, false is returned.
+func (s Schedule) deadline(lastSeen, now time.Time) (time.Time, bool) {
+	if s.cron == nil {
+		since := now.Sub(lastSeen)
+		if since <= s.Interval {
+			return time.Time{}, false
+		}
+
+		periods := since / s.Interval
+		return lastSeen.Add(periods * s.Interval), true
+	}
+
+	// the message needs to be seen between two consecutive times of the
+	// schedule
+	d, ok := s.cron.prev(now)
+	if !ok {
+		return time.Time{}, false
+	}
+
+	before, ok := s.cron.prev(d.Add(-time.Minute))
+	if !ok || lastSeen.After(before) {
+		return time.Time{}, false
+	}
+
+	return d, true
+}
+
+// Next returns the point in time by which the message needs to be seen next.
+func (s Schedule) Next(lastSeen, now time.Time) time.Time {
+	if s.cron == nil {
+		return lastSeen.Add(s.Interval)
+	}
+
+	// find the first time of the schedule after the one following lastSeen
+	t, ok := s.cron.next(lastSeen)
+	if !ok {
+		return time.Time{}
+	}
+
+	t, _ = s.cron.next(t)
+	return t
+}
+
+// cronSchedule is a parsed cron expression with the fields minute, hour, day
+// of month, month and day of week.
+type cronSchedule struct {
+	minute, hour, dom, month, dow []bool
+
+	// domAll and dowAll are set if the field is "*"
+	domAll, dowAll bool
+}
+
+// parseCronField parses a field of a cron expression with lists, ranges and
+// steps, like "1,5-10/2" or "*/15".
+func parseCronField(s string, min, max int) ([]bool, error) {
+	set := make([]bool, max+1)
+
+	for _, item := range strings.Split(s, ",") {
+		step := 1
+		if i := strings.IndexByte(item, '/'); i >= 0 {
+			n, err := strconv.Atoi(item[i+1:])
+			if err != nil || n <= 0 {
+				return nil, errors.Errorf("invalid step in %q", s)
+			}
+			step = n
+			item = item[:i]
+		}
+
+		lo, hi := min, max
+		if item != "*" {
+			r := strings.SplitN(item, "-", 2)
+
+			var err error
+			lo, err = strconv.Atoi(r[0])
+			if err != nil {
+				return nil, errors.Errorf("invalid value in %q", s)
+			}
+
+			hi = lo
+			if len(r) == 2 {
+				hi, err = strconv.Atoi(r[1])
+				if err != nil {
+					return nil, errors.Errorf("invalid value in %q", s)
+				}
+			}
+		}
+
+		if lo < min || hi > max || lo > hi {
+			return nil, errors.Errorf("value out of range in %q", s)
+		}
+
+		for i := lo; i <= hi; i += step {
+			set[i] = true
+		}
+	}
+
+	return set, nil
+}
+
+// parseCron parses a cron expression.
+func parseCron(s string) (*cronSchedule, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("invalid schedule %q, use daily, hourly, weekly, every <duration> or a cron expression", s)
+	}
+
+	limits := []struct{ min, max int }{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := make([][]bool, len(fields))
+	for i, f := range fields {
+		set, err := parseCronField(f, limits[i].min, limits[i].max)
+		if err != nil {
+			return nil, errors.WithMessage(err, "cron expression "+strconv.Quote(s))
+		}
+		sets[i] = set
+	}
+
+	c := &cronSchedule{
+		minute: sets[0],
+		hour:   sets[1],
+		dom:    sets[2],
+		month:  sets[3],
+		dow:    sets[4][:7],
+		domAll: fields[2] == "*",
+		dowAll: fields[4] == "*",
+	}
+
+	// Sunday can be written as 0 or 7
+	if sets[4][7] {
+		c.dow[0] = true
+	}
+
+	return c, nil
+}
+
+// matchDay returns true if the schedule includes the day. Like cron, the day
+// matches if either the day of month or the day of week matches when both are
+// restricted.
+func (c *cronSchedule) matchDay(t time.Time) bool {
+	if !c.month[int(t.Month())] {
+		return false
+	}
+
+	dom, dow := c.dom[t.Day()], c.dow[int(t.Weekday())]
+	switch {
+	case c.domAll && c.dowAll:
+		return true
+	case c.domAll:
+		return dow
+	case c.dowAll:
+		return dom
+	default:
+		return dom || dow
+	}
+}
+
+// searchLimit is how far prev and next search for a matching time.
+const searchLimit = 5 * 366 * 24 * time.Hour
+
+// prev returns the latest time of the schedule which is not after t.
+func (c *cronSchedule) prev(t time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute)
+	limit := t.Add(-searchLimit)
+
+	for t.After(limit) {
+		if !c.matchDay(t) {
+			// continue with the last minute of the previous day
+			t = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location()).Add(-time.Minute)
+			continue
+		}
+
+		if !c.hour[t.Hour()] {
+			t = t.Add(-time.Duration(t.Minute()+1) * time.Minute)
+			continue
+		}
+
+		if c.minute[t.Minute()] {
+			return t, true
+		}
+
+		t = t.Add(-time.Minute)
+	}
+
+	return time.Time{}, false
+}
+
+// next returns the earliest time of the schedule which is after t.
+func (c *cronSchedule) next(t time.Time) (time.Time, bool) {
+	t = t.Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(searchLimit)
+
+	for t.Before(limit) {
+		if !c.matchDay(t) {
+			// continue with the first minute of the next day
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, t.Location())
+			continue
+		}
+
+		if !c.hour[t.Hour()] {
+			t = t.Add(time.Duration(60-t.Minute()) * time.Minute)
+			continue
+		}
+
+		if c.minute[t.Minute()] {
+			return t, true
+		}
+
+		t = t.Add(time.Minute)
+	}
+
+	return time.Time{}, false
+}
+
+// expectation records when an expected message has been seen.
+type expectation struct {
+	LastSeen int64 `json:"last_seen"`
+	Alerted  int64 `json:"alerted,omitempty"`
+
+	// Seen is false if the message has not been seen yet, LastSeen is the
+	// time the message was first expected in this case.
+	Seen bool `json:"seen"`
+}
+
+// Expectations keep track of when the messages matched by templates with a
+// schedule have been seen last. They are saved to the state directory.
+type Expectations struct {
+	Entries map[string]*expectation `json:"entries"`
+}
+
+// NewExpectations returns a new, empty Expectations.
+func NewExpectations() *Expectations {
+	return &Expectations{Entries: make(map[string]*expectation)}
+}
+
+// LoadExpectations reads the expectations from the file. If the file does not
+// exist, empty Expectations are returned.
+func LoadExpectations(filename string) (*Expectations, error) {
+	buf, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return NewExpectations(), nil
+	}
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	e := NewExpectations()
+	if err = json.Unmarshal(buf, e); err != nil {
+		return nil, errors.WithMessage(err, filename)
+	}
+
+	if e.Entries == nil {
+		e.Entries = make(map[string]*expectation)
+	}
+
+	return e, nil
+}
+
+// Save writes the expectations to the file.
+func (e *Expectations) Save(filename string) error {
+	buf, err := json.Marshal(e)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(ioutil.WriteFile(filename, buf, 0600))
+}
+
+// expectKey returns the key for the expectation of a template.
+func expectKey(r *Rules, t Template) string {
+	return r.TemplateID(t)
+}
+
+// migrate renames the expectations saved with the keys used before template
+// IDs, which consisted of the filename and the text of the template.
+func (e *Expectations) migrate(rules []Rules) {
+	for i := range rules {
+		r := &rules[i]
+		for _, t := range r.Templates {
+			old := r.Filename + "\x00" + t.Text
+			exp, ok := e.Entries[old]
+			if !ok {
+				continue
+			}
+
+			delete(e.Entries, old)
+			if _, ok := e.Entries[expectKey(r, t)]; !ok {
+				e.Entries[expectKey(r, t)] = exp
+			}
+		}
+	}
+}
+
+// seen records that the message for the key has been seen at the time ts,
+// messages older than the one recorded before are ignored.
+func (e *Expectations) seen(key string, ts time.Time) {
+	if exp, ok := e.Entries[key]; ok && exp.Seen && exp.LastSeen > ts.Unix() {
+		return
+	}
+
+	e.Entries[key] = &expectation{LastSeen: ts.Unix(), Seen: true}
+}
+
+// ExpectStatus is the status of a template with a schedule.
+type ExpectStatus struct {
+	Rules    *Rules
+	Template Template
+
+	// Seen is false if the message has not been seen since erpel started
+	// to expect it, LastSeen is the time it was first expected then.
+	Seen     bool
+	LastSeen time.Time
+
+	// Next is the point in time by which the message needs to be seen, it
+	// is in the past if Missing is set.
+	Next    time.Time
+	Missing bool
+}
+
+// Status returns the status of all templates with a schedule. Templates
+// which have not been tracked before start to be expected now.
+func (e *Expectations) Status(rules []Rules, now time.Time) []ExpectStatus {
+	e.migrate(rules)
+
+	var list []ExpectStatus
+	for i := range rules {
+		r := &rules[i]
+		for _, t := range r.Templates {
+			if t.Expect == nil {
+				continue
+			}
+
+			key := expectKey(r, t)
+			exp, ok := e.Entries[key]
+			if !ok {
+				exp = &expectation{LastSeen: now.Unix()}
+				e.Entries[key] = exp
+			}
+
+			lastSeen := time.Unix(exp.LastSeen, 0)
+			_, missing := t.Expect.deadline(lastSeen, now)
+			list = append(list, ExpectStatus{
+				Rules:    r,
+				Template: t,
+				Seen:     exp.Seen,
+				LastSeen: lastSeen,
+				Next:     t.Expect.Next(lastSeen, now),
+				Missing:  missing,
+			})
+		}
+	}
+
+	return list
+}
+
+// CheckExpected returns alert lines for all messages which have not been seen
+// as expected. Each missed deadline is alerted only once. Expectations of
+// templates which are not within their rules file any more are removed, those
+// of rules files which are not loaded or only partially are kept.
+func (e *Expectations) CheckExpected(rules []Rules, now time.Time) (lines []Line) {
+	e.migrate(rules)
+	keys := make(map[string]struct{})
+	complete := make(map[string]struct{})
+
+	for i := range rules {
+		r := &rules[i]
+		if !r.partial {
+			complete[r.idPrefix()] = struct{}{}
+		}
+
+		for _, t := range r.Templates {
+			if t.Expect == nil {
+				continue
+			}
+
+			key := expectKey(r, t)
+			keys[key] = struct{}{}
+
+			exp, ok := e.Entries[key]
+			if !ok {
+				e.Entries[key] = &expectation{LastSeen: now.Unix()}
+				continue
+			}
+
+			lastSeen := time.Unix(exp.LastSeen, 0)
+			deadline, missing := t.Expect.deadline(lastSeen, now)
+			if !missing || exp.Alerted >= deadline.Unix() {
+				continue
+			}
+
+			exp.Alerted = now.Unix()
+
+			line := Line{
+				Text:   fmt.Sprintf("expected message (%v) not seen since %v", t.Expect, lastSeen.Format(time.RFC3339)),
+				Action: ActionAlert,
+				Rule:   r.Describe(t),
+			}
+			line.Category, line.Severity = r.Classify(t)
+			lines = append(lines, line)
+		}
+	}
+
+	for key := range e.Entries {
+		if _, ok := complete[idPrefixOf(key)]; !ok {
+			continue
+		}
+
+		if _, ok := keys[key]; !ok {
+			delete(e.Entries, key)
+		}
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		return lines[i].Rule < lines[j].Rule
+	})
+
+	return lines
+}
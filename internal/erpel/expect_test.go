@@ -0,0 +1,266 @@
+package erpel
+
+import (
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func parseTime(t testing.TB, s string) time.Time {
+	ts, err := time.ParseInLocation("2006-01-02 15:04", s, time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ts
+}
+
+var scheduleTests = []struct {
+	schedule string
+	lastSeen string
+	now      string
+	deadline string
+	next     string
+}{
+	{"daily", "2026-10-01 03:00", "2026-10-02 02:00", "", "2026-10-02 03:00"},
+	{"daily", "2026-10-01 03:00", "2026-10-02 04:00", "2026-10-02 03:00", "2026-10-02 03:00"},
+	{"daily", "2026-10-01 03:00", "2026-10-04 04:00", "2026-10-04 03:00", "2026-10-02 03:00"},
+	{"every 6h", "2026-10-01 03:00", "2026-10-01 10:00", "2026-10-01 09:00", "2026-10-01 09:00"},
+	{"every 2d", "2026-10-01 03:00", "2026-10-02 10:00", "", "2026-10-03 03:00"},
+	// the message needs to be seen between two consecutive times
+	{"0 3 * * *", "2026-10-01 02:50", "2026-10-02 03:30", "2026-10-02 03:00", "2026-10-02 03:00"},
+	{"0 3 * * *", "2026-10-01 03:10", "2026-10-02 03:30", "", "2026-10-03 03:00"},
+	{"0 3 * * *", "2026-10-01 02:50", "2026-10-02 02:30", "", "2026-10-02 03:00"},
+	{"0 3 * * *", "2026-09-30 02:50", "2026-10-01 03:30", "2026-10-01 03:00", "2026-10-01 03:00"},
+	{"0 3 * * *", "2026-09-30 02:50", "2026-10-05 12:00", "2026-10-05 03:00", "2026-10-01 03:00"},
+	{"*/15 8-17 * * 1-5", "2026-10-16 17:50", "2026-10-19 08:05", "", "2026-10-19 08:15"},
+	{"*/15 8-17 * * 1-5", "2026-10-16 17:50", "2026-10-19 08:20", "2026-10-19 08:15", "2026-10-19 08:15"},
+	{"30 4 1 * 7", "2026-10-01 04:40", "2026-10-04 05:00", "", "2026-10-11 04:30"},
+}
+
+func TestSchedule(t *testing.T) {
+	for i, test := range scheduleTests {
+		s, err := parseSchedule(test.schedule)
+		if err != nil {
+			t.Errorf("test %d: parse %q failed: %v", i, test.schedule, err)
+			continue
+		}
+
+		lastSeen, now := parseTime(t, test.lastSeen), parseTime(t, test.now)
+		deadline, missing := s.deadline(lastSeen, now)
+		if missing != (test.deadline != "") {
+			t.Errorf("test %d: %v: wrong missing status %v", i, test.schedule, missing)
+			continue
+		}
+
+		if missing && !deadline.Equal(parseTime(t, test.deadline)) {
+			t.Errorf("test %d: %v: want deadline %v, got %v", i, test.schedule, test.deadline, deadline)
+		}
+
+		next := s.Next(lastSeen, now)
+		if !next.Equal(parseTime(t, test.next)) {
+			t.Errorf("test %d: %v: want next %v, got %v", i, test.schedule, test.next, next)
+		}
+	}
+}
+
+var invalidSchedules = []string{
+	"",
+	"sometimes",
+	"every",
+	"every 10s",
+	"every x",
+	"0 3 * *",
+	"60 3 * * *",
+	"0 3 0 * *",
+	"0 3 * * 8",
+	"5-1 * * * *",
+	"*/0 * * * *",
+}
+
+func TestParseScheduleInvalid(t *testing.T) {
+	for i, s := range invalidSchedules {
+		if _, err := parseSchedule(s); err == nil {
+			t.Errorf("test %d: expected error for %q not found", i, s)
+		}
+	}
+}
+
+func TestCheckExpected(t *testing.T) {
+	fields := map[string]Field{
+		"num": {Name: "num", Template: "123", Pattern: regexp.MustCompile(`\d+`)},
+	}
+
+	rules, err := ParseRules(fields, "---\n@expect = 'daily'\nbackup completed in 123s\nother message\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "backup"
+
+	now := parseTime(t, "2026-10-01 03:00")
+	opts := Options{
+		Expectations: NewExpectations(),
+		Now:          func() time.Time { return now },
+	}
+
+	process := func(data string) {
+		err := Process([]Rules{rules}, strings.NewReader(data), opts, func([]Line) error { return nil })
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	check := func() []string {
+		var res []string
+		for _, l := range opts.Expectations.CheckExpected([]Rules{rules}, now) {
+			res = append(res, l.Action.String()+" "+l.Text)
+		}
+		return res
+	}
+
+	// the first check starts to expect the message
+	if res := check(); len(res) != 0 {
+		t.Fatalf("unexpected alerts: %v", res)
+	}
+
+	now = now.Add(25 * time.Hour)
+	want := []string{"alert expected message (daily) not seen since 2026-10-01T03:00:00Z"}
+	if res := check(); !reflect.DeepEqual(res, want) {
+		t.Errorf("wrong alerts, want %q, got %q", want, res)
+	}
+
+	// the missing message is only alerted once per period
+	now = now.Add(time.Hour)
+	if res := check(); len(res) != 0 {
+		t.Errorf("unexpected alerts: %v", res)
+	}
+
+	process("other message\nbackup completed in 42s\n")
+	now = now.Add(23 * time.Hour)
+	if res := check(); len(res) != 0 {
+		t.Errorf("unexpected alerts: %v", res)
+	}
+
+	now = now.Add(2 * time.Hour)
+	want = []string{"alert expected message (daily) not seen since 2026-10-02T05:00:00Z"}
+	if res := check(); !reflect.DeepEqual(res, want) {
+		t.Errorf("wrong alerts, want %q, got %q", want, res)
+	}
+
+	status := opts.Expectations.Status([]Rules{rules}, now)
+	if len(status) != 1 || !status[0].Seen || !status[0].Missing {
+		t.Errorf("wrong status returned: %+v", status)
+	}
+}
+
+func TestCheckExpectedCleanup(t *testing.T) {
+	rules, err := ParseRules(nil, "---\n@expect = 'daily'\nbackup completed\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "/etc/erpel/rules.d/backup"
+
+	partial, err := ParseRules(nil, "---\n@expect = 'daily'\ncron started\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	partial.Filename = "/etc/erpel/rules.d/cron"
+	partial.partial = true
+
+	now := parseTime(t, "2026-10-19 12:00")
+	e := NewExpectations()
+	for _, key := range []string{"backup/removed", "cron/deselected", "postfix/queue"} {
+		e.Entries[key] = &expectation{LastSeen: now.Unix(), Seen: true}
+	}
+
+	e.CheckExpected([]Rules{rules, partial}, now)
+
+	var keys []string
+	for key := range e.Entries {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	// only the expectation of a template removed from a rules file which
+	// is loaded completely is removed
+	want := []string{rules.TemplateID(rules.Templates[0]), partial.TemplateID(partial.Templates[0]), "cron/deselected", "postfix/queue"}
+	sort.Strings(want)
+	if !reflect.DeepEqual(keys, want) {
+		t.Errorf("wrong expectations kept, want %q, got %q", want, keys)
+	}
+}
+
+func TestExpectationsMigrate(t *testing.T) {
+	rules, err := ParseRules(nil, "---\n@expect = 'daily'\nbackup completed\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "/etc/erpel/rules.d/backup"
+
+	// expectations were saved by filename and template text before
+	now := parseTime(t, "2026-10-19 12:00")
+	e := NewExpectations()
+	e.Entries["/etc/erpel/rules.d/backup\x00backup completed"] = &expectation{
+		LastSeen: now.Add(-time.Hour).Unix(),
+		Seen:     true,
+	}
+
+	status := e.Status([]Rules{rules}, now)
+	if len(status) != 1 || !status[0].Seen || status[0].Missing {
+		t.Errorf("wrong status returned: %+v", status)
+	}
+
+	id := rules.TemplateID(rules.Templates[0])
+	if _, ok := e.Entries[id]; !ok || len(e.Entries) != 1 {
+		t.Errorf("expectation not migrated to the key %q: %v", id, e.Entries)
+	}
+}
+
+func TestProcessExpectedTimestamp(t *testing.T) {
+	fields := map[string]Field{
+		"timestamp": {
+			Name:       "timestamp",
+			Template:   "Jan  1 11:22:33",
+			Pattern:    regexp.MustCompile(`\w{3}  ?\d{1,2} \d{2}:\d{2}:\d{2}`),
+			TimeFormat: "Jan _2 15:04:05",
+		},
+		"num": {Name: "num", Template: "123", Pattern: regexp.MustCompile(`\d+`)},
+	}
+
+	// the broad rules file is tried first, the message is recorded anyway
+	broad, err := ParseRules(fields, "prefix = 'Jan  1 11:22:33 '\n---\nbackup completed in 123s\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := ParseRules(fields, "prefix = 'Jan  1 11:22:33 '\n---\n@expect = 'daily'\nbackup completed in 123s\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "backup"
+
+	now := parseTime(t, "2026-10-19 12:00")
+	opts := Options{
+		Expectations: NewExpectations(),
+		Now:          func() time.Time { return now },
+	}
+
+	data := "Oct 18 03:00:00 backup completed in 42s\n"
+	err = Process([]Rules{broad, rules}, strings.NewReader(data), opts, func([]Line) error { return nil })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the message was seen at the time it was logged
+	status := opts.Expectations.Status([]Rules{rules}, now)
+	if len(status) != 1 || !status[0].Seen || !status[0].LastSeen.Equal(parseTime(t, "2026-10-18 03:00")) {
+		t.Errorf("wrong status returned: %+v", status)
+	}
+
+	if !status[0].Missing {
+		t.Errorf("message seen more than a day ago is not missing: %+v", status[0])
+	}
+}
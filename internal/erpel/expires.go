@@ -0,0 +1,70 @@
+package erpel
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// dateFormat is the format for expiry dates.
+const dateFormat = "2006-01-02"
+
+// parseExpires parses an expiry date like "2026-11-01" in the local time zone.
+func parseExpires(s string) (time.Time, error) {
+	t, err := time.ParseInLocation(dateFormat, s, time.Local)
+	if err != nil {
+		return time.Time{}, errors.Errorf("invalid expiry date %q, format is YYYY-MM-DD", s)
+	}
+
+	return t, nil
+}
+
+// expired returns true if the date has passed at now, rules are still applied
+// on the day they expire.
+func expired(date, now time.Time) bool {
+	return !date.IsZero() && !now.Before(date.AddDate(0, 0, 1))
+}
+
+// expires returns the expiry date of the template, the earlier one of the
+// template and the rules.
+func (r *Rules) expires(t Template) time.Time {
+	if t.Expires.IsZero() || (!r.Expires.IsZero() && r.Expires.Before(t.Expires)) {
+		return r.Expires
+	}
+
+	return t.Expires
+}
+
+// Expiring returns warnings for the rules and templates which have expired or
+// expire within the given number of days after now.
+func (r *Rules) Expiring(now time.Time, days int) (warnings []string) {
+	limit := now.AddDate(0, 0, days)
+
+	check := func(date time.Time, what string) {
+		switch {
+		case date.IsZero() || date.After(limit):
+		case expired(date, now):
+			warnings = append(warnings, fmt.Sprintf("%v expired on %v", what, date.Format(dateFormat)))
+		default:
+			warnings = append(warnings, fmt.Sprintf("%v expires on %v", what, date.Format(dateFormat)))
+		}
+	}
+
+	name := r.Filename
+	if name == "" {
+		name = "rules"
+	}
+	check(r.Expires, name)
+
+	for _, t := range r.Templates {
+		if t.Expires.IsZero() || (!r.Expires.IsZero() && !t.Expires.Before(r.Expires)) {
+			// already covered by the warning for the rules
+			continue
+		}
+
+		check(t.Expires, r.Describe(t))
+	}
+
+	return warnings
+}
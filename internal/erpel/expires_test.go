@@ -0,0 +1,126 @@
+package erpel
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestExpired(t *testing.T) {
+	date, err := parseExpires("2026-11-01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		now     time.Time
+		expired bool
+	}{
+		{time.Date(2026, 10, 31, 12, 0, 0, 0, time.Local), false},
+		{time.Date(2026, 11, 1, 23, 59, 0, 0, time.Local), false},
+		{time.Date(2026, 11, 2, 0, 0, 0, 0, time.Local), true},
+	}
+
+	for i, test := range tests {
+		if expired(date, test.now) != test.expired {
+			t.Errorf("test %d: want expired %v at %v", i, test.expired, test.now)
+		}
+	}
+
+	if expired(time.Time{}, time.Now()) {
+		t.Errorf("zero date is expired")
+	}
+
+	for _, s := range []string{"", "2026-13-01", "01.11.2026", "tomorrow"} {
+		if _, err := parseExpires(s); err == nil {
+			t.Errorf("expected error for %q not found", s)
+		}
+	}
+}
+
+func TestRulesExpiring(t *testing.T) {
+	rules, err := ParseRules(nil, "expires = '2026-12-31'\n---\n"+
+		"@expires = '2026-10-20'\ncert warning\n"+
+		"@expires = '2026-10-01'\nold warning\n"+
+		"@expires = '2027-01-31'\nlater warning\n"+
+		"other message\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "tmp"
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.Local)
+
+	want := []string{
+		"tmp:4: cert warning expires on 2026-10-20",
+		"tmp:6: old warning expired on 2026-10-01",
+	}
+
+	if w := rules.Expiring(now, 7); !reflect.DeepEqual(w, want) {
+		t.Errorf("wrong warnings returned:\n  want %q\n   got %q", want, w)
+	}
+
+	want = append([]string{"tmp expires on 2026-12-31"}, want...)
+	if w := rules.Expiring(now, 90); !reflect.DeepEqual(w, want) {
+		t.Errorf("wrong warnings returned:\n  want %q\n   got %q", want, w)
+	}
+
+	var tests = []struct {
+		line string
+		ok   bool
+	}{
+		{"cert warning", true},
+		{"old warning", false},
+		{"later warning", true},
+		{"other message", true},
+	}
+
+	for i, test := range tests {
+		if _, _, ok := rules.MatchAt(test.line, now); ok != test.ok {
+			t.Errorf("test %d: %q: want %v, got %v", i, test.line, test.ok, ok)
+		}
+	}
+
+	// all templates expire with the rules
+	now = time.Date(2027, 1, 1, 0, 0, 0, 0, time.Local)
+	for i, test := range tests {
+		if _, _, ok := rules.MatchAt(test.line, now); ok {
+			t.Errorf("test %d: %q matched after the rules have expired", i, test.line)
+		}
+	}
+}
+
+func TestProcessExpired(t *testing.T) {
+	rules, err := ParseRules(nil, "---\n@expires = '2026-10-20'\ncert warning\nother message\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "tmp"
+
+	var res []string
+	handler := func(lines []Line) error {
+		for _, l := range lines {
+			res = append(res, fmt.Sprintf("%v %v", l.Number, l.Text))
+		}
+		return nil
+	}
+
+	data := "cert warning\nother message\n"
+	for _, now := range []time.Time{
+		time.Date(2026, 10, 20, 12, 0, 0, 0, time.Local),
+		time.Date(2026, 10, 21, 0, 0, 0, 0, time.Local),
+	} {
+		opts := Options{Now: func() time.Time { return now }}
+		if err = Process([]Rules{rules}, strings.NewReader(data), opts, handler); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// after the expiry date, the template does not match any more
+	want := []string{"1 cert warning"}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("wrong lines returned:\n  want %q\n   got %q", want, res)
+	}
+}
@@ -0,0 +1,164 @@
+package erpel
+
+import (
+	"bufio"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// ValuesFile is a file which lists allowed values for a field, one per line.
+// Empty lines and lines starting with # are ignored. The file is read again
+// when it has been modified.
+type ValuesFile struct {
+	Filename string
+
+	m       sync.Mutex
+	values  map[string]struct{}
+	modTime time.Time
+	checked time.Time
+}
+
+// valuesFileCheckInterval is the minimal time between two checks whether a
+// values file has been modified.
+const valuesFileCheckInterval = 5 * time.Second
+
+// newValuesFile returns a ValuesFile and reads the values.
+func newValuesFile(filename string) (*ValuesFile, error) {
+	vf := &ValuesFile{Filename: filename}
+	if err := vf.Reload(); err != nil {
+		return nil, err
+	}
+
+	return vf, nil
+}
+
+// Reload reads the file again if it has been modified since it was read.
+func (vf *ValuesFile) Reload() error {
+	vf.m.Lock()
+	defer vf.m.Unlock()
+
+	return vf.reload()
+}
+
+func (vf *ValuesFile) reload() error {
+	vf.checked = time.Now()
+
+	fi, err := os.Stat(vf.Filename)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	if vf.values != nil && fi.ModTime().Equal(vf.modTime) {
+		return nil
+	}
+
+	fd, err := os.Open(vf.Filename)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer fd.Close()
+
+	values := make(map[string]struct{})
+	sc := bufio.NewScanner(fd)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		values[line] = struct{}{}
+	}
+
+	if err = sc.Err(); err != nil {
+		return errors.WithMessage(err, vf.Filename)
+	}
+
+	vf.values = values
+	vf.modTime = fi.ModTime()
+
+	return nil
+}
+
+// Contains returns true if the file lists s. When the file has been modified
+// it is read again, if this fails the values read before are used.
+func (vf *ValuesFile) Contains(s string) bool {
+	vf.m.Lock()
+	defer vf.m.Unlock()
+
+	if time.Since(vf.checked) >= valuesFileCheckInterval {
+		_ = vf.reload()
+	}
+
+	_, ok := vf.values[s]
+	return ok
+}
+
+// parseNetworks parses the list of networks in CIDR notation.
+func parseNetworks(list []string) (networks []*net.IPNet, err error) {
+	for _, s := range list {
+		_, network, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// hasConstraints returns true if the values of the field are restricted.
+func (f *Field) hasConstraints() bool {
+	return len(f.Values) > 0 || len(f.NotValues) > 0 || len(f.Networks) > 0 || f.ValuesFile != nil
+}
+
+// checkConstraints returns an error if s is not allowed by the constraints
+// of the field. When values and a values file are configured, s needs to be
+// listed in either of them.
+func (f *Field) checkConstraints(s string) error {
+	for _, v := range f.NotValues {
+		if s == v {
+			return errors.Errorf("value %q is not allowed", s)
+		}
+	}
+
+	if len(f.Values) > 0 || f.ValuesFile != nil {
+		found := false
+		for _, v := range f.Values {
+			if s == v {
+				found = true
+				break
+			}
+		}
+
+		if !found && f.ValuesFile != nil {
+			found = f.ValuesFile.Contains(s)
+		}
+
+		if !found {
+			return errors.Errorf("value %q is not in the list of allowed values", s)
+		}
+	}
+
+	if len(f.Networks) > 0 {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return errors.Errorf("value %q is not an IP address", s)
+		}
+
+		for _, network := range f.Networks {
+			if network.Contains(ip) {
+				return nil
+			}
+		}
+
+		return errors.Errorf("address %q is not within the allowed networks", s)
+	}
+
+	return nil
+}
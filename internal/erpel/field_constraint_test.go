@@ -0,0 +1,105 @@
+package erpel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestFieldConstraints(t *testing.T) {
+	networks, err := parseNetworks([]string{"10.0.0.0/8", "2001:db8::/32"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		field   Field
+		valid   []string
+		invalid []string
+	}{
+		{
+			field:   Field{Values: []string{"backup", "nagios"}},
+			valid:   []string{"backup", "nagios"},
+			invalid: []string{"root", "Backup", ""},
+		},
+		{
+			field:   Field{NotValues: []string{"root"}},
+			valid:   []string{"backup", "roots"},
+			invalid: []string{"root"},
+		},
+		{
+			field:   Field{Networks: networks},
+			valid:   []string{"10.1.2.3", "2001:db8::1"},
+			invalid: []string{"192.168.0.1", "2001:db9::1", "foo"},
+		},
+	}
+
+	for i, test := range tests {
+		for _, s := range test.valid {
+			if err := test.field.validate(s); err != nil {
+				t.Errorf("test %d: value %q is not valid: %v", i, s, err)
+			}
+		}
+
+		for _, s := range test.invalid {
+			if err := test.field.validate(s); err == nil {
+				t.Errorf("test %d: value %q is valid", i, s)
+			}
+		}
+	}
+}
+
+func TestValuesFile(t *testing.T) {
+	tempdir, err := ioutil.TempDir("", "erpel-test-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(tempdir)
+
+	filename := filepath.Join(tempdir, "users.txt")
+	if err = ioutil.WriteFile(filename, []byte("# service accounts\nbackup\n\n  nagios  \n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	vf, err := newValuesFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f := Field{Values: []string{"monitor"}, ValuesFile: vf}
+	for _, s := range []string{"backup", "nagios", "monitor"} {
+		if err := f.validate(s); err != nil {
+			t.Errorf("value %q is not valid: %v", s, err)
+		}
+	}
+
+	for _, s := range []string{"root", "# service accounts", ""} {
+		if err := f.validate(s); err == nil {
+			t.Errorf("value %q is valid", s)
+		}
+	}
+
+	if err = ioutil.WriteFile(filename, []byte("root\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// make sure the modification time differs
+	mtime := time.Now().Add(time.Minute)
+	if err = os.Chtimes(filename, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = vf.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if vf.Contains("backup") || !vf.Contains("root") {
+		t.Errorf("values file has not been reloaded")
+	}
+
+	if _, err = newValuesFile(filepath.Join(tempdir, "missing")); err == nil {
+		t.Errorf("expected error for missing file not found")
+	}
+}
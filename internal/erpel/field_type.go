@@ -0,0 +1,209 @@
+package erpel
+
+import (
+	"net"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// fieldType is a built-in type for fields. Values matched by the pattern are
+// validated with check afterwards.
+type fieldType struct {
+	template string
+	pattern  string
+	check    func(s string) error
+
+	// value returns the numeric value for ordered types, min and max are
+	// only allowed for these.
+	value func(s string) (int64, error)
+}
+
+var fieldTypes = map[string]fieldType{
+	"ip": {
+		template: "1.2.3.4",
+		pattern:  `[0-9a-fA-F.:]*[0-9a-fA-F]`,
+		check:    checkIP(""),
+	},
+	"ipv4": {
+		template: "192.0.2.1",
+		pattern:  `\d{1,3}(\.\d{1,3}){3}`,
+		check:    checkIP("ipv4"),
+	},
+	"ipv6": {
+		template: "2001:db8::1",
+		pattern:  `[0-9a-fA-F.]*:[0-9a-fA-F.:]*`,
+		check:    checkIP("ipv6"),
+	},
+	"uuid": {
+		template: "123e4567-e89b-12d3-a456-426614174000",
+		pattern:  `[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`,
+	},
+	"int": {
+		template: "123",
+		pattern:  `[-+]?\d+`,
+		check: func(s string) error {
+			_, err := strconv.ParseInt(s, 10, 64)
+			return err
+		},
+		value: func(s string) (int64, error) {
+			return strconv.ParseInt(s, 10, 64)
+		},
+	},
+	"hostname": {
+		template: "host.example.com",
+		pattern:  `[a-zA-Z0-9]([a-zA-Z0-9.-]*[a-zA-Z0-9])?`,
+		check:    checkHostname,
+	},
+	"email": {
+		template: "user@example.com",
+		pattern:  `[a-zA-Z0-9!#$%&'*+/=?^_{|}~.-]+@[a-zA-Z0-9.-]+`,
+		check:    checkEmail,
+	},
+	"duration": {
+		template: "1.5s",
+		pattern:  `[-+]?(\d+(\.\d+)?(ns|us|µs|ms|s|m|h))+`,
+		check: func(s string) error {
+			_, err := time.ParseDuration(s)
+			return err
+		},
+		value: func(s string) (int64, error) {
+			d, err := time.ParseDuration(s)
+			return int64(d), err
+		},
+	},
+}
+
+// lookupFieldType returns the type for the name.
+func lookupFieldType(name string) (fieldType, error) {
+	t, ok := fieldTypes[name]
+	if !ok {
+		return fieldType{}, errors.Errorf("unknown type %q", name)
+	}
+
+	return t, nil
+}
+
+// checkIP returns a function which checks for a valid IP address of the
+// version, both versions are accepted if it is empty.
+func checkIP(version string) func(string) error {
+	return func(s string) error {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return errors.Errorf("invalid IP address %q", s)
+		}
+
+		v4 := ip.To4() != nil && !strings.Contains(s, ":")
+		if (version == "ipv4" && !v4) || (version == "ipv6" && v4) {
+			return errors.Errorf("%q is not an %v address", s, version)
+		}
+
+		return nil
+	}
+}
+
+// checkHostname returns an error if s is not a valid host name.
+func checkHostname(s string) error {
+	if len(s) > 253 {
+		return errors.Errorf("host name %q is too long", s)
+	}
+
+	for _, label := range strings.Split(strings.TrimSuffix(s, "."), ".") {
+		if label == "" || len(label) > 63 {
+			return errors.Errorf("host name %q has an invalid label", s)
+		}
+
+		if label[0] == '-' || label[len(label)-1] == '-' {
+			return errors.Errorf("host name %q has an invalid label %q", s, label)
+		}
+	}
+
+	return nil
+}
+
+// checkEmail returns an error if s is not a valid mail address.
+func checkEmail(s string) error {
+	i := strings.LastIndexByte(s, '@')
+	if i <= 0 || i > 64 {
+		return errors.Errorf("invalid local part in mail address %q", s)
+	}
+
+	return checkHostname(s[i+1:])
+}
+
+// validate returns an error if the value matched by the field's pattern does
+// not conform to the type, range and constraints of the field.
+func (f *Field) validate(s string) error {
+	if err := f.checkType(s); err != nil {
+		return err
+	}
+
+	return f.checkConstraints(s)
+}
+
+// checkType returns an error if s is not valid for the type and range.
+func (f *Field) checkType(s string) error {
+	if f.Type == "" {
+		return nil
+	}
+
+	t, err := lookupFieldType(f.Type)
+	if err != nil {
+		return err
+	}
+
+	if t.check != nil {
+		if err := t.check(s); err != nil {
+			return err
+		}
+	}
+
+	if t.value == nil || (f.Min == nil && f.Max == nil) {
+		return nil
+	}
+
+	v, err := t.value(s)
+	if err != nil {
+		return err
+	}
+
+	if f.Min != nil && v < *f.Min {
+		return errors.Errorf("value %q is below the minimum", s)
+	}
+
+	if f.Max != nil && v > *f.Max {
+		return errors.Errorf("value %q is above the maximum", s)
+	}
+
+	return nil
+}
+
+// hasChecks returns true if values need to be validated after a match.
+func (f *Field) hasChecks() bool {
+	return f.Type != "" || f.hasConstraints()
+}
+
+// parseLimit parses min or max for the field type.
+func parseLimit(typ, s string) (*int64, error) {
+	if typ == "" {
+		return nil, errors.New("min and max need a type")
+	}
+
+	t, err := lookupFieldType(typ)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.value == nil {
+		return nil, errors.Errorf("min and max are not supported for type %v", typ)
+	}
+
+	v, err := t.value(s)
+	if err != nil {
+		return nil, err
+	}
+
+	return &v, nil
+}
@@ -0,0 +1,65 @@
+package erpel
+
+import "testing"
+
+func int64p(v int64) *int64 {
+	return &v
+}
+
+var testFieldValidate = []struct {
+	field   Field
+	valid   []string
+	invalid []string
+}{
+	{
+		field:   Field{Type: "ip"},
+		valid:   []string{"192.168.0.1", "2003::a07", "::1", "2a01:4f8::1234:1"},
+		invalid: []string{"87.444.65.555", "2003:::a07", "1.2.3"},
+	},
+	{
+		field:   Field{Type: "ipv4"},
+		valid:   []string{"10.0.0.1", "255.255.255.255"},
+		invalid: []string{"256.1.1.1", "::ffff:10.0.0.1", "2003::1"},
+	},
+	{
+		field:   Field{Type: "ipv6"},
+		valid:   []string{"2003::1", "::ffff:10.0.0.1"},
+		invalid: []string{"10.0.0.1", "2003::g"},
+	},
+	{
+		field:   Field{Type: "int", Min: int64p(1), Max: int64p(65535)},
+		valid:   []string{"1", "25", "65535"},
+		invalid: []string{"0", "65536", "-1", "99999999999999999999"},
+	},
+	{
+		field:   Field{Type: "hostname"},
+		valid:   []string{"localhost", "mail.example.com", "a-b.example.com."},
+		invalid: []string{"-foo.example.com", "foo..example.com", "foo-.com"},
+	},
+	{
+		field:   Field{Type: "email"},
+		valid:   []string{"user@example.com", "a.b+c@mail.example.org"},
+		invalid: []string{"@example.com", "user@-example.com"},
+	},
+	{
+		field:   Field{Type: "duration", Max: int64p(int64(60 * 1e9))},
+		valid:   []string{"1.5s", "59s", "1m", "300ms"},
+		invalid: []string{"1m1s", "2h", "5x"},
+	},
+}
+
+func TestFieldValidate(t *testing.T) {
+	for i, test := range testFieldValidate {
+		for _, s := range test.valid {
+			if err := test.field.validate(s); err != nil {
+				t.Errorf("test %d: value %q is not valid: %v", i, s, err)
+			}
+		}
+
+		for _, s := range test.invalid {
+			if err := test.field.validate(s); err == nil {
+				t.Errorf("test %d: value %q is valid", i, s)
+			}
+		}
+	}
+}
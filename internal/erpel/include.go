@@ -0,0 +1,125 @@
+package erpel
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/fd0/erpel/internal/rules"
+	"github.com/pkg/errors"
+)
+
+// includeChain describes the files which include each other, for error
+// messages.
+func includeChain(chain []string) string {
+	return strings.Join(chain, " -> ")
+}
+
+// includes collects the fields from included files.
+type includes struct {
+	// dir is the directory relative names are resolved to
+	dir string
+
+	fields map[string]rules.Field
+
+	// source is the file each field is defined in
+	source map[string]string
+
+	// files lists all included files in the order they were included
+	files []string
+}
+
+// load adds the fields from the files in list, which are included by the
+// last file in chain.
+func (inc *includes) load(list []string, chain []string) error {
+	for _, name := range list {
+		name, err := unquoteString(name)
+		if err != nil {
+			return errors.Errorf("%v: include %v: %v", includeChain(chain), name, err)
+		}
+
+		filename := name
+		if !filepath.IsAbs(filename) {
+			filename = filepath.Join(inc.dir, filename)
+		}
+
+		if filename, err = filepath.Abs(filename); err != nil {
+			return errors.WithStack(err)
+		}
+
+		next := append(chain[:len(chain):len(chain)], filename)
+		for _, f := range chain {
+			if f == filename {
+				return errors.Errorf("include cycle: %v", includeChain(next))
+			}
+		}
+
+		buf, err := ioutil.ReadFile(filename)
+		if err != nil {
+			return errors.Errorf("%v: %v", includeChain(next), err)
+		}
+
+		state, err := rules.Parse(string(buf))
+		if err != nil {
+			return errors.Errorf("%v: %v", includeChain(next), err)
+		}
+
+		if len(state.Options) > 0 || len(state.Templates) > 0 || len(state.Samples) > 0 {
+			return errors.Errorf("%v: only fields and includes are allowed in included files", includeChain(next))
+		}
+
+		if err = inc.load(state.Includes, next); err != nil {
+			return err
+		}
+
+		for field, f := range state.Fields {
+			if prev, ok := inc.source[field]; ok && prev != filename {
+				return errors.Errorf("%v: field %v is already defined in %v", includeChain(next), field, prev)
+			}
+
+			inc.fields[field] = f
+			inc.source[field] = filename
+		}
+
+		found := false
+		for _, f := range inc.files {
+			found = found || f == filename
+		}
+
+		if !found {
+			inc.files = append(inc.files, filename)
+		}
+	}
+
+	return nil
+}
+
+// includeFields adds the fields from the files included by the rules file to
+// the state, fields defined in the rules file itself take precedence. The
+// included files are resolved relative to dir (also for nested includes) and
+// may only contain fields and includes. The names of all included files are
+// returned.
+func includeFields(state *rules.State, filename, dir string) ([]string, error) {
+	filename, err := filepath.Abs(filename)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	inc := &includes{
+		dir:    dir,
+		fields: make(map[string]rules.Field),
+		source: make(map[string]string),
+	}
+
+	if err = inc.load(state.Includes, []string{filename}); err != nil {
+		return nil, err
+	}
+
+	for name, f := range inc.fields {
+		if _, ok := state.Fields[name]; !ok {
+			state.Fields[name] = f
+		}
+	}
+
+	return inc.files, nil
+}
@@ -0,0 +1,167 @@
+package erpel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeFiles writes the files with the names relative to dir.
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	for name, data := range files {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filename, []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestInclude(t *testing.T) {
+	dir, err := ioutil.TempDir("", "erpel-include-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"fields/common.fields": "field num {\n  pattern = '\\d+'\n}\n",
+		"fields/mail.fields": "include = 'fields/common.fields'\n" +
+			"field mailaddress {\n  pattern = '[a-z]+@[a-z.]+'\n}\n" +
+			"field session {\n  pattern = '[a-z]{4}'\n}\n",
+		"dovecot": "include = 'fields/mail.fields'\ninclude = 'fields/common.fields'\n" +
+			"field session {\n  pattern = '[A-Z]+'\n}\n" +
+			"---\nlogin {{mailaddress}} session {{session}} bytes={{num}}\n" +
+			"---\nlogin foo@example.com session ABCDEFG bytes=123\n",
+	})
+
+	r, err := ParseRulesFile(nil, filepath.Join(dir, "dovecot"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = r.Check(); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		filepath.Join(dir, "fields", "common.fields"),
+		filepath.Join(dir, "fields", "mail.fields"),
+	}
+	if !reflect.DeepEqual(r.Includes, want) {
+		t.Errorf("wrong includes, want %v, got %v", want, r.Includes)
+	}
+
+	// the field from the rules file takes precedence
+	if r.Match("login foo@example.com session abcd bytes=123") {
+		t.Errorf("included field session was used instead of the local one")
+	}
+}
+
+func TestIncludeSubdirectory(t *testing.T) {
+	dir, err := ioutil.TempDir("", "erpel-include-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"fields/mail.fields": "field num {\n  pattern = '\\d+'\n  template = '123'\n}\n",
+		"mail/dovecot":       "include = 'fields/mail.fields'\n---\nbytes=123\n---\nbytes=42\n",
+	})
+
+	files, err := FindRulesFiles([]string{dir}, FileFilter{Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 1 || files[0].Name != "mail/dovecot" {
+		t.Fatalf("wrong rules files found: %v", files)
+	}
+
+	// includes are relative to the rules directory, not to the subdirectory
+	r, _, _, err := Config{}.LoadRulesFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{filepath.Join(dir, "fields", "mail.fields")}
+	if !reflect.DeepEqual(r.Includes, want) {
+		t.Errorf("wrong includes, want %v, got %v", want, r.Includes)
+	}
+}
+
+var testInvalidIncludes = []struct {
+	files map[string]string
+	err   string
+}{
+	{
+		files: map[string]string{"rules": "include = 'missing.fields'\n"},
+		err:   "rules -> DIR/missing.fields: open",
+	},
+	{
+		files: map[string]string{
+			"rules":    "include = 'a.fields'\n",
+			"a.fields": "include = 'b.fields'\n",
+			"b.fields": "include = 'a.fields'\n",
+		},
+		err: "include cycle: DIR/rules -> DIR/a.fields -> DIR/b.fields -> DIR/a.fields",
+	},
+	{
+		files: map[string]string{
+			"rules":    "include = 'a.fields'\n",
+			"a.fields": "include = 'b.fields'\n",
+			"b.fields": "prefix = 'foo'\n",
+		},
+		err: "DIR/rules -> DIR/a.fields -> DIR/b.fields: only fields and includes are allowed",
+	},
+	{
+		files: map[string]string{
+			"rules":    "include = 'a.fields'\ninclude = 'b.fields'\n",
+			"a.fields": "field num {\n  pattern = '\\d+'\n}\n",
+			"b.fields": "field num {\n  pattern = '[0-9]+'\n}\n",
+		},
+		err: "DIR/rules -> DIR/b.fields: field num is already defined in DIR/a.fields",
+	},
+	{
+		files: map[string]string{
+			"rules":    "include = 'a.fields'\n",
+			"a.fields": "field num {\n",
+		},
+		err: "DIR/rules -> DIR/a.fields: ",
+	},
+}
+
+func TestIncludeInvalid(t *testing.T) {
+	for i, test := range testInvalidIncludes {
+		dir, err := ioutil.TempDir("", "erpel-include-")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		writeFiles(t, dir, test.files)
+
+		_, err = ParseRulesFile(nil, filepath.Join(dir, "rules"))
+		os.RemoveAll(dir)
+
+		if err == nil {
+			t.Errorf("test %d: expected error not found", i)
+			continue
+		}
+
+		msg := strings.Replace(test.err, "DIR", dir, -1)
+		if !strings.Contains(err.Error(), msg) {
+			t.Errorf("test %d: error %q does not contain %q", i, err, msg)
+		}
+	}
+
+	if _, err := ParseRules(nil, "include = 'foo.fields'\n"); err == nil {
+		t.Errorf("include without a rules file: expected error not found")
+	}
+}
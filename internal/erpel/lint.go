@@ -0,0 +1,89 @@
+package erpel
+
+import (
+	"fmt"
+	"unicode"
+)
+
+// minLiteralText is the number of characters (not counting white space) a
+// template must contain verbatim when it includes a wildcard or does not need
+// to match the whole message.
+const minLiteralText = 4
+
+// literalLength returns the number of non-space characters every message
+// matched by the view must contain verbatim.
+func literalLength(rv RuleView) (n int) {
+	for _, item := range rv {
+		switch item := item.(type) {
+		case Text:
+			for _, r := range string(item) {
+				if !unicode.IsSpace(r) {
+					n++
+				}
+			}
+		case AlternationView:
+			min := -1
+			for _, v := range item {
+				l := literalLength(v)
+				if min < 0 || l < min {
+					min = l
+				}
+			}
+			if min > 0 {
+				n += min
+			}
+		case RepeatView:
+			n += literalLength(item.V)
+		}
+	}
+
+	return n
+}
+
+// hasWildcard returns true if the view contains a wildcard.
+func hasWildcard(rv RuleView) bool {
+	for _, item := range rv {
+		switch item := item.(type) {
+		case WildcardView:
+			return true
+		case OptionalView:
+			if hasWildcard(RuleView(item)) {
+				return true
+			}
+		case AlternationView:
+			for _, v := range item {
+				if hasWildcard(v) {
+					return true
+				}
+			}
+		case RepeatView:
+			if hasWildcard(item.V) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Lint returns warnings for templates which are likely to match far more
+// messages than intended, e.g. a wildcard with hardly any text around it.
+func (r *Rules) Lint() (warnings []string) {
+	for _, t := range r.Templates {
+		v, err := r.view(t.Parts)
+		if err != nil {
+			continue
+		}
+
+		if !hasWildcard(v) && r.matchMode(t) == MatchFull {
+			continue
+		}
+
+		if literalLength(v) < minLiteralText {
+			warnings = append(warnings, fmt.Sprintf("template %q (match %v) contains too little text and matches almost everything",
+				t.Text, r.matchMode(t)))
+		}
+	}
+
+	return warnings
+}
@@ -0,0 +1,55 @@
+package erpel
+
+import "testing"
+
+var testLint = []struct {
+	data     string
+	warnings int
+}{
+	{
+		data: `
+----
+foo ... bar
+...
+ab ...
+`,
+		warnings: 2,
+	},
+	{
+		data: `
+match = 'contains'
+----
+err
+connection refused
+@match = 'full'
+x
+`,
+		warnings: 1,
+	},
+	{
+		data: `
+----
+(a|b) (c|de) ...
+@match = 'prefix'
+ab[[cdef]]
+@match = 'prefix'
+abcd{{repeat: x}}
+`,
+		warnings: 2,
+	},
+}
+
+func TestRulesLint(t *testing.T) {
+	for i, test := range testLint {
+		rules, err := ParseRules(nil, test.data)
+		if err != nil {
+			t.Errorf("test %d: parse failed: %v", i, err)
+			continue
+		}
+
+		warnings := rules.Lint()
+		if len(warnings) != test.warnings {
+			t.Errorf("test %d: want %d warnings, got %d: %q", i, test.warnings, len(warnings), warnings)
+		}
+	}
+}
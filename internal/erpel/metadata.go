@@ -0,0 +1,168 @@
+package erpel
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Metadata documents rules files and templates, it does not change how
+// messages are matched. Tags can be used to select groups of rules.
+type Metadata struct {
+	Description string
+	Owner       string
+	References  []string
+	Tags        []string
+}
+
+// validTag matches the allowed names for tags.
+var validTag = validID
+
+// parseStrings returns the strings from value, which is either a list or a
+// single string.
+func parseStrings(value string) ([]string, error) {
+	if strings.HasPrefix(value, "[") {
+		return unquoteList(value)
+	}
+
+	v, err := unquoteString(value)
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{v}, nil
+}
+
+// set sets the metadata for key from the (still quoted) value. If key is not
+// a metadata key, false is returned.
+func (m *Metadata) set(key, value string) (bool, error) {
+	switch key {
+	case "description", "owner":
+		if strings.HasPrefix(value, "[") {
+			return true, errors.Errorf("%v needs a single string", key)
+		}
+
+		v, err := unquoteString(value)
+		if err != nil {
+			return true, errors.WithMessage(err, value)
+		}
+
+		if key == "description" {
+			m.Description = v
+		} else {
+			m.Owner = v
+		}
+	case "reference":
+		list, err := parseStrings(value)
+		if err != nil {
+			return true, errors.WithMessage(err, value)
+		}
+		m.References = append(m.References, list...)
+	case "tags":
+		list, err := parseStrings(value)
+		if err != nil {
+			return true, errors.WithMessage(err, value)
+		}
+
+		for _, tag := range list {
+			if !validTag.MatchString(tag) {
+				return true, errors.Errorf("invalid tag %q", tag)
+			}
+			m.Tags = append(m.Tags, tag)
+		}
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// Tags returns the tags of the template together with the tags of the rules
+// file, sorted and without duplicates.
+func (r *Rules) Tags(t Template) []string {
+	seen := make(map[string]struct{})
+	var tags []string
+	for _, list := range [][]string{r.Metadata.Tags, t.Metadata.Tags} {
+		for _, tag := range list {
+			if _, ok := seen[tag]; ok {
+				continue
+			}
+
+			seen[tag] = struct{}{}
+			tags = append(tags, tag)
+		}
+	}
+	sort.Strings(tags)
+
+	return tags
+}
+
+// TagFilter selects templates by their tags.
+type TagFilter struct {
+	// Tags lists the tags of which a template needs to have at least one,
+	// all templates are selected if it is empty.
+	Tags []string
+
+	// Exclude lists tags which deselect a template.
+	Exclude []string
+}
+
+// Empty returns true if the filter selects all templates.
+func (f TagFilter) Empty() bool {
+	return len(f.Tags) == 0 && len(f.Exclude) == 0
+}
+
+// contains returns true if one of the tags is in list.
+func contains(list, tags []string) bool {
+	for _, tag := range tags {
+		for _, s := range list {
+			if s == tag {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// Selects returns true if a template with the tags is selected.
+func (f TagFilter) Selects(tags []string) bool {
+	if len(f.Tags) > 0 && !contains(f.Tags, tags) {
+		return false
+	}
+
+	return !contains(f.Exclude, tags)
+}
+
+// Select removes the templates which are not selected by the filter and
+// returns the number of templates removed. Templates of a sequence need to be
+// selected together.
+func (r *Rules) Select(f TagFilter) (removed int, err error) {
+	if f.Empty() {
+		return 0, nil
+	}
+
+	var templates []Template
+	for _, t := range r.Templates {
+		if !f.Selects(r.Tags(t)) {
+			removed++
+			continue
+		}
+
+		templates = append(templates, t)
+	}
+
+	if removed == 0 {
+		return 0, nil
+	}
+
+	r.Templates = templates
+	r.partial = true
+	r.compiled = nil
+	if err = r.compile(); err != nil {
+		return 0, err
+	}
+
+	return removed, nil
+}
@@ -0,0 +1,114 @@
+package erpel
+
+import (
+	"reflect"
+	"testing"
+)
+
+const testMetadataRules = `
+description = 'mail delivery'
+owner = 'postmaster@example.com'
+reference = ['https://wiki.example.com/mail', 'RFC 5321']
+tags = ['mail', 'smtp']
+---
+@tags = 'auth'
+@description = 'login'
+@reference = 'https://wiki.example.com/login'
+login ok
+@tags = ['debug', 'mail']
+debug message
+delivered
+`
+
+func TestMetadata(t *testing.T) {
+	r, err := ParseRules(nil, testMetadataRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Metadata{
+		Description: "mail delivery",
+		Owner:       "postmaster@example.com",
+		References:  []string{"https://wiki.example.com/mail", "RFC 5321"},
+		Tags:        []string{"mail", "smtp"},
+	}
+	if !reflect.DeepEqual(r.Metadata, want) {
+		t.Errorf("wrong metadata for rules, want:\n  %#v\ngot:\n  %#v", want, r.Metadata)
+	}
+
+	tmpl := r.Templates[0].Metadata
+	if tmpl.Description != "login" || !reflect.DeepEqual(tmpl.References, []string{"https://wiki.example.com/login"}) {
+		t.Errorf("wrong metadata for template: %#v", tmpl)
+	}
+
+	var tests = []struct {
+		tags []string
+	}{
+		{[]string{"auth", "mail", "smtp"}},
+		{[]string{"debug", "mail", "smtp"}},
+		{[]string{"mail", "smtp"}},
+	}
+
+	for i, test := range tests {
+		tags := r.Tags(r.Templates[i])
+		if !reflect.DeepEqual(tags, test.tags) {
+			t.Errorf("test %d: wrong tags, want %v, got %v", i, test.tags, tags)
+		}
+	}
+}
+
+func TestMetadataInvalid(t *testing.T) {
+	for i, data := range []string{
+		"tags = 'a b'\n---\nfoo\n",
+		"---\n@tags = ['ok', 'a/b']\nfoo\n",
+		"owner = ['a', 'b']\n---\nfoo\n",
+	} {
+		if _, err := ParseRules(nil, data); err == nil {
+			t.Errorf("test %d: expected error not found", i)
+		}
+	}
+}
+
+var testSelect = []struct {
+	filter  TagFilter
+	removed int
+	match   []string
+}{
+	{TagFilter{}, 0, []string{"login ok", "debug message", "delivered"}},
+	{TagFilter{Tags: []string{"mail"}}, 0, []string{"login ok", "debug message", "delivered"}},
+	{TagFilter{Tags: []string{"auth"}}, 2, []string{"login ok"}},
+	{TagFilter{Tags: []string{"auth", "debug"}}, 1, []string{"login ok", "debug message"}},
+	{TagFilter{Exclude: []string{"debug"}}, 1, []string{"login ok", "delivered"}},
+	{TagFilter{Tags: []string{"mail"}, Exclude: []string{"auth", "debug"}}, 2, []string{"delivered"}},
+	{TagFilter{Tags: []string{"web"}}, 3, nil},
+}
+
+func TestSelect(t *testing.T) {
+	for i, test := range testSelect {
+		r, err := ParseRules(nil, testMetadataRules)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		removed, err := r.Select(test.filter)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+
+		if removed != test.removed {
+			t.Errorf("test %d: wrong number of removed templates, want %d, got %d", i, test.removed, removed)
+		}
+
+		var matched []string
+		for _, s := range []string{"login ok", "debug message", "delivered"} {
+			if r.Match(s) {
+				matched = append(matched, s)
+			}
+		}
+
+		if !reflect.DeepEqual(matched, test.match) {
+			t.Errorf("test %d: wrong messages matched, want %v, got %v", i, test.match, matched)
+		}
+	}
+}
@@ -0,0 +1,345 @@
+package erpel
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Override changes the rules loaded from a rules file without editing it,
+// e.g. for rules files installed by a package.
+type Override struct {
+	// Source is the configuration file the override is defined in.
+	Source string
+
+	// Disable is set if the whole rules file is disabled.
+	Disable bool
+
+	// DisableTemplates lists the IDs of the templates which are disabled.
+	DisableTemplates []string
+
+	// Templates are added to the rules.
+	Templates []string
+
+	// Patterns replace the patterns of fields, indexed by field name.
+	Patterns map[string]string
+}
+
+// patternPrefix is the prefix for keys in an override section which replace
+// the pattern of a field.
+const patternPrefix = "pattern."
+
+// parseOverride returns the override from the data.
+func parseOverride(data map[string]string) (o Override, err error) {
+	for key, value := range data {
+		if strings.HasPrefix(key, patternPrefix) {
+			v, err := unquoteString(value)
+			if err != nil {
+				return o, errors.WithMessage(err, value)
+			}
+
+			if o.Patterns == nil {
+				o.Patterns = make(map[string]string)
+			}
+			o.Patterns[strings.TrimPrefix(key, patternPrefix)] = v
+			continue
+		}
+
+		switch key {
+		case "disable":
+			v, err := unquoteString(value)
+			if err != nil {
+				return o, errors.WithMessage(err, value)
+			}
+
+			o.Disable, err = strconv.ParseBool(v)
+			if err != nil {
+				return o, errors.Errorf("invalid value %q for disable", v)
+			}
+		case "disable_templates":
+			o.DisableTemplates, err = unquoteList(value)
+			if err != nil {
+				return o, errors.WithMessage(err, value)
+			}
+		case "templates":
+			o.Templates, err = unquoteList(value)
+			if err != nil {
+				return o, errors.WithMessage(err, value)
+			}
+		default:
+			return o, errors.Errorf("unknown key %q", key)
+		}
+	}
+
+	return o, nil
+}
+
+// name returns the name of the rules file which is used to refer to it in
+// template IDs and overrides.
+func (r *Rules) name() string {
+	if r.Name != "" {
+		return r.Name
+	}
+
+	return filepath.Base(r.Filename)
+}
+
+// matchID returns true if the template has the ID, the name of the rules file
+// may be omitted from the ID.
+func matchID(name string, t Template, id string) bool {
+	return id == t.localID() || id == name+"/"+t.localID()
+}
+
+// apply changes the rules as configured by the override. Descriptions of the
+// changes are returned.
+func (o Override) apply(r *Rules) (notes []string, err error) {
+	name := r.name()
+
+	if len(o.DisableTemplates) > 0 {
+		var templates []Template
+		for _, t := range r.Templates {
+			if o.disables(name, t) {
+				notes = append(notes, fmt.Sprintf("template %v (%v) disabled", r.TemplateID(t), r.Describe(t)))
+				continue
+			}
+
+			templates = append(templates, t)
+		}
+
+		for _, id := range o.DisableTemplates {
+			found := false
+			for _, t := range r.Templates {
+				found = found || matchID(name, t, id)
+			}
+
+			if !found {
+				return nil, errors.Errorf("template with ID %q not found", id)
+			}
+		}
+
+		r.partial = r.partial || len(templates) < len(r.Templates)
+		r.Templates = templates
+	}
+
+	for _, s := range o.Templates {
+		t, err := ParseTemplate(s)
+		if err != nil {
+			return nil, errors.WithMessage(err, s)
+		}
+
+		r.Templates = append(r.Templates, t)
+		notes = append(notes, fmt.Sprintf("template %q added", s))
+	}
+
+	names := make([]string, 0, len(o.Patterns))
+	for field := range o.Patterns {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	for _, field := range names {
+		if err := r.replacePattern(field, o.Patterns[field]); err != nil {
+			return nil, err
+		}
+		notes = append(notes, fmt.Sprintf("pattern of field %v replaced with %q", field, o.Patterns[field]))
+	}
+
+	// fields composed of replaced fields need to be resolved again
+	for name, f := range r.Fields {
+		if f.Expression != "" {
+			f.Pattern = nil
+			r.Fields[name] = f
+		}
+	}
+
+	if err = resolveFields(r.Fields, r.GlobalFields); err != nil {
+		return nil, err
+	}
+
+	r.compiled = nil
+	if err = r.compile(); err != nil {
+		return nil, err
+	}
+
+	return notes, nil
+}
+
+// disables returns true if the template is disabled by the override.
+func (o Override) disables(name string, t Template) bool {
+	for _, id := range o.DisableTemplates {
+		if matchID(name, t, id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// disabledSamples returns the samples of the rules which are only matched by
+// templates disabled by the override.
+func (o Override) disabledSamples(r *Rules) map[string]bool {
+	if len(o.DisableTemplates) == 0 {
+		return nil
+	}
+
+	name := r.name()
+	templates := r.templates()
+	samples := make(map[string]bool)
+	for _, sample := range r.Samples {
+		if !r.matchPrefix(sample) {
+			continue
+		}
+
+		disabled, enabled := false, false
+		for i, ct := range templates {
+			if _, ok := ct.match(sample, false); !ok {
+				continue
+			}
+
+			if o.disables(name, r.Templates[i]) {
+				disabled = true
+			} else {
+				enabled = true
+			}
+		}
+
+		if disabled && !enabled {
+			samples[sample] = true
+		}
+	}
+
+	return samples
+}
+
+// replacePattern replaces the pattern of the field. Global fields are copied
+// to the local fields of the rules before.
+func (r *Rules) replacePattern(name, pattern string) error {
+	f, ok := r.Fields[name]
+	if !ok {
+		if f, ok = r.GlobalFields[name]; !ok {
+			return errors.Errorf("field %q not found", name)
+		}
+	}
+
+	f.Pattern, f.Expression = nil, ""
+	if fieldReference.MatchString(pattern) {
+		f.Expression = pattern
+	} else {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return errors.WithMessage(err, pattern)
+		}
+
+		f.Pattern = re
+		if err = f.Check(); err != nil {
+			return errors.Errorf("field %v: %v", name, err)
+		}
+	}
+
+	r.Fields[name] = f
+	return nil
+}
+
+// disabled returns true if the rules file with the name is disabled by an
+// override, which is described in note.
+func (c Config) disabled(name string) (note string, disabled bool) {
+	o, ok := c.Overrides[name]
+	if !ok || !o.Disable {
+		return "", false
+	}
+
+	return fmt.Sprintf("rules file %v disabled by %v", name, o.Source), true
+}
+
+// ApplyOverride applies the override configured for the rules, if any. The
+// changes made are described in notes, disabled is set if the whole rules
+// file is disabled. Samples which are only matched by disabled templates are
+// removed, the rules need to be checked afterwards.
+func (c Config) ApplyOverride(r *Rules) (notes []string, disabled bool, err error) {
+	name := r.name()
+	if note, ok := c.disabled(name); ok {
+		return []string{note}, true, nil
+	}
+
+	o, ok := c.Overrides[name]
+	if !ok {
+		return nil, false, nil
+	}
+
+	// samples of disabled templates are expected to not match any more
+	skip := o.disabledSamples(r)
+
+	notes, err = o.apply(r)
+	if err != nil {
+		return nil, false, errors.Errorf("override for %v in %v: %v", name, o.Source, err)
+	}
+
+	if len(skip) > 0 {
+		var samples []string
+		for _, sample := range r.Samples {
+			if !skip[sample] {
+				samples = append(samples, sample)
+			}
+		}
+
+		notes = append(notes, fmt.Sprintf("%d samples of disabled templates are skipped", len(r.Samples)-len(samples)))
+		r.Samples = samples
+	}
+
+	for i := range notes {
+		notes[i] = fmt.Sprintf("%v (%v)", notes[i], o.Source)
+	}
+
+	return notes, false, nil
+}
+
+// LoadRulesFile parses the rules file, applies the override configured for it
+// and checks the rules afterwards. Included files are resolved relative to
+// the rules directory. A file disabled by an override is not
+// parsed at all, disabled is set and the override is described in notes like
+// the changes made otherwise.
+func (c Config) LoadRulesFile(f RulesFile) (r Rules, notes []string, disabled bool, err error) {
+	if note, ok := c.disabled(f.Name); ok {
+		return Rules{}, []string{note}, true, nil
+	}
+
+	r, err = parseRulesFile(c.Fields, f.Filename, f.Dir)
+	if err != nil {
+		return Rules{}, nil, false, err
+	}
+	r.Name = f.Name
+
+	notes, _, err = c.ApplyOverride(&r)
+	if err != nil {
+		return Rules{}, nil, false, err
+	}
+
+	if err = r.Check(); err != nil {
+		return Rules{}, nil, false, err
+	}
+
+	return r, notes, false, nil
+}
+
+// UnusedOverrides returns the names of the rules files which have an
+// override, but are not within the rules files.
+func (c Config) UnusedOverrides(files []RulesFile) (names []string) {
+	used := make(map[string]struct{}, len(files))
+	for _, f := range files {
+		used[f.Name] = struct{}{}
+	}
+
+	for name := range c.Overrides {
+		if _, ok := used[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return names
+}
@@ -0,0 +1,303 @@
+package erpel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+const testOverrideRules = `field num {
+	template = '123'
+	pattern = '\d+'
+}
+
+field pair {
+	pattern = '{{num}}/{{num}}'
+	template = '1/2'
+}
+
+---
+@id = 'connect'
+connect from 123
+@id = 'bytes'
+transferred 1/2 bytes
+disconnect
+
+---
+connect from 12345
+transferred 1/2 bytes
+disconnect
+`
+
+func TestApplyOverride(t *testing.T) {
+	cfg, err := ParseConfig(`
+override 'dovecot' {
+	disable_templates = ['dovecot/connect']
+	templates = ['timeout after {{num}} seconds']
+	pattern.num = '\d{1,3}'
+}
+
+override 'postfix' {
+	disable = 'true'
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules, err := ParseRules(nil, testOverrideRules)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "/etc/erpel/rules.d/dovecot"
+
+	notes, disabled, err := cfg.ApplyOverride(&rules)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if disabled {
+		t.Fatalf("rules are disabled")
+	}
+
+	want := []string{
+		"template dovecot/connect (/etc/erpel/rules.d/dovecot:13: connect from 123) disabled ()",
+		`template "timeout after {{num}} seconds" added ()`,
+		`pattern of field num replaced with "\\d{1,3}" ()`,
+		"1 samples of disabled templates are skipped ()",
+	}
+
+	if !reflect.DeepEqual(notes, want) {
+		t.Errorf("wrong notes returned:\n  want %q\n   got %q", want, notes)
+	}
+
+	if err = rules.Check(); err != nil {
+		t.Errorf("check failed after the override: %v", err)
+	}
+
+	var tests = []struct {
+		line  string
+		match bool
+	}{
+		{"connect from 12", false},
+		{"timeout after 10 seconds", true},
+		{"timeout after 1000 seconds", false},
+		// the composed field uses the replaced pattern
+		{"transferred 100/200 bytes", true},
+		{"transferred 1000/200 bytes", false},
+		{"disconnect", true},
+	}
+
+	for i, test := range tests {
+		if rules.Match(test.line) != test.match {
+			t.Errorf("test %d: %q: want match %v", i, test.line, test.match)
+		}
+	}
+
+	other, err := ParseRules(nil, "---\nfoo\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Filename = "postfix"
+
+	if _, disabled, _ := cfg.ApplyOverride(&other); !disabled {
+		t.Errorf("rules file postfix is not disabled")
+	}
+
+	unused := cfg.UnusedOverrides([]RulesFile{{Name: "dovecot", Filename: "/etc/erpel/rules.d/dovecot"}})
+	if !reflect.DeepEqual(unused, []string{"postfix"}) {
+		t.Errorf("wrong unused overrides returned: %v", unused)
+	}
+}
+
+func TestLoadRulesFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "erpel-override-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	writeFiles(t, dir, map[string]string{
+		"broken":       "field num {\n",
+		"dovecot":      "field num {\n  template = '123'\n  pattern = '[0-9]{3}'\n}\n---\nconnect from 123\n---\nconnect from 12345\n",
+		"postfix":      "---\nfoo\n---\nbar\n",
+		"mail/dovecot": "---\n@id = 'login'\nlogin\n---\nlogin\n",
+		"imap":         "field num {\n  template = '7'\n  pattern = '\\d+'\n}\n---\n@id = 'foo'\nfoo 7\nbar 7\n---\nfoo 1\nbar 3\n",
+	})
+
+	cfg, err := ParseConfig(`
+override 'broken' {
+	disable = 'true'
+}
+
+override 'dovecot' {
+	pattern.num = '\d+'
+}
+
+override 'mail/dovecot' {
+	disable_templates = ['mail/dovecot/login']
+}
+
+override 'imap' {
+	disable_templates = ['foo']
+	pattern.num = '[a-z]+'
+}
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := FindRulesFiles([]string{dir}, FileFilter{Recursive: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	load := func(name string) (Rules, []string, bool, error) {
+		for _, f := range files {
+			if f.Name == name {
+				return cfg.LoadRulesFile(f)
+			}
+		}
+
+		t.Fatalf("rules file %v not found", name)
+		return Rules{}, nil, false, nil
+	}
+
+	// disabled files are not parsed
+	_, notes, disabled, err := load("broken")
+	if err != nil || !disabled || len(notes) != 1 {
+		t.Errorf("broken rules file not disabled: %v %v %v", notes, disabled, err)
+	}
+
+	// the samples are checked after the pattern has been replaced
+	r, _, disabled, err := load("dovecot")
+	if err != nil || disabled {
+		t.Errorf("loading rules file failed: %v %v", disabled, err)
+	}
+
+	if !r.Match("connect from 12345") {
+		t.Errorf("override has not been applied")
+	}
+
+	if _, _, _, err = load("postfix"); err == nil {
+		t.Errorf("expected error for sample which does not match not found")
+	}
+
+	// files in subdirectories are referred to by the relative path
+	r, notes, _, err = load("mail/dovecot")
+	if err != nil || len(notes) != 2 || len(r.Templates) != 0 || len(r.Samples) != 0 {
+		t.Errorf("override for mail/dovecot not applied: %v %v %v", notes, r.Templates, err)
+	}
+
+	// only the samples of disabled templates are skipped, the others are
+	// still checked
+	if _, notes, _, err = load("imap"); err == nil {
+		t.Errorf("expected error for sample broken by the override not found, notes %v", notes)
+	}
+
+	if unused := cfg.UnusedOverrides(files); len(unused) != 0 {
+		t.Errorf("unexpected unused overrides: %v", unused)
+	}
+}
+
+var testInvalidOverrides = []string{
+	`override 'dovecot' { disable_templates = ['unknown'] }`,
+	`override 'dovecot' { templates = ['foo {{unknown}}'] }`,
+	`override 'dovecot' { pattern.num = '(' }`,
+	`override 'dovecot' { pattern.num = '{{unknown}}' }`,
+	`override 'dovecot' { pattern.unknown = 'x' }`,
+}
+
+func TestApplyOverrideInvalid(t *testing.T) {
+	for i, data := range testInvalidOverrides {
+		cfg, err := ParseConfig(data)
+		if err != nil {
+			t.Errorf("test %d: parse failed: %v", i, err)
+			continue
+		}
+
+		rules, err := ParseRules(nil, testOverrideRules)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rules.Filename = "dovecot"
+
+		if _, _, err = cfg.ApplyOverride(&rules); err == nil {
+			t.Errorf("test %d: expected error not found", i)
+		}
+	}
+}
+
+var testInvalidOverrideConfigs = []string{
+	`override 'dovecot' { foo = 'bar' }`,
+	`override 'dovecot' { disable = 'maybe' }`,
+}
+
+func TestParseOverrideInvalid(t *testing.T) {
+	for i, data := range testInvalidOverrideConfigs {
+		if _, err := ParseConfig(data); err == nil {
+			t.Errorf("test %d: expected error not found", i)
+		}
+	}
+}
+
+func TestOverrideDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "erpel-override-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	overrideDir := filepath.Join(dir, "override.d")
+	if err = os.Mkdir(overrideDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"erpel.conf":             "override_dir = '" + overrideDir + "'\noverride 'dovecot' { disable = 'true' }\n",
+		"override.d/postfix":     "override 'postfix' { disable = 'true' }\n",
+		"override.d/.hidden":     "foo = 'bar'\n",
+		"override.d/postfix-old": "# nothing\n",
+	}
+
+	for name, data := range files {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(data), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cfg, err := ParseConfigFile(filepath.Join(dir, "erpel.conf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if o := cfg.Overrides["dovecot"]; !o.Disable || o.Source != filepath.Join(dir, "erpel.conf") {
+		t.Errorf("wrong override for dovecot: %+v", o)
+	}
+
+	if o := cfg.Overrides["postfix"]; !o.Disable || o.Source != filepath.Join(overrideDir, "postfix") {
+		t.Errorf("wrong override for postfix: %+v", o)
+	}
+
+	// overrides may only be defined once
+	err = ioutil.WriteFile(filepath.Join(overrideDir, "dovecot"), []byte("override 'dovecot' { disable = 'false' }\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ParseConfigFile(filepath.Join(dir, "erpel.conf")); err == nil {
+		t.Errorf("expected error for duplicate override not found")
+	}
+
+	// other options are not allowed in the override_dir
+	err = ioutil.WriteFile(filepath.Join(overrideDir, "dovecot"), []byte("state_dir = '/tmp'\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ParseConfigFile(filepath.Join(dir, "erpel.conf")); err == nil {
+		t.Errorf("expected error for options in the override_dir not found")
+	}
+}
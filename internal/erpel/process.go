@@ -2,18 +2,83 @@ package erpel
 
 import (
 	"bufio"
+	"fmt"
 	"io"
 	"os"
 	"strings"
+	"time"
 )
 
+// Line is a log message handed to a HandleFunc.
+type Line struct {
+	// Number is the line number within the processed data, starting at one.
+	Number int
+
+	Text string
+
+	// Context is set for lines which have been matched by the rules, but are
+	// passed on because they are close to a line which has not been matched.
+	Context bool
+
+	// Action is ActionReport or ActionAlert for lines which are passed on
+	// because they are matched by report or alert rules, Rule describes
+	// the matching template in this case.
+	Action Action
+	Rule   string
+
+	// Category and Severity classify the line. Unmatched lines have the
+	// category DefaultCategory, context lines the classification of the
+	// line they are shown for.
+	Category string
+	Severity string
+
+	// Summary is set for lines which are reported because a threshold has
+	// been exceeded or a sequence has not been completed, the line itself
+	// is a sample. Like Text, it may contain values from the log message.
+	Summary string
+}
+
 // HandleFunc handles lines than have not been filtered out by any rules.
-type HandleFunc func(lines []string) error
+type HandleFunc func(lines []Line) error
+
+// Options control how log messages are processed.
+type Options struct {
+	// Before and After configure the number of lines around each unmatched
+	// line which are passed on as context, regardless of whether or not
+	// they are matched by any rule.
+	Before, After int
+
+	// Charset is the character set of the input, lines are converted to
+	// UTF-8 before matching. UTF-8 is assumed when it is empty.
+	Charset string
+
+	// Counters keep track of messages matched by templates with a
+	// threshold. If it is nil, messages are only counted within a single
+	// call to Process.
+	Counters *Counters
+
+	// Correlations keep track of sequences which have been started. If it
+	// is nil, sequences need to be completed within a single call to
+	// Process.
+	Correlations *Correlations
+
+	// Expectations record when messages matched by templates with a
+	// schedule have been seen. If it is nil, they are not recorded.
+	Expectations *Expectations
+
+	// Now returns the current time, time.Now is used if it is nil.
+	Now func() time.Time
+
+	// Source names the log the data is read from. Windows of sequences
+	// given as a number of lines count the lines of the source the sequence
+	// was started in. ProcessFile uses the file name if it is empty.
+	Source string
+}
 
 // ProcessFile extracts all log messages starting at the marker from the file
 // by opening it and calling Process(). Returned is a marker for the last
 // position within the file.
-func ProcessFile(rules []Rules, filename string, last Marker, fn HandleFunc) (m Marker, err error) {
+func ProcessFile(rules []Rules, filename string, last Marker, opts Options, fn HandleFunc) (m Marker, err error) {
 	var fd *os.File
 
 	fd, err = os.Open(filename)
@@ -32,7 +97,11 @@ func ProcessFile(rules []Rules, filename string, last Marker, fn HandleFunc) (m
 		}
 	}()
 
-	err = Process(rules, fd, fn)
+	if opts.Source == "" {
+		opts.Source = filename
+	}
+
+	err = Process(rules, fd, opts, fn)
 	if err != nil {
 		return Marker{}, err
 	}
@@ -42,38 +111,297 @@ func ProcessFile(rules []Rules, filename string, last Marker, fn HandleFunc) (m
 
 const handleBatchSize = 20
 
+// contextBuffer keeps the last lines matched by the rules, so that they can
+// be passed on when an unmatched line is found.
+type contextBuffer struct {
+	lines []Line
+	max   int
+}
+
+func (b *contextBuffer) add(l Line) {
+	if b.max == 0 {
+		return
+	}
+
+	if len(b.lines) == b.max {
+		copy(b.lines, b.lines[1:])
+		b.lines = b.lines[:len(b.lines)-1]
+	}
+
+	b.lines = append(b.lines, l)
+}
+
+// flush returns all buffered lines and empties the buffer.
+func (b *contextBuffer) flush() []Line {
+	lines := b.lines
+	b.lines = nil
+	return lines
+}
+
+// matcher matches lines against rules.
+type matcher struct {
+	rules        []Rules
+	counters     *Counters
+	correlations *Correlations
+	expectations *Expectations
+	now          func() time.Time
+	source       string
+
+	// found holds the matching template of each stateful rules file for
+	// the current line
+	found []ruleMatch
+
+	// reports collects lines for incomplete sequences found while matching
+	reports []Line
+}
+
+// ruleMatch is a template matching a line together with the values and the
+// time of the message.
+type ruleMatch struct {
+	ok     bool
+	t      Template
+	values Values
+	ts     time.Time
+}
+
+// match sets the action for the line, alert rules are tried before report
+// rules, which are tried before ignore rules. For alert and report rules, the
+// matching template is described and the line is classified, unmatched lines
+// have the category DefaultCategory. If the line is not matched at all, false
+// is returned.
+//
+// Thresholds, steps of sequences and expected messages are tracked for all
+// rules matching the line, not only for the one deciding the action. Ignored
+// lines are reported with a summary when a threshold is exceeded.
+func (m *matcher) match(line *Line) bool {
+	m.correlations.Lines[m.source]++
+	now := m.now()
+
+	for i := range m.rules {
+		r := &m.rules[i]
+		f := &m.found[i]
+		*f = ruleMatch{}
+		if r.stateful {
+			f.t, f.values, f.ts, f.ok = r.matchAt(line.Text, now)
+		}
+	}
+
+	if !m.classify(line, now) {
+		line.Category = DefaultCategory
+		return false
+	}
+
+	for i := range m.rules {
+		r, f := &m.rules[i], m.found[i]
+		if !f.ok {
+			continue
+		}
+
+		if f.t.Expect != nil && m.expectations != nil {
+			m.expectations.seen(expectKey(r, f.t), f.ts)
+		}
+
+		if r.Action != ActionIgnore {
+			continue
+		}
+
+		if f.t.Sequence != "" {
+			m.reports = append(m.reports, m.checkSequence(r, f, line)...)
+		}
+		m.checkThreshold(r, f, line)
+	}
+
+	return true
+}
+
+// classify sets the action for the line from the first rules matching it.
+func (m *matcher) classify(line *Line, now time.Time) bool {
+	for _, a := range []Action{ActionAlert, ActionReport, ActionIgnore} {
+		for i := range m.rules {
+			r := &m.rules[i]
+			if r.Action != a {
+				continue
+			}
+
+			var f ruleMatch
+			switch {
+			case r.stateful:
+				f = m.found[i]
+			case a == ActionIgnore:
+				f.ok = r.Match(line.Text)
+			default:
+				f.t, f.values, f.ts, f.ok = r.matchAt(line.Text, now)
+			}
+
+			if !f.ok {
+				continue
+			}
+
+			line.Action = a
+			if a != ActionIgnore {
+				line.Rule = r.Describe(f.t)
+				line.Category, line.Severity = r.Classify(f.t)
+			}
+			return true
+		}
+	}
+
+	return false
+}
+
+// checkThreshold counts the line matched by the template of an ignore rule at
+// the time of the message. When the threshold is exceeded and the line is
+// ignored otherwise, it is reported with a summary.
+func (m *matcher) checkThreshold(r *Rules, f ruleMatch, line *Line) {
+	t := f.t
+	if t.Threshold == nil {
+		return
+	}
+
+	group := f.values.Get(t.GroupBy)
+	count, report := m.counters.add(thresholdKey(r, t, group), *t.Threshold, f.ts)
+	if !report || line.Action != ActionIgnore {
+		return
+	}
+
+	line.Action = ActionReport
+	line.Rule = r.Describe(t)
+	line.Category, line.Severity = r.Classify(t)
+	line.Summary = fmt.Sprintf("threshold exceeded, %d messages within %v", count, t.Threshold.Window)
+	if t.GroupBy != "" {
+		line.Summary += fmt.Sprintf(" for %v=%v", t.GroupBy, group)
+	}
+}
+
 // Process extracts all log messages from the reader, ignores those matched by
-// the rules and hands the remaining lines to f. When f returns an error,
+// the rules and hands the remaining lines to f. Lines matched by report or
+// alert rules are always handed to f. When f returns an error,
 // processing stops and this error is returned. Empty lines are always ignored.
-func Process(rules []Rules, rd io.Reader, f HandleFunc) error {
+// Lines close to unmatched lines are passed on as context as configured by
+// opts, each line is passed on at most once. Sequences which have not been
+// completed within their window are reported at the end.
+func Process(rules []Rules, rd io.Reader, opts Options, f HandleFunc) error {
+	cs, err := lookupCharset(opts.Charset)
+	if err != nil {
+		return err
+	}
+
+	m := &matcher{
+		rules:        rules,
+		counters:     opts.Counters,
+		correlations: opts.Correlations,
+		expectations: opts.Expectations,
+		now:          opts.Now,
+		source:       opts.Source,
+		found:        make([]ruleMatch, len(rules)),
+	}
+	if m.counters == nil {
+		m.counters = NewCounters()
+	}
+	if m.correlations == nil {
+		m.correlations = NewCorrelations()
+	}
+
+	// line numbers of sequences started before refer to other data
+	for _, p := range m.correlations.Pending {
+		p.number = 0
+	}
+
+	m.counters.migrate(rules)
+	m.correlations.migrate(rules)
+	if m.expectations != nil {
+		m.expectations.migrate(rules)
+	}
+	if m.now == nil {
+		m.now = time.Now
+	}
+
 	sc := bufio.NewScanner(rd)
 
-	var resultLines []string
+	var (
+		resultLines []Line
+		num         int
+		after       int
+
+		// last line which has been passed on, not counting context
+		last Line
+	)
+
+	before := contextBuffer{max: opts.Before}
+
+	emit := func(l Line) error {
+		resultLines = append(resultLines, l)
+
+		if len(resultLines) >= handleBatchSize {
+			err := f(resultLines)
+			if err != nil {
+				return err
+			}
+
+			resultLines = resultLines[:0]
+		}
+
+		return nil
+	}
 
 nextLine:
 	for sc.Scan() {
-		line := strings.TrimSpace(sc.Text())
+		num++
 
-		// ignore empty lines
-		if line == "" {
-			continue nextLine
+		text := sc.Text()
+		if cs != nil {
+			text = cs.decode(sc.Bytes())
 		}
 
-		for _, rule := range rules {
-			if rule.Match(line) {
-				continue nextLine
+		line := Line{
+			Number: num,
+			Text:   strings.TrimSpace(text),
+		}
+
+		matched := line.Text != "" && m.match(&line)
+
+		for _, l := range m.reports {
+			if err := emit(l); err != nil {
+				return err
 			}
 		}
+		m.reports = m.reports[:0]
 
-		resultLines = append(resultLines, line)
+		// empty lines are always ignored
+		if line.Text == "" || (matched && line.Action == ActionIgnore) {
+			line.Context = true
 
-		if len(resultLines) >= handleBatchSize {
-			err := f(resultLines)
-			if err != nil {
+			if after > 0 {
+				after--
+				line.Category, line.Severity = last.Category, last.Severity
+				if err := emit(line); err != nil {
+					return err
+				}
+				continue nextLine
+			}
+
+			before.add(line)
+			continue nextLine
+		}
+
+		for _, l := range before.flush() {
+			l.Category, l.Severity = line.Category, line.Severity
+			if err := emit(l); err != nil {
 				return err
 			}
+		}
 
-			resultLines = resultLines[:0]
+		if err := emit(line); err != nil {
+			return err
+		}
+
+		after = opts.After
+		last = line
+	}
+
+	for _, l := range m.expireSequences() {
+		if err := emit(l); err != nil {
+			return err
 		}
 	}
 
@@ -1,12 +1,17 @@
 package erpel
 
 import (
+	"fmt"
+	"reflect"
+	"regexp"
 	"strings"
 	"testing"
+	"time"
 )
 
 var processTests = []struct {
 	rules  []Rules
+	opts   Options
 	data   string
 	result string
 }{
@@ -24,9 +29,9 @@ log message`,
 	{
 		rules: []Rules{
 			Rules{
-				Templates: []string{
+				Templates: templates(
 					"foobar",
-				},
+				),
 			},
 		},
 		data: `foobar
@@ -37,17 +42,94 @@ log message`,
 bump
 log message`,
 	},
+	{
+		rules: []Rules{
+			Rules{
+				Templates: templates(
+					"foo",
+					"bar",
+				),
+			},
+		},
+		opts: Options{Before: 1, After: 1},
+		data: `foo
+bar
+baz
+foo
+bar
+bar
+foo
+bump
+bar
+log message
+foo`,
+		result: `- bar
+: baz
+- foo
+- foo
+: bump
+- bar
+: log message
+- foo`,
+	},
+	{
+		rules: []Rules{
+			Rules{
+				Templates: templates(
+					"foo",
+				),
+			},
+		},
+		opts: Options{Before: 2},
+		data: `foo
+foo
+
+foo
+baz
+foo`,
+		result: `- 
+- foo
+: baz`,
+	},
+	{
+		rules: []Rules{
+			Rules{
+				Templates: templates(
+					"grüße",
+				),
+			},
+		},
+		opts:   Options{Charset: "latin1"},
+		data:   "gr\xfc\xdfe\ncaf\xe9\n",
+		result: "café",
+	},
+}
+
+// formatLine marks context lines like grep does.
+func formatLine(l Line) string {
+	if l.Context {
+		return "- " + l.Text
+	}
+
+	return ": " + l.Text
 }
 
 func TestProcess(t *testing.T) {
 	for i, test := range processTests {
 		var res []string
-		handler := func(lines []string) error {
-			res = append(res, lines...)
+		handler := func(lines []Line) error {
+			for _, l := range lines {
+				if test.opts.Before > 0 || test.opts.After > 0 {
+					res = append(res, formatLine(l))
+					continue
+				}
+
+				res = append(res, l.Text)
+			}
 			return nil
 		}
 
-		err := Process(test.rules, strings.NewReader(test.data), handler)
+		err := Process(test.rules, strings.NewReader(test.data), test.opts, handler)
 		if err != nil {
 			t.Errorf("test %d failed: %v", i, err)
 			continue
@@ -61,3 +143,242 @@ func TestProcess(t *testing.T) {
 		}
 	}
 }
+
+func TestProcessContextBatch(t *testing.T) {
+	rules := []Rules{
+		Rules{
+			Templates: templates("foo"),
+		},
+	}
+
+	var data []string
+	for i := 0; i < 3*handleBatchSize; i++ {
+		data = append(data, "foo")
+	}
+	data[handleBatchSize] = "bar"
+
+	var res []Line
+	handler := func(lines []Line) error {
+		res = append(res, lines...)
+		return nil
+	}
+
+	opts := Options{Before: handleBatchSize, After: handleBatchSize}
+	err := Process(rules, strings.NewReader(strings.Join(data, "\n")), opts, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(res) != 2*handleBatchSize+1 {
+		t.Fatalf("wrong number of lines returned, want %d, got %d", 2*handleBatchSize+1, len(res))
+	}
+
+	for i, l := range res {
+		if l.Number != i+1 {
+			t.Errorf("line %d has wrong number %d", i, l.Number)
+		}
+
+		if l.Context != (l.Text == "foo") {
+			t.Errorf("line %d (%q) has wrong context flag %v", i, l.Text, l.Context)
+		}
+	}
+}
+
+func TestProcessReportRules(t *testing.T) {
+	ignore, err := ParseRules(nil, "match = 'prefix'\n---\nkernel:\nsshd:\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report, err := ParseRules(nil, "action = 'report'\nmatch = 'contains'\n---\n@category = 'crash'\nsegfault\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	report.Filename = "local"
+
+	alert, err := ParseRules(nil, "action = 'alert'\ncategory = 'security'\nseverity = 'critical'\n---\n"+
+		"sshd: Accepted password for root...\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	alert.Filename = "security"
+
+	data := `kernel: foo[123]: segfault at 0
+kernel: usb 1-1: new device
+sshd: Accepted password for root from 10.0.0.1
+sshd: Accepted password for user from 10.0.0.1
+foobar segfault
+unmatched`
+
+	var res []Line
+	handler := func(lines []Line) error {
+		res = append(res, lines...)
+		return nil
+	}
+
+	err = Process([]Rules{ignore, report, alert}, strings.NewReader(data), Options{}, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Line{
+		{Number: 1, Text: "kernel: foo[123]: segfault at 0", Action: ActionReport, Rule: "local:5: segfault",
+			Category: "crash"},
+		{Number: 3, Text: "sshd: Accepted password for root from 10.0.0.1", Action: ActionAlert,
+			Rule: "security:5: sshd: Accepted password for root...", Category: "security", Severity: "critical"},
+		{Number: 5, Text: "foobar segfault", Action: ActionReport, Rule: "local:5: segfault", Category: "crash"},
+		{Number: 6, Text: "unmatched", Category: DefaultCategory},
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("wrong lines returned:\n  want %+v\n   got %+v", want, res)
+	}
+}
+
+func TestProcessContextCategory(t *testing.T) {
+	report, err := ParseRules(nil, "action = 'report'\ncategory = 'security'\n---\nroot login\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ignore := Rules{Templates: templates("foo")}
+
+	var res []string
+	handler := func(lines []Line) error {
+		for _, l := range lines {
+			res = append(res, l.Category+" "+formatLine(l))
+		}
+		return nil
+	}
+
+	data := "foo\nroot login\nfoo\nfoo\nbar\nfoo\n"
+	err = Process([]Rules{report, ignore}, strings.NewReader(data), Options{Before: 1, After: 1}, handler)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"security - foo",
+		"security : root login",
+		"security - foo",
+		"unknown - foo",
+		"unknown : bar",
+		"unknown - foo",
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("wrong lines returned:\n  want %q\n   got %q", want, res)
+	}
+}
+
+func TestProcessThreshold(t *testing.T) {
+	fields := map[string]Field{
+		"IP": {Name: "IP", Template: "1.2.3.4", Pattern: regexp.MustCompile(`[0-9.]+`)},
+	}
+
+	rules, err := ParseRules(fields, "---\n"+
+		"@threshold = '2/1m'\n@group_by = 'IP'\nauth failed from 1.2.3.4\n"+
+		"@threshold = '1/10m'\nconnection reset\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "auth"
+
+	now := time.Unix(1000000, 0)
+	opts := Options{
+		Counters: NewCounters(),
+		Now:      func() time.Time { return now },
+	}
+
+	var res []string
+	handler := func(lines []Line) error {
+		for _, l := range lines {
+			res = append(res, fmt.Sprintf("%v %v %v", l.Number, l.Text, l.Summary))
+		}
+		return nil
+	}
+
+	data := `auth failed from 10.0.0.1
+auth failed from 10.0.0.1
+auth failed from 10.0.0.2
+connection reset
+auth failed from 10.0.0.1
+connection reset
+auth failed from 10.0.0.1
+connection reset`
+
+	if err = Process([]Rules{rules}, strings.NewReader(data), opts, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	// after the window has passed, the counters start over
+	now = now.Add(2 * time.Minute)
+	if err = Process([]Rules{rules}, strings.NewReader(data), opts, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{
+		"5 auth failed from 10.0.0.1 threshold exceeded, 3 messages within 1m0s for IP=10.0.0.1",
+		"6 connection reset threshold exceeded, 2 messages within 10m0s",
+		"5 auth failed from 10.0.0.1 threshold exceeded, 3 messages within 1m0s for IP=10.0.0.1",
+	}
+
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("wrong lines returned:\n  want %q\n   got %q", want, res)
+	}
+}
+
+func TestProcessThresholdTimestamp(t *testing.T) {
+	fields := map[string]Field{
+		"timestamp": {
+			Name:       "timestamp",
+			Template:   "Jan  1 11:22:33",
+			Pattern:    regexp.MustCompile(`\w{3}  ?\d{1,2} \d{2}:\d{2}:\d{2}`),
+			TimeFormat: "Jan _2 15:04:05",
+		},
+		"IP": {Name: "IP", Template: "1.2.3.4", Pattern: regexp.MustCompile(`[0-9.]+`)},
+	}
+
+	// the broad rules file is tried first, the threshold is counted anyway
+	broad, err := ParseRules(fields, "prefix = 'Jan  1 11:22:33 '\n---\nauth failed from 1.2.3.4\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	threshold, err := ParseRules(fields, "prefix = 'Jan  1 11:22:33 '\n---\n"+
+		"@threshold = '2/1m'\nauth failed from 1.2.3.4\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	threshold.Filename = "auth"
+
+	now := parseTime(t, "2026-10-19 12:00")
+	opts := Options{
+		Counters: NewCounters(),
+		Now:      func() time.Time { return now },
+	}
+
+	var res []string
+	handler := func(lines []Line) error {
+		for _, l := range lines {
+			res = append(res, fmt.Sprintf("%v %v", l.Number, l.Summary))
+		}
+		return nil
+	}
+
+	data := `Oct 19 11:00:00 auth failed from 10.0.0.1
+Oct 19 11:10:00 auth failed from 10.0.0.1
+Oct 19 11:20:00 auth failed from 10.0.0.1
+Oct 19 11:30:00 auth failed from 10.0.0.1
+Oct 19 11:30:10 auth failed from 10.0.0.1
+Oct 19 11:30:20 auth failed from 10.0.0.1`
+
+	if err = Process([]Rules{broad, threshold}, strings.NewReader(data), opts, handler); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"6 threshold exceeded, 3 messages within 1m0s"}
+	if !reflect.DeepEqual(res, want) {
+		t.Errorf("wrong lines returned:\n  want %q\n   got %q", want, res)
+	}
+}
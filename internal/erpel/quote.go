@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/pkg/errors"
 )
@@ -55,7 +57,7 @@ func unquoteList(s string) (list []string, err error) {
 		return []string{}, err
 	}
 
-	for _, data := range strings.Split(s, ",") {
+	for _, data := range splitList(s) {
 		data = strings.TrimSpace(data)
 		item, err := unquoteString(data)
 		if err != nil {
@@ -67,3 +69,64 @@ func unquoteList(s string) (list []string, err error) {
 
 	return list, nil
 }
+
+// splitList splits the items of a list at the commas which are not within
+// quoted strings.
+func splitList(s string) (items []string) {
+	var quote byte
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch {
+		case quote == 0 && s[i] == ',':
+			items = append(items, s[start:i])
+			start = i + 1
+		case quote == 0 && (s[i] == '"' || s[i] == '\'' || s[i] == '`'):
+			quote = s[i]
+		case quote != 0 && quote != '`' && s[i] == '\\' && i+1 < len(s) && s[i+1] == quote:
+			i++
+		case s[i] == quote:
+			quote = 0
+		}
+	}
+
+	return append(items, s[start:])
+}
+
+// needsEscape returns true if r must not be printed to a terminal as is.
+func needsEscape(r rune) bool {
+	if r == '\t' {
+		return false
+	}
+
+	return unicode.Is(unicode.Cc, r) || unicode.Is(unicode.Bidi_Control, r)
+}
+
+// Escape returns s with control characters, bidirectional formatting
+// characters and bytes which are not valid UTF-8 replaced by escape sequences
+// like \x1b or \u202e, so that it can be printed to a terminal safely.
+// Backslashes are escaped as well, so that escape sequences in the original
+// text can be told apart.
+func Escape(s string) string {
+	var sb strings.Builder
+
+	for i := 0; i < len(s); {
+		r, size := utf8.DecodeRuneInString(s[i:])
+
+		switch {
+		case r == '\\':
+			sb.WriteString(`\\`)
+		case r == utf8.RuneError && size == 1:
+			fmt.Fprintf(&sb, "\\x%02x", s[i])
+		case needsEscape(r) && r < utf8.RuneSelf:
+			fmt.Fprintf(&sb, "\\x%02x", r)
+		case needsEscape(r):
+			fmt.Fprintf(&sb, "\\u%04x", r)
+		default:
+			sb.WriteString(s[i : i+size])
+		}
+
+		i += size
+	}
+
+	return sb.String()
+}
@@ -82,6 +82,10 @@ var testUnquoteList = []struct {
 		"['f', `x`]",
 		[]string{"f", "x"},
 	},
+	{
+		`["/srv/a,b", 'it\'s, here', "\", x"]`,
+		[]string{"/srv/a,b", "it's, here", `", x`},
+	},
 }
 
 func TestUnquoteList(t *testing.T) {
@@ -97,3 +101,29 @@ func TestUnquoteList(t *testing.T) {
 		}
 	}
 }
+
+var testEscape = []struct {
+	data   string
+	result string
+}{
+	{"", ""},
+	{"foo bar", "foo bar"},
+	{"foo\tbar", "foo\tbar"},
+	{"grüße, 世界", "grüße, 世界"},
+	{"foo \x1b[31mbar\x1b[0m", `foo \x1b[31mbar\x1b[0m`},
+	{"foo\rbar\x00\x7f", `foo\x0dbar\x00\x7f`},
+	{"invalid \xff\xfe utf8", `invalid \xff\xfe utf8`},
+	{"C1 \u0085 control", `C1 \u0085 control`},
+	{"bidi \u202egnp.exe", `bidi \u202egnp.exe`},
+	{`literal \x1b`, `literal \\x1b`},
+	{"real \x1b, literal \\x1b", `real \x1b, literal \\x1b`},
+}
+
+func TestEscape(t *testing.T) {
+	for i, test := range testEscape {
+		res := Escape(test.data)
+		if res != test.result {
+			t.Errorf("test %d: Escape(%q) returned wrong result: want %q, got %q", i, test.data, test.result, res)
+		}
+	}
+}
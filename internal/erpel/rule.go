@@ -3,26 +3,131 @@ package erpel
 import (
 	"fmt"
 	"io/ioutil"
+	"net"
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/fd0/erpel/internal/rules"
 	"github.com/pkg/errors"
 )
 
+// Action is taken for messages matched by rules.
+type Action int
+
+// These are the actions for rules. Messages matched by report or alert rules
+// are always passed on, even if they are also matched by ignore rules.
+const (
+	ActionIgnore Action = iota
+	ActionReport
+	ActionAlert
+)
+
+var actions = map[string]Action{
+	"ignore": ActionIgnore,
+	"report": ActionReport,
+	"alert":  ActionAlert,
+}
+
+// parseAction returns the action for the name.
+func parseAction(s string) (Action, error) {
+	a, ok := actions[s]
+	if !ok {
+		return ActionIgnore, errors.Errorf("invalid action %q", s)
+	}
+
+	return a, nil
+}
+
+func (a Action) String() string {
+	for name, action := range actions {
+		if action == a {
+			return name
+		}
+	}
+
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// DefaultCategory is the category for messages which are not classified by
+// any rule.
+const DefaultCategory = "unknown"
+
+// severities lists the valid severities, from lowest to highest.
+var severities = []string{"debug", "info", "notice", "warning", "error", "critical"}
+
+// checkSeverity returns an error if s is not a valid severity.
+func checkSeverity(s string) error {
+	for _, severity := range severities {
+		if s == severity {
+			return nil
+		}
+	}
+
+	return errors.Errorf("invalid severity %q, valid are %v", s, strings.Join(severities, ", "))
+}
+
 // Rules holds all information parsed from a rules file.
 type Rules struct {
 	Prefix string
 
+	// MatchMode is the default match mode for the templates.
+	MatchMode MatchMode
+
+	// Action is taken for messages matched by the rules.
+	Action Action
+
+	// Category and Severity classify the messages matched by the rules,
+	// templates can override them.
+	Category string
+	Severity string
+
+	// Active restricts the times the rules are applied, templates can
+	// override it.
+	Active *ActiveSchedule
+
+	// Expires is the date after which the rules are not applied any more,
+	// it is zero if they do not expire.
+	Expires time.Time
+
+	// Filename is the file the rules were loaded from, if any.
+	Filename string
+
+	// Name is the path of the file relative to the rules directory, it is
+	// used to refer to the rules in template IDs and overrides. The base
+	// name of Filename is used if it is empty.
+	Name string
+
+	// partial is set if templates have been removed after parsing, either
+	// by an override or by the selection of tags. The state saved for the
+	// templates of the file is not cleaned up in this case.
+	partial bool
+
+	// Includes lists the files the fields were included from.
+	Includes []string
+
+	// Metadata documents the rules, the tags apply to all templates.
+	Metadata Metadata
+
 	Fields       map[string]Field
 	GlobalFields map[string]Field
-	Templates    []string
+	Templates    []Template
 	Samples      []string
 
-	rexs      []*regexp.Regexp
+	compiled  []compiledTemplate
 	prefixReg *regexp.Regexp
+
+	// stateful is set if any template has a threshold, is part of a
+	// sequence, is expected, has an active schedule or expires, the
+	// matching template is needed for these.
+	stateful bool
+
+	// sequences maps the name of a sequence to the indexes of its steps
+	sequences map[string][]int
 }
 
 // Field is a dynamic section in a log message.
@@ -31,11 +136,44 @@ type Field struct {
 	Template string
 	Pattern  *regexp.Regexp
 	Samples  []string
+
+	// Expression is the pattern as written in the configuration, if it
+	// references other fields as {{name}}. Pattern holds the pattern with
+	// all references resolved.
+	Expression string
+
+	// Type is the name of a built-in type, values matched by the pattern
+	// are validated against it.
+	Type string
+
+	// Min and Max restrict the values for numeric types.
+	Min, Max *int64
+
+	// Values lists the allowed values, NotValues the forbidden ones.
+	Values, NotValues []string
+
+	// Networks restricts the values to IP addresses within the networks.
+	Networks []*net.IPNet
+
+	// ValuesFile lists allowed values in addition to Values.
+	ValuesFile *ValuesFile
+
+	// TimeFormat is set for fields holding the timestamp of a message, it
+	// is the layout for time.Parse.
+	TimeFormat string
 }
 
+// fieldReference matches a reference to another field within a pattern.
+var fieldReference = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_-]+)\s*\}\}`)
+
 func parseField(name string, field rules.Field) (f Field, err error) {
 	f = Field{Name: name}
 
+	var (
+		min, max    string
+		hasTemplate bool
+	)
+
 	for key, value := range field {
 		switch value[0] {
 		case '"', '\'', '`':
@@ -47,6 +185,12 @@ func parseField(name string, field rules.Field) (f Field, err error) {
 
 		switch key {
 		case "pattern":
+			if fieldReference.MatchString(value) {
+				// compiled when the references are resolved
+				f.Expression = value
+				continue
+			}
+
 			r, err := regexp.Compile(value)
 			if err != nil {
 				return f, errors.WithMessage(err, value)
@@ -54,6 +198,40 @@ func parseField(name string, field rules.Field) (f Field, err error) {
 			f.Pattern = r
 		case "template":
 			f.Template = value
+			hasTemplate = true
+		case "type":
+			f.Type = value
+		case "values":
+			f.Values, err = unquoteList(value)
+			if err != nil {
+				return f, errors.WithMessage(err, value)
+			}
+		case "not_values":
+			f.NotValues, err = unquoteList(value)
+			if err != nil {
+				return f, errors.WithMessage(err, value)
+			}
+		case "cidr":
+			list, err := unquoteList(value)
+			if err != nil {
+				return f, errors.WithMessage(err, value)
+			}
+
+			f.Networks, err = parseNetworks(list)
+			if err != nil {
+				return f, errors.WithMessage(err, "cidr")
+			}
+		case "values_file":
+			f.ValuesFile, err = newValuesFile(value)
+			if err != nil {
+				return f, errors.WithMessage(err, "values_file")
+			}
+		case "time_format":
+			f.TimeFormat = value
+		case "min":
+			min = value
+		case "max":
+			max = value
 		case "samples":
 			f.Samples, err = unquoteList(value)
 			if err != nil {
@@ -64,9 +242,149 @@ func parseField(name string, field rules.Field) (f Field, err error) {
 		}
 	}
 
+	if f.Type != "" {
+		t, err := lookupFieldType(f.Type)
+		if err != nil {
+			return f, err
+		}
+
+		if !hasTemplate {
+			f.Template = t.template
+		}
+
+		if f.Pattern == nil && f.Expression == "" {
+			f.Pattern = regexp.MustCompile(t.pattern)
+		}
+	}
+
+	if f.TimeFormat != "" && hasTemplate {
+		if _, err := time.Parse(f.TimeFormat, f.Template); err != nil {
+			return f, errors.WithMessage(err, "time_format")
+		}
+	}
+
+	if min != "" {
+		if f.Min, err = parseLimit(f.Type, min); err != nil {
+			return f, errors.WithMessage(err, "min")
+		}
+	}
+
+	if max != "" {
+		if f.Max, err = parseLimit(f.Type, max); err != nil {
+			return f, errors.WithMessage(err, "max")
+		}
+	}
+
+	if f.Expression != "" {
+		return f, nil
+	}
+
+	if f.Pattern == nil {
+		return f, errors.Errorf("field %v has neither a pattern nor a type", name)
+	}
+
 	return f, f.Check()
 }
 
+// fieldResolver resolves references to other fields within the patterns of
+// composed fields.
+type fieldResolver struct {
+	scopes []map[string]Field
+
+	// names of the fields currently being resolved, used to detect cycles
+	stack []string
+}
+
+// lookup returns the field from the first scope it is defined in, together
+// with the scope.
+func (fr *fieldResolver) lookup(name string) (Field, map[string]Field, bool) {
+	for _, scope := range fr.scopes {
+		if f, ok := scope[name]; ok {
+			return f, scope, true
+		}
+	}
+
+	return Field{}, nil, false
+}
+
+// resolve compiles the pattern of the field within the scope, the fields it
+// references are resolved first.
+func (fr *fieldResolver) resolve(f Field, scope map[string]Field) (Field, error) {
+	if f.Pattern != nil || f.Expression == "" {
+		return f, nil
+	}
+
+	for i, name := range fr.stack {
+		if name == f.Name {
+			cycle := append(fr.stack[i:], f.Name)
+			return f, errors.Errorf("fields reference each other: %v", strings.Join(cycle, " -> "))
+		}
+	}
+
+	fr.stack = append(fr.stack, f.Name)
+	defer func() {
+		fr.stack = fr.stack[:len(fr.stack)-1]
+	}()
+
+	var err error
+	pattern := fieldReference.ReplaceAllStringFunc(f.Expression, func(ref string) string {
+		name := fieldReference.FindStringSubmatch(ref)[1]
+
+		other, otherScope, ok := fr.lookup(name)
+		if !ok {
+			if err == nil {
+				err = errors.Errorf("field %v references unknown field %q", f.Name, name)
+			}
+			return ref
+		}
+
+		other, e := fr.resolve(other, otherScope)
+		if e != nil {
+			if err == nil {
+				err = e
+			}
+			return ref
+		}
+
+		return "(?:" + other.Pattern.String() + ")"
+	})
+	if err != nil {
+		return f, err
+	}
+
+	f.Pattern, err = regexp.Compile(pattern)
+	if err != nil {
+		return f, errors.Errorf("field %v: %v", f.Name, err)
+	}
+
+	if err = f.Check(); err != nil {
+		return f, errors.Errorf("field %v: %v", f.Name, err)
+	}
+
+	scope[f.Name] = f
+	return f, nil
+}
+
+// resolveFields resolves the references within the patterns of all fields,
+// names are looked up in the fields first, then in the global fields.
+func resolveFields(fields, global map[string]Field) error {
+	fr := &fieldResolver{scopes: []map[string]Field{fields, global}}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if _, err := fr.resolve(fields[name], fields); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 // parseRuleState returns a Rules from a state.
 func parseRuleState(global map[string]Field, state rules.State) (r Rules, err error) {
 	rules := Rules{
@@ -83,8 +401,42 @@ func parseRuleState(global map[string]Field, state rules.State) (r Rules, err er
 		switch key {
 		case "prefix":
 			rules.Prefix = v
+		case "match":
+			rules.MatchMode, err = parseMatchMode(v)
+			if err != nil {
+				return Rules{}, err
+			}
+		case "action":
+			rules.Action, err = parseAction(v)
+			if err != nil {
+				return Rules{}, err
+			}
+		case "category":
+			rules.Category = v
+		case "severity":
+			if err = checkSeverity(v); err != nil {
+				return Rules{}, err
+			}
+			rules.Severity = v
+		case "active":
+			rules.Active, err = parseActive(v)
+			if err != nil {
+				return Rules{}, err
+			}
+		case "expires":
+			rules.Expires, err = parseExpires(v)
+			if err != nil {
+				return Rules{}, err
+			}
 		default:
-			return Rules{}, errors.WithStack(fmt.Errorf("unknown key %q in config", key))
+			ok, err := rules.Metadata.set(key, value)
+			if err != nil {
+				return Rules{}, err
+			}
+
+			if !ok {
+				return Rules{}, errors.WithStack(fmt.Errorf("unknown key %q in config", key))
+			}
 		}
 	}
 
@@ -97,52 +449,286 @@ func parseRuleState(global map[string]Field, state rules.State) (r Rules, err er
 		rules.Fields[name] = f
 	}
 
-	rules.Templates = state.Templates
+	if err = resolveFields(rules.Fields, global); err != nil {
+		return Rules{}, err
+	}
+
+	for _, t := range state.Templates {
+		tmpl, err := parseTemplate(t)
+		if err != nil {
+			return Rules{}, errors.WithMessage(err, fmt.Sprintf("line %d: %v", t.LineNumber, t.Line))
+		}
+
+		rules.Templates = append(rules.Templates, tmpl)
+	}
 	rules.Samples = state.Samples
 
+	if err = rules.compile(); err != nil {
+		return Rules{}, err
+	}
+
 	return rules, nil
 }
 
-func applyFields(s string, fields map[string]Field) string {
-	for _, field := range fields {
-		repl := regexp.QuoteMeta(field.Template)
-		s = strings.Replace(s, repl, field.Pattern.String(), -1)
+// matchMode returns the match mode for the template.
+func (r *Rules) matchMode(t Template) MatchMode {
+	if t.Match != MatchDefault {
+		return t.Match
+	}
+
+	if r.MatchMode != MatchDefault {
+		return r.MatchMode
 	}
 
-	return s
+	return MatchFull
 }
 
-// RegExps returns the rules as a list of regexps. These are cached internally.
-func (r *Rules) RegExps() (rules []*regexp.Regexp) {
-	if r.rexs != nil {
-		return r.rexs
+// fieldGroup is a group in the regexp of a template which holds the value of
+// a field, or a repeated section if repeat is set.
+type fieldGroup struct {
+	group  int
+	field  Field
+	repeat *compiledTemplate
+}
+
+// compiledTemplate is the regexp for a template together with the groups
+// for the fields.
+type compiledTemplate struct {
+	re     *regexp.Regexp
+	groups []fieldGroup
+
+	// checked is set if any of the values need to be validated
+	checked bool
+
+	// rest is the group for the remaining elements of a repeated section
+	rest int
+}
+
+// fields returns the fields of the template in order, including the ones
+// within repeated sections.
+func (ct compiledTemplate) fields() []Field {
+	var list []Field
+	for _, g := range ct.groups {
+		if g.repeat != nil {
+			list = append(list, g.repeat.fields()...)
+			continue
+		}
+		list = append(list, g.field)
+	}
+
+	return list
+}
+
+// hasField returns true if the field occurs in the template.
+func (ct compiledTemplate) hasField(name string) bool {
+	for _, f := range ct.fields() {
+		if f.Name == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// compileTemplate compiles the regexp s, the groups for the fields in c are
+// looked up by name.
+func compileTemplate(s string, c *captures) (compiledTemplate, error) {
+	re, err := regexp.Compile(s)
+	if err != nil {
+		return compiledTemplate{}, err
+	}
+
+	ct := compiledTemplate{re: re}
+	for i, name := range re.SubexpNames() {
+		if strings.HasPrefix(name, repeatName) {
+			n, err := strconv.Atoi(name[len(repeatName):])
+			if err != nil || n >= len(c.repeats) {
+				continue
+			}
+
+			rt, err := compileTemplate(c.repeats[n].re, c.repeats[n].c)
+			if err != nil {
+				return compiledTemplate{}, err
+			}
+			rt.rest = rt.re.SubexpIndex(restName)
+
+			ct.groups = append(ct.groups, fieldGroup{group: i, repeat: &rt})
+			if rt.checked {
+				ct.checked = true
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(name, captureName) {
+			continue
+		}
+
+		n, err := strconv.Atoi(name[len(captureName):])
+		if err != nil || n >= len(c.fields) {
+			continue
+		}
+
+		f := c.fields[n]
+		ct.groups = append(ct.groups, fieldGroup{group: i, field: f})
+		if f.hasChecks() {
+			ct.checked = true
+		}
 	}
 
-	if r.Prefix != "" && r.prefixReg == nil {
-		s := "^" + regexp.QuoteMeta(r.Prefix)
-		s = applyFields(s, r.Fields)
-		s = applyFields(s, r.GlobalFields)
-		r.prefixReg = regexp.MustCompile(s)
+	return ct, nil
+}
+
+// templateRegexp returns the regexp for the template with the prefix,
+// depending on the match mode. Fields which need to be validated are added to c.
+func (r *Rules) templateRegexp(prefix []rules.Part, t Template, c *captures) (string, error) {
+	if r.matchMode(t) == MatchContains {
+		pv, err := r.view(prefix)
+		if err != nil {
+			return "", err
+		}
+
+		tv, err := r.templateView(t)
+		if err != nil {
+			return "", err
+		}
+
+		return "^" + pv.regexp(c) + ".*" + tv.regexp(c) + ".*$", nil
+	}
+
+	v, err := r.prefixedView(prefix, t)
+	if err != nil {
+		return "", err
 	}
 
-	for _, s := range r.Templates {
-		s = "^" + regexp.QuoteMeta(r.Prefix) + regexp.QuoteMeta(s) + "$"
+	if r.matchMode(t) == MatchPrefix {
+		return "^" + v.regexp(c) + ".*$", nil
+	}
+
+	return "^" + v.regexp(c) + "$", nil
+}
+
+// compile builds the regexps for the prefix and the templates.
+func (r *Rules) compile() error {
+	prefix, err := parsePrefix(r.Prefix)
+	if err != nil {
+		return errors.WithMessage(err, "prefix")
+	}
+
+	if len(prefix) > 0 {
+		v, err := r.view(prefix)
+		if err != nil {
+			return errors.WithMessage(err, "prefix")
+		}
+
+		r.prefixReg, err = regexp.Compile("^" + v.regexp(nil))
+		if err != nil {
+			return errors.WithMessage(err, "prefix")
+		}
+	}
 
-		// apply local fields, then global
-		s = applyFields(s, r.Fields)
-		s = applyFields(s, r.GlobalFields)
+	compiled := make([]compiledTemplate, 0, len(r.Templates))
+	for _, t := range r.Templates {
+		c := &captures{}
+		s, err := r.templateRegexp(prefix, t, c)
+		if err != nil {
+			return errors.WithMessage(err, t.position())
+		}
 
-		re, err := regexp.Compile(s)
+		ct, err := compileTemplate(s, c)
 		if err != nil {
-			panic(err)
+			return errors.WithMessage(err, t.position())
+		}
+
+		if t.GroupBy != "" && !ct.hasField(t.GroupBy) {
+			return errors.Errorf("%v: field %q to group by is not part of the template", t.position(), t.GroupBy)
+		}
+
+		if t.Sequence != "" && !ct.hasField(t.Key) {
+			return errors.Errorf("%v: key field %q is not part of the template", t.position(), t.Key)
+		}
+
+		if t.Threshold != nil || t.Sequence != "" || t.Expect != nil || r.active(t) != nil ||
+			!r.expires(t).IsZero() {
+			r.stateful = true
+		}
+
+		compiled = append(compiled, ct)
+	}
+
+	if err := r.checkIDs(); err != nil {
+		return err
+	}
+
+	if err := r.compileSequences(); err != nil {
+		return err
+	}
+
+	r.compiled = compiled
+
+	return nil
+}
+
+// compileSequences collects the steps of the sequences.
+func (r *Rules) compileSequences() error {
+	r.sequences = nil
+	for i, t := range r.Templates {
+		if t.Sequence == "" {
+			continue
+		}
+
+		if r.sequences == nil {
+			r.sequences = make(map[string][]int)
+		}
+
+		steps := r.sequences[t.Sequence]
+		if len(steps) == 0 && t.Within == nil {
+			return errors.Errorf("%v: first step of sequence %q needs a window (within)", t.position(), t.Sequence)
 		}
 
-		rules = append(rules, re)
+		if len(steps) > 0 && t.Within != nil {
+			return errors.Errorf("%v: within is only allowed for the first step of sequence %q", t.position(), t.Sequence)
+		}
+
+		r.Templates[i].step = len(steps)
+		r.sequences[t.Sequence] = append(steps, i)
 	}
 
-	r.rexs = rules
+	for name, steps := range r.sequences {
+		if len(steps) < 2 {
+			return errors.Errorf("sequence %q needs at least two steps", name)
+		}
+
+		if r.Action != ActionIgnore {
+			return errors.Errorf("sequence %q: sequences are only supported for ignore rules", name)
+		}
+	}
 
-	return rules
+	return nil
+}
+
+// templates returns the compiled templates, these are cached internally. If
+// the rules cannot be compiled, nil is returned. This can only happen for
+// Rules which have not been returned by ParseRules, which reports the error.
+func (r *Rules) templates() []compiledTemplate {
+	if r.compiled != nil {
+		return r.compiled
+	}
+
+	if err := r.compile(); err != nil {
+		return nil
+	}
+
+	return r.compiled
+}
+
+// RegExps returns the rules as a list of regexps. These are cached internally.
+func (r *Rules) RegExps() []*regexp.Regexp {
+	list := make([]*regexp.Regexp, 0, len(r.Templates))
+	for _, ct := range r.templates() {
+		list = append(list, ct.re)
+	}
+
+	return list
 }
 
 // checkPattern tests whether the r matches s completely.
@@ -171,6 +757,10 @@ func (f *Field) Check() error {
 		if err := checkPattern(f.Pattern, sample); err != nil {
 			return errors.WithStack(err)
 		}
+
+		if err := f.validate(sample); err != nil {
+			return errors.WithMessage(err, "sample")
+		}
 	}
 
 	return nil
@@ -186,6 +776,24 @@ func (f Field) Equals(other Field) bool {
 		return false
 	}
 
+	if f.Type != other.Type || !reflect.DeepEqual(f.Min, other.Min) || !reflect.DeepEqual(f.Max, other.Max) {
+		return false
+	}
+
+	if !reflect.DeepEqual(f.Values, other.Values) || !reflect.DeepEqual(f.NotValues, other.NotValues) ||
+		!reflect.DeepEqual(f.Networks, other.Networks) {
+		return false
+	}
+
+	if (f.ValuesFile == nil) != (other.ValuesFile == nil) ||
+		(f.ValuesFile != nil && f.ValuesFile.Filename != other.ValuesFile.Filename) {
+		return false
+	}
+
+	if f.TimeFormat != other.TimeFormat {
+		return false
+	}
+
 	if !reflect.DeepEqual(f.Samples, other.Samples) {
 		return false
 	}
@@ -197,18 +805,102 @@ func (f Field) Equals(other Field) bool {
 	return true
 }
 
-// Match tests if a rule matches s completely.
-func (r *Rules) Match(s string) bool {
-	// test prefix first
-	if r.prefixReg != nil {
-		match := r.prefixReg.FindStringIndex(s)
-		if match == nil {
+// Values maps field names to the values of all occurrences of the field
+// within a message, in order.
+type Values map[string][]string
+
+// Get returns the value of the first occurrence of the field, or the empty
+// string if the field does not occur.
+func (v Values) Get(name string) string {
+	if len(v[name]) == 0 {
+		return ""
+	}
+
+	return v[name][0]
+}
+
+// match tests whether the template matches s completely. The values of the
+// fields are returned if values is set.
+func (ct compiledTemplate) match(s string, values bool) (Values, bool) {
+	if !values && !ct.checked {
+		return nil, ct.re.MatchString(s)
+	}
+
+	match := ct.re.FindStringSubmatchIndex(s)
+	if match == nil {
+		return nil, false
+	}
+
+	var v Values
+	if values {
+		v = make(Values)
+	}
+
+	return v, ct.validate(s, match, v)
+}
+
+// validate checks the values of the groups in match against the fields, the
+// values are added to v unless it is nil.
+func (ct compiledTemplate) validate(s string, match []int, v Values) bool {
+	for _, g := range ct.groups {
+		start, end := match[2*g.group], match[2*g.group+1]
+		if start < 0 {
+			// the group did not participate in the match
+			continue
+		}
+
+		value := s[start:end]
+		if g.repeat != nil {
+			if !g.repeat.matchElements(value, v) {
+				return false
+			}
+			continue
+		}
+
+		if err := g.field.validate(value); err != nil {
 			return false
 		}
+
+		if v != nil {
+			v[g.field.Name] = append(v[g.field.Name], value)
+		}
+	}
+
+	return true
+}
+
+// matchElements matches the elements of a repeated section in s one after
+// the other, so the fields in every element are validated. It stops when the
+// rest is empty or did not get shorter, which happens for elements matching
+// the empty string.
+func (ct compiledTemplate) matchElements(s string, v Values) bool {
+	for {
+		match := ct.re.FindStringSubmatchIndex(s)
+		if match == nil || !ct.validate(s, match, v) {
+			return false
+		}
+
+		start, end := match[2*ct.rest], match[2*ct.rest+1]
+		if start < 0 || end == start || end-start >= len(s) {
+			return true
+		}
+		s = s[start:end]
+	}
+}
+
+// matchPrefix returns false if the prefix does not match s.
+func (r *Rules) matchPrefix(s string) bool {
+	return r.prefixReg == nil || r.prefixReg.MatchString(s)
+}
+
+// Match tests if a rule matches s completely.
+func (r *Rules) Match(s string) bool {
+	if !r.matchPrefix(s) {
+		return false
 	}
 
-	for _, rule := range r.RegExps() {
-		if err := checkPattern(rule, s); err == nil {
+	for _, ct := range r.templates() {
+		if _, ok := ct.match(s, false); ok {
 			return true
 		}
 	}
@@ -216,6 +908,95 @@ func (r *Rules) Match(s string) bool {
 	return false
 }
 
+// Classify returns the category and severity for messages matched by the
+// template. The category is DefaultCategory if neither the template nor the
+// rules set one.
+func (r *Rules) Classify(t Template) (category, severity string) {
+	category, severity = t.Category, t.Severity
+
+	if category == "" {
+		category = r.Category
+	}
+
+	if category == "" {
+		category = DefaultCategory
+	}
+
+	if severity == "" {
+		severity = r.Severity
+	}
+
+	return category, severity
+}
+
+// Describe returns a description of the template for messages, including the
+// file name and line number if known.
+func (r *Rules) Describe(t Template) string {
+	if r.Filename == "" {
+		return t.position()
+	}
+
+	if t.LineNumber == 0 {
+		return fmt.Sprintf("%v: %v", r.Filename, t.Text)
+	}
+
+	return fmt.Sprintf("%v:%d: %v", r.Filename, t.LineNumber, t.Text)
+}
+
+// MatchFields tests if a rule matches s completely like Match. It returns the
+// first matching template and the values of all fields within the message,
+// including those from the prefix.
+func (r *Rules) MatchFields(s string) (Template, Values, bool) {
+	if !r.matchPrefix(s) {
+		return Template{}, nil, false
+	}
+
+	for i, ct := range r.templates() {
+		if v, ok := ct.match(s, true); ok {
+			return r.Templates[i], v, true
+		}
+	}
+
+	return Template{}, nil, false
+}
+
+// MatchAt is like MatchFields, but templates are skipped if they have expired
+// at now or are not active at the time of the message. The time is taken from
+// the first field with a time format within the message, now is used if there
+// is none.
+func (r *Rules) MatchAt(s string, now time.Time) (Template, Values, bool) {
+	t, v, _, ok := r.matchAt(s, now)
+	return t, v, ok
+}
+
+// matchAt is like MatchAt, it also returns the time of the message.
+func (r *Rules) matchAt(s string, now time.Time) (Template, Values, time.Time, bool) {
+	if !r.matchPrefix(s) {
+		return Template{}, nil, time.Time{}, false
+	}
+
+	for i, ct := range r.templates() {
+		v, ok := ct.match(s, true)
+		if !ok {
+			continue
+		}
+
+		t := r.Templates[i]
+		if expired(r.expires(t), now) {
+			continue
+		}
+
+		ts := ct.timestamp(v, now)
+		if a := r.active(t); a != nil && !a.Active(ts) {
+			continue
+		}
+
+		return t, v, ts, true
+	}
+
+	return Template{}, nil, time.Time{}, false
+}
+
 // Check runs self-tests on the Rules, it returns an error if a message in the
 // samples section is not matched by the rules.
 func (r *Rules) Check() error {
@@ -228,13 +1009,18 @@ func (r *Rules) Check() error {
 	return nil
 }
 
-// ParseRules parses the data as an erpel rule file.
+// ParseRules parses the data as an erpel rule file. Includes are only
+// supported for rules files, see ParseRulesFile.
 func ParseRules(global map[string]Field, data string) (Rules, error) {
 	state, err := rules.Parse(data)
 	if err != nil {
 		return Rules{}, errors.WithStack(err)
 	}
 
+	if len(state.Includes) > 0 {
+		return Rules{}, errors.New("include is only supported in rules files")
+	}
+
 	rules, err := parseRuleState(global, state)
 	if err != nil {
 		return Rules{}, errors.WithStack(err)
@@ -243,40 +1029,38 @@ func ParseRules(global map[string]Field, data string) (Rules, error) {
 	return rules, nil
 }
 
-// ParseRulesFile loads rules from a file and parses it.
+// ParseRulesFile loads rules from a file and parses it. Files included by
+// the rules file are loaded as well, they are resolved relative to the
+// directory of the file. Rules files found in a rules directory are loaded
+// with Config.LoadRulesFile instead.
 func ParseRulesFile(global map[string]Field, filename string) (Rules, error) {
+	return parseRulesFile(global, filename, filepath.Dir(filename))
+}
+
+// parseRulesFile loads rules from a file and parses it, included files are
+// resolved relative to dir.
+func parseRulesFile(global map[string]Field, filename, dir string) (Rules, error) {
 	buf, err := ioutil.ReadFile(filename)
 	if err != nil {
 		return Rules{}, err
 	}
 
-	return ParseRules(global, string(buf))
-}
-
-// ParseAllRulesFiles loads rules from all files in the directory.
-func ParseAllRulesFiles(global map[string]Field, dir string) (rules []Rules, err error) {
-	pattern := filepath.Join(dir, "*")
-	matches, err := filepath.Glob(pattern)
+	state, err := rules.Parse(string(buf))
 	if err != nil {
-		return nil, errors.WithMessage(err, pattern)
+		return Rules{}, errors.WithStack(err)
 	}
 
-	for _, file := range matches {
-		if strings.HasPrefix(filepath.Base(file), ".") {
-			continue
-		}
-
-		r, err := ParseRulesFile(global, file)
-		if err != nil {
-			return nil, errors.WithMessage(err, file)
-		}
-
-		if err = r.Check(); err != nil {
-			return nil, errors.WithMessage(err, file)
-		}
+	includes, err := includeFields(&state, filename, dir)
+	if err != nil {
+		return Rules{}, err
+	}
 
-		rules = append(rules, r)
+	rules, err := parseRuleState(global, state)
+	if err != nil {
+		return Rules{}, errors.WithStack(err)
 	}
 
+	rules.Filename = filename
+	rules.Includes = includes
 	return rules, nil
 }
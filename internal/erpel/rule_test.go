@@ -5,9 +5,34 @@ import (
 	"path/filepath"
 	"reflect"
 	"regexp"
+	"strings"
 	"testing"
 )
 
+// templates parses the lines as templates, it panics on error.
+func templates(lines ...string) []Template {
+	var list []Template
+	for _, line := range lines {
+		t, err := ParseTemplate(line)
+		if err != nil {
+			panic(err)
+		}
+
+		list = append(list, t)
+	}
+
+	return list
+}
+
+// numbered sets consecutive line numbers for the templates, starting at line.
+func numbered(line int, list []Template) []Template {
+	for i := range list {
+		list[i].LineNumber = line + i
+	}
+
+	return list
+}
+
 var testRulesFiles = []struct {
 	data   string
 	global map[string]Field
@@ -83,10 +108,10 @@ Jun  2 23:17:22 mail dovecot: IMAP(foobar): Disconnected: Logged out bytes=1152/
 					Pattern:  regexp.MustCompile(`\d+`),
 				},
 			},
-			Templates: []string{
+			Templates: numbered(26, templates(
 				`lda(user@host.tld): sieve: msgid=<20160602211704.9125E5A063@localhost>: stored mail into mailbox 'INBOX'`,
 				`IMAP(username@domain.tld): Disconnected: Logged out bytes=123/123`,
-			},
+			)),
 			Samples: []string{
 				`Jun  2 23:17:18 mail dovecot: lda(me@domain.de): sieve: msgid=<20160602211704.9125E5A063@graphite.x.net>: stored mail into mailbox 'INBOX'`,
 				`Jun  2 23:17:22 mail dovecot: IMAP(foobar): Disconnected: Logged out bytes=1152/16042`,
@@ -164,3 +189,569 @@ func TestParseSampleRules(t *testing.T) {
 		}
 	}
 }
+
+var testRulesMatch = []struct {
+	data    string
+	match   []string
+	nomatch []string
+}{
+	{
+		data: `
+prefix = "{{timestamp}} {{host:[a-z]+}} dovecot: "
+
+field num {
+    template = '123'
+    pattern = '\d+'
+}
+
+field user {
+    pattern = '[a-z]+'
+}
+
+---
+login: user={{user}}, rip=1.2.3.4, mpid={{num:\d{1,3}}}
+literal 123 \{{user}}
+trailing space\ 
+`,
+		match: []string{
+			"Jun  2 23:17:13 mail dovecot: login: user=foo, rip=192.168.0.1, mpid=123",
+			"Jun  2 23:17:13 mail dovecot: literal 23 {{user}}",
+			"Jun  2 23:17:13 mail dovecot: trailing space ",
+		},
+		nomatch: []string{
+			"Jun  2 23:17:13 mail dovecot: login: user=foo, rip=192.168.0.1, mpid=1234",
+			"Jun  2 23:17:13 mail dovecot: login: user=Foo, rip=192.168.0.1, mpid=123",
+			"Jun  2 23:17:13 Mail dovecot: login: user=foo, rip=192.168.0.1, mpid=123",
+			"Jun  2 23:17:13 mail dovecot: literal 23 foo",
+			"Jun  2 23:17:13 mail dovecot: trailing space",
+		},
+	},
+	{
+		data: `
+field num {
+    template = '123'
+    pattern = '\d+'
+}
+
+---
+Login: rip=1.2.3.4[[, mpid=123]], (TLS|SSL)[[, session=<{{session:[a-z]+}}>]]
+(IMAP|imap)(user): Logged out (bytes=123/123|in=123 out=123)
+`,
+		match: []string{
+			"Login: rip=192.168.0.1, TLS",
+			"Login: rip=192.168.0.1, mpid=5, SSL",
+			"Login: rip=192.168.0.1, mpid=5, TLS, session=<abc>",
+			"Login: rip=192.168.0.1, SSL, session=<abc>",
+			"imap(user): Logged out in=1 out=2",
+			"IMAP(user): Logged out bytes=1/2",
+		},
+		nomatch: []string{
+			"Login: rip=192.168.0.1, mpid=, TLS",
+			"Login: rip=192.168.0.1, mpid=5",
+			"Login: rip=192.168.0.1, STARTTLS",
+			"Login: rip=192.168.0.1, TLS, session=<>",
+			"Imap(user): Logged out in=1 out=2",
+			"IMAP(user): Logged out bytes=1/2 in=1 out=2",
+		},
+	},
+	{
+		data: `
+field mailaddress {
+    template = 'user@domain.tld'
+    pattern = '[a-z]+@[a-z]+\.[a-z]+'
+}
+
+---
+to={{repeat: <user@domain.tld>, sep=", "}}, status=sent
+orig_to=<{{repeat:{{num:\d}}}}>
+`,
+		match: []string{
+			"to=<foo@example.com>, status=sent",
+			"to=<foo@example.com>, <bar@example.com>, <baz@example.com>, status=sent",
+			"orig_to=<1>",
+			"orig_to=<123>",
+		},
+		nomatch: []string{
+			"to=, status=sent",
+			"to=<foo@example.com>, <bar>, status=sent",
+			"to=<foo@example.com>,<bar@example.com>, status=sent",
+			"to=<foo@example.com>, , status=sent",
+			"orig_to=<>",
+			"orig_to=<12a>",
+		},
+	},
+	{
+		data: `
+prefix = "{{host:[a-z]+}} postfix: "
+---
+connect from ...[1.2.3.4]
+@match = 'prefix'
+disconnect from
+@match = 'contains'
+warning: TLS library problem
+`,
+		match: []string{
+			"mail postfix: connect from unknown[192.168.0.1]",
+			"mail postfix: connect from [192.168.0.1]",
+			"mail postfix: disconnect from unknown[192.168.0.1] ehlo=1 quit=1",
+			"mail postfix: disconnect from",
+			"mail postfix: 12345: warning: TLS library problem: error:14094418",
+		},
+		nomatch: []string{
+			"mail postfix: connect from unknown",
+			"mail postfix: lost connection after disconnect from",
+			"Mail postfix: warning: TLS library problem",
+			"mail sshd: warning: TLS library problem",
+		},
+	},
+	{
+		data: `
+prefix = "{{host:[a-z]+}} kernel: "
+
+field num {
+    template = '123'
+    pattern = '\d+'
+}
+
+---
+re:CPU{{num}}: (\w+ ){2,}throttled
+re:a|b
+`,
+		match: []string{
+			"mail kernel: CPU3: core temperature above threshold throttled",
+			"mail kernel: CPU12: package power limit throttled",
+			"mail kernel: a",
+			"mail kernel: b",
+		},
+		nomatch: []string{
+			"mail kernel: CPUx: core temperature above threshold throttled",
+			"mail kernel: CPU3: core throttled",
+			"mail kernel: ab",
+			"kernel: a",
+			"mail sshd: a",
+		},
+	},
+	{
+		data: `
+field localpart {
+    pattern = '[a-z0-9.+-]+'
+}
+
+field domain {
+    pattern = '[a-z0-9-]+(\.[a-z0-9-]+)+'
+}
+
+field mailaddress {
+    template = 'user@domain.tld'
+    pattern = '{{localpart}}@{{ domain }}'
+    samples = ['foo.bar@example.com']
+}
+
+field relay {
+    template = 'mx.domain.tld[1.2.3.4]'
+    pattern = '{{domain}}\[{{IP}}\]'
+}
+
+---
+to=<user@domain.tld>, relay=mx.domain.tld[1.2.3.4]
+`,
+		match: []string{
+			"to=<foo+bar@example.com>, relay=mx.example.com[192.168.1.1]",
+		},
+		nomatch: []string{
+			"to=<foo@example>, relay=mx.example.com[192.168.1.1]",
+			"to=<foo@example.com>, relay=mx.example.com",
+		},
+	},
+	{
+		data: `
+field client {
+    template = '10.0.0.1'
+    type = 'ip'
+}
+
+field port {
+    template = '25'
+    type = 'int'
+    min = '1'
+    max = '65535'
+}
+
+field host {
+    type = 'hostname'
+}
+
+---
+connect from {{host}}[10.0.0.1]:25
+{{repeat: 10.0.0.1, sep=" "}}
+`,
+		match: []string{
+			"connect from mail.example.com[192.168.0.1]:587",
+			"connect from mail.example.com[2001:db8::1]:65535",
+			"192.168.0.1 10.0.0.1 ::1",
+		},
+		nomatch: []string{
+			"connect from mail.example.com[87.444.65.555]:587",
+			"connect from mail.example.com[192.168.0.1]:65536",
+			"connect from mail.example.com[192.168.0.1]:0",
+			"connect from -mail.example.com[192.168.0.1]:25",
+			"192.168.0.1 10.0.0.1 10.0.0.256",
+			"192.168.0.1 10.0.0.256 ::1",
+		},
+	},
+	{
+		data: `
+field user {
+    template = 'user'
+    pattern = '[a-z]+'
+    values = ['alice', 'bob']
+}
+
+---
+to: {{repeat: <user>, sep=", "}}
+`,
+		match: []string{
+			"to: <alice>",
+			"to: <alice>, <bob>, <alice>",
+		},
+		nomatch: []string{
+			"to: <mallory>",
+			"to: <alice>, <mallory>, <bob>",
+			"to: <mallory>, <alice>",
+		},
+	},
+	{
+		data: `
+field user {
+    template = 'someuser'
+    pattern = '[a-z]+'
+    values = ['backup', 'nagios']
+}
+
+field client {
+    template = '10.0.0.1'
+    pattern = '[0-9a-f.:]+'
+    cidr = ['10.0.0.0/8', '192.168.0.0/16']
+    not_values = ['10.0.0.1']
+}
+
+---
+login user=someuser from 10.0.0.1
+`,
+		match: []string{
+			"login user=backup from 10.1.2.3",
+			"login user=nagios from 192.168.0.1",
+		},
+		nomatch: []string{
+			"login user=root from 10.1.2.3",
+			"login user=backup from 172.16.0.1",
+			"login user=backup from 10.0.0.1",
+		},
+	},
+	{
+		data: `
+match = 'prefix'
+---
+connect from
+@match = 'full'
+lost connection
+`,
+		match: []string{
+			"connect from unknown[192.168.0.1]",
+			"lost connection",
+		},
+		nomatch: []string{
+			"lost connection after DATA",
+		},
+	},
+}
+
+func TestRulesMatch(t *testing.T) {
+	for i, test := range testRulesMatch {
+		rules := parseRules(t, test.data)
+
+		for _, line := range test.match {
+			if !rules.Match(line) {
+				t.Errorf("test %d: line %q not matched", i, line)
+			}
+		}
+
+		for _, line := range test.nomatch {
+			if rules.Match(line) {
+				t.Errorf("test %d: line %q matched", i, line)
+			}
+		}
+	}
+}
+
+var testInvalidRules = []string{
+	// templates overlap
+	"field a {\ntemplate = 'ab'\npattern = 'x'\n}\nfield b {\ntemplate = 'bc'\npattern = 'y'\n}\n---\nabc\n",
+	// same template for two local fields
+	"field a {\ntemplate = 'ab'\npattern = 'x'\n}\nfield b {\ntemplate = 'ab'\npattern = 'y'\n}\n---\nfoo ab\n",
+	"---\nfoo {{unknown}}\n",
+	"---\nfoo {{num:(}}\n",
+	"prefix = '{{unknown}} '\n---\nfoo\n",
+	"match = 'some'\n---\nfoo\n",
+	"action = 'drop'\n---\nfoo\n",
+	"severity = 'urgent'\n---\nfoo\n",
+	"---\n@severity = 'high'\nfoo\n",
+	"---\n@match = 'some'\nfoo\n",
+	"---\n@unknown = 'x'\nfoo\n",
+	"---\n@threshold = '5'\nfoo\n",
+	"active = 'Monday'\n---\nfoo\n",
+	"---\n@active = '25:00-26:00'\nfoo\n",
+	"field t {\ntemplate = 'Jan  1'\npattern = 'x'\ntime_format = '2006-01-02'\n}\n---\nfoo\n",
+	"---\n@key = 'x'\nfoo\n",
+	"---\n@sequence = 's'\nfoo\n",
+	"---\n@sequence = 's'\n@key = 'x'\n@within = 'soon'\nfoo\n",
+	// key is not part of the template
+	"field x {\ntemplate = 'bar'\npattern = 'b'\n}\n---\n@sequence = 's'\n@key = 'y'\n@within = '1h'\nfoo bar\n@sequence = 's'\n@key = 'y'\nbaz bar\n",
+	// only a single step
+	"field x {\ntemplate = 'bar'\npattern = 'b'\n}\n---\n@sequence = 's'\n@key = 'x'\n@within = '1h'\nfoo bar\n",
+	// window missing for the first step
+	"field x {\ntemplate = 'bar'\npattern = 'b'\n}\n---\n@sequence = 's'\n@key = 'x'\nfoo bar\n@sequence = 's'\n@key = 'x'\n@within = '1h'\nbaz bar\n",
+	// sequences in report rules
+	"action = 'report'\nfield x {\ntemplate = 'bar'\npattern = 'b'\n}\n---\n@sequence = 's'\n@key = 'x'\n@within = '1h'\nfoo bar\n@sequence = 's'\n@key = 'x'\nbaz bar\n",
+	"---\n@group_by = 'x'\nfoo\n",
+	"field x {\ntemplate = 'bar'\npattern = 'b'\n}\n---\n@threshold = '5/1m'\n@group_by = 'y'\nfoo bar\n",
+	"---\nre:foo(\n",
+	"---\nre:foo {{unknown}}\n",
+	// references to unknown fields and cycles
+	"field a {\npattern = '{{b}}x'\n}\n---\nfoo\n",
+	"field a {\npattern = '{{b}}x'\n}\nfield b {\npattern = 'y{{c}}'\n}\nfield c {\npattern = '{{a}}'\n}\n---\nfoo\n",
+	"field a {\npattern = 'x{{a}}'\n}\n---\nfoo\n",
+	// types, min and max
+	"field a {\ntype = 'foo'\n}\n---\nfoo\n",
+	"field a {\ntype = 'hostname'\nmin = '1'\n}\n---\nfoo\n",
+	"field a {\npattern = '\\d+'\nmax = '1'\n}\n---\nfoo\n",
+	"field a {\ntype = 'int'\nmax = 'x'\n}\n---\nfoo\n",
+	"field a {\ntype = 'ipv4'\nsamples = ['1.2.3.400']\n}\n---\nfoo\n",
+	// constraints
+	"field a {\npattern = '.*'\ncidr = ['10.0.0.0/33']\n}\n---\nfoo\n",
+	"field a {\npattern = '.*'\nvalues_file = '/nonexistent/erpel/values'\n}\n---\nfoo\n",
+	"field a {\npattern = '[a-z]+'\nvalues = ['foo']\nsamples = ['bar']\n}\n---\nfoo\n",
+	// samples of composed fields are checked
+	"field a {\npattern = '[a-z]+'\n}\nfield b {\npattern = '{{a}}@{{a}}'\nsamples = ['foo']\n}\n---\nfoo\n",
+}
+
+func TestRulesErrorPosition(t *testing.T) {
+	_, err := ParseRules(nil, "---\nfoo\n\nre:bar[a-\n")
+	if err == nil {
+		t.Fatal("expected error not found")
+	}
+
+	if !strings.Contains(err.Error(), "line 4: re:bar[a-") {
+		t.Errorf("error does not contain the template position: %v", err)
+	}
+}
+
+func TestRulesBackreference(t *testing.T) {
+	var tests = []struct {
+		data string
+		ref  string
+	}{
+		{"---\nre:(a+) \\1\n", `\1`},
+		{"---\nre:(?P<x>a+) (?P=x)\n", "(?P=x)"},
+		{"---\nre:(?P<x>a+) \\k<x>\n", `\k`},
+	}
+
+	for i, test := range tests {
+		_, err := ParseRules(nil, test.data)
+		if err == nil {
+			t.Errorf("test %d: expected error not found", i)
+			continue
+		}
+
+		if !strings.Contains(err.Error(), "backreference "+test.ref+" is not supported") {
+			t.Errorf("test %d: wrong error returned: %v", i, err)
+		}
+	}
+
+	// an escaped backslash followed by a digit is fine
+	if _, err := ParseRules(nil, "---\nre:a\\\\1\n"); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func TestRulesInvalid(t *testing.T) {
+	for i, data := range testInvalidRules {
+		_, err := ParseRules(nil, data)
+		if err == nil {
+			t.Errorf("test %d: expected error not found", i)
+		}
+	}
+}
+
+const testSubstitutionRules = `
+field mailaddress {
+    template = 'user@domain.tld'
+    pattern = '[a-z]+@[a-z.]+'
+}
+
+field name {
+    template = 'user'
+    pattern = '[a-z]+'
+}
+
+# shadows the global field IP
+field ip {
+    template = '1.2.3.4'
+    pattern = '[0-9.]+'
+}
+
+---
+login user=user@domain.tld name=user from 1.2.3.4 (user)
+`
+
+func TestRulesSubstitution(t *testing.T) {
+	want := []string{
+		"login ", "[name]", "=", "[mailaddress]", " name=", "[name]",
+		" from ", "[ip]", " (", "[name]", ")",
+	}
+	wantRegexp := `^login (?P<erpel_field0>[a-z]+)=(?P<erpel_field1>[a-z]+@[a-z.]+) ` +
+		`name=(?P<erpel_field2>[a-z]+) from (?P<erpel_field3>[0-9.]+) \((?P<erpel_field4>[a-z]+)\)$`
+
+	// run several times, map iteration order is random
+	for i := 0; i < 20; i++ {
+		rules := parseRules(t, testSubstitutionRules)
+
+		views := rules.Views()
+		if len(views) != 1 {
+			t.Fatalf("wrong number of views returned: %v", len(views))
+		}
+
+		var got []string
+		for _, item := range views[0] {
+			got = append(got, item.String())
+		}
+
+		if !reflect.DeepEqual(got, want) {
+			t.Fatalf("wrong view returned:\n  want %q\n   got %q", want, got)
+		}
+
+		re := rules.RegExps()[0].String()
+		if re != wantRegexp {
+			t.Fatalf("wrong regexp returned:\n  want %v\n   got %v", wantRegexp, re)
+		}
+	}
+}
+
+const testMatchFieldsRules = `
+prefix = "{{timestamp}} {{host:[a-z]+}} dovecot: "
+
+field num {
+    template = '123'
+    pattern = '(\d)+'
+}
+
+field session {
+    pattern = '(?P<session>[a-zA-Z0-9+/]+)'
+}
+
+---
+foo
+login: rip=1.2.3.4, lip=1.2.3.4[[, mpid=123]][[, session=<{{session}}>]]
+`
+
+func TestRulesMatchFields(t *testing.T) {
+	rules := parseRules(t, testMatchFieldsRules)
+
+	var tests = []struct {
+		line     string
+		template string
+		values   Values
+	}{
+		{
+			line:     "Jun  2 23:17:13 mail dovecot: foo",
+			template: "foo",
+			values: Values{
+				"timestamp": {"Jun  2 23:17:13"},
+				"host":      {"mail"},
+			},
+		},
+		{
+			line:     "Jun  2 23:17:13 mail dovecot: login: rip=192.168.0.1, lip=2003::1, mpid=42",
+			template: "login: rip=1.2.3.4, lip=1.2.3.4[[, mpid=123]][[, session=<{{session}}>]]",
+			values: Values{
+				"timestamp": {"Jun  2 23:17:13"},
+				"host":      {"mail"},
+				"IP":        {"192.168.0.1", "2003::1"},
+				"num":       {"42"},
+			},
+		},
+		{
+			line:     "Jun  2 23:17:13 mail dovecot: login: rip=192.168.0.1, lip=2003::1, session=<abc>",
+			template: "login: rip=1.2.3.4, lip=1.2.3.4[[, mpid=123]][[, session=<{{session}}>]]",
+			values: Values{
+				"timestamp": {"Jun  2 23:17:13"},
+				"host":      {"mail"},
+				"IP":        {"192.168.0.1", "2003::1"},
+				"session":   {"abc"},
+			},
+		},
+	}
+
+	for i, test := range tests {
+		tmpl, values, ok := rules.MatchFields(test.line)
+		if !ok {
+			t.Errorf("test %d: line not matched", i)
+			continue
+		}
+
+		if tmpl.Text != test.template {
+			t.Errorf("test %d: wrong template returned, want %q, got %q", i, test.template, tmpl.Text)
+		}
+
+		if !reflect.DeepEqual(values, test.values) {
+			t.Errorf("test %d: wrong values returned:\n  want %v\n   got %v", i, test.values, values)
+		}
+	}
+
+	if v := tests[1].values; v.Get("IP") != "192.168.0.1" || v.Get("unknown") != "" {
+		t.Errorf("Get returned wrong values")
+	}
+
+	if _, _, ok := rules.MatchFields("Jun  2 23:17:13 mail dovecot: bar"); ok {
+		t.Errorf("unexpected match")
+	}
+}
+
+func TestRulesMatchFieldsEmptyRepeat(t *testing.T) {
+	var tests = []string{
+		"field n {\ntemplate = '1'\npattern = '\\d+'\n}\n---\na{{repeat: {{n:\\d*}}}}b\n",
+		"field n {\ntemplate = '1'\npattern = '\\d+'\n}\n---\na{{repeat: [[{{n:\\d+}}]]}}b\n",
+	}
+
+	for i, data := range tests {
+		rules := parseRules(t, data)
+
+		for _, line := range []string{"ab", "a12b"} {
+			if _, _, ok := rules.MatchFields(line); !ok {
+				t.Errorf("test %d: line %q not matched", i, line)
+			}
+		}
+	}
+}
+
+func TestNonCapturing(t *testing.T) {
+	var tests = []struct {
+		pattern string
+		match   string
+	}{
+		{`\d+`, "123"},
+		{`(\d{1,3}\.\d{1,3}\.\d{1,3}\.\d{1,3}|([0-9a-f]{0,4}:){0,7}[0-9a-f]{0,4})`, "2003::1"},
+		{`(?P<name>[a-z]+)(?i:x)`, "fooX"},
+	}
+
+	for i, test := range tests {
+		re := regexp.MustCompile("^" + nonCapturing(regexp.MustCompile(test.pattern)) + "$")
+		if re.NumSubexp() != 0 {
+			t.Errorf("test %d: pattern %v still has %d groups", i, re, re.NumSubexp())
+		}
+
+		if !re.MatchString(test.match) {
+			t.Errorf("test %d: pattern %v does not match %q", i, re, test.match)
+		}
+	}
+}
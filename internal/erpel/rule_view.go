@@ -2,7 +2,14 @@ package erpel
 
 import (
 	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"sort"
+	"strconv"
 	"strings"
+
+	"github.com/fd0/erpel/internal/rules"
+	"github.com/pkg/errors"
 )
 
 // RuleView is the semantic representation of a rule, constructed by replacing
@@ -31,54 +38,452 @@ func (fv FieldView) String() string {
 	return "[" + fv.F.Name + "]"
 }
 
-func applyField(field Field, data RuleView, global bool) (result RuleView) {
-	for _, item := range data {
-		str, ok := item.(Text)
-		if !ok {
-			result = append(result, item)
-			continue
+// OptionalView is used within a RuleView for an optional section.
+type OptionalView RuleView
+
+func (ov OptionalView) String() string {
+	return "[[" + RuleView(ov).String() + "]]"
+}
+
+// AlternationView is used within a RuleView for a list of alternatives.
+type AlternationView []RuleView
+
+func (av AlternationView) String() string {
+	list := make([]string, 0, len(av))
+	for _, v := range av {
+		list = append(list, v.String())
+	}
+
+	return "(" + strings.Join(list, "|") + ")"
+}
+
+// RepeatView is used within a RuleView for a section which is repeated one or
+// more times.
+type RepeatView struct {
+	V         RuleView
+	Separator string
+}
+
+func (rv RepeatView) String() string {
+	s := "{{repeat: " + rv.V.String()
+	if rv.Separator != "" {
+		s += ", sep=" + strconv.Quote(rv.Separator)
+	}
+
+	return s + "}}"
+}
+
+// WildcardView is used within a RuleView for a wildcard, which matches any
+// text.
+type WildcardView struct{}
+
+func (WildcardView) String() string {
+	return "..."
+}
+
+// RegexpView is used within a RuleView for a raw regular expression. The
+// Text items within are regexp syntax and not matched verbatim.
+type RegexpView RuleView
+
+func (rv RegexpView) String() string {
+	return "re:" + RuleView(rv).String()
+}
+
+func (v RuleView) String() string {
+	var s string
+	for _, item := range v {
+		s += item.String()
+	}
+
+	return s
+}
+
+// fieldTemplate is a field whose template is searched for in the text of
+// message templates.
+type fieldTemplate struct {
+	field  Field
+	global bool
+}
+
+// fieldTemplates returns all fields which have a template in the order in
+// which they are tried: longer templates first, then local fields before
+// global ones, then ordered by name.
+func (r *Rules) fieldTemplates() []fieldTemplate {
+	var fts []fieldTemplate
+
+	for _, f := range r.Fields {
+		if f.Template != "" {
+			fts = append(fts, fieldTemplate{field: f})
+		}
+	}
+
+	for _, f := range r.GlobalFields {
+		if f.Template != "" {
+			fts = append(fts, fieldTemplate{field: f, global: true})
+		}
+	}
+
+	sort.Slice(fts, func(i, j int) bool {
+		a, b := fts[i], fts[j]
+
+		if len(a.field.Template) != len(b.field.Template) {
+			return len(a.field.Template) > len(b.field.Template)
+		}
+
+		if a.global != b.global {
+			return !a.global
+		}
+
+		return a.field.Name < b.field.Name
+	})
+
+	return fts
+}
+
+// substitute splits text into verbatim text and fields. The text is scanned
+// once from left to right, at each position the longest field template found
+// there is used, and scanning continues after it. When a local and a global
+// field have the same template, the local field is used.
+//
+// The result is always complete, but an error is returned when the
+// substitution is ambiguous: when two fields of the same scope have the same
+// template, or when a template found at a later position within the one used
+// extends beyond its end.
+func substitute(text string, fts []fieldTemplate) (view RuleView, err error) {
+	var verbatim string
+
+	for i := 0; i < len(text); {
+		var (
+			found *fieldTemplate
+			n     int
+		)
+
+		for j := range fts {
+			ft := &fts[j]
+			tmpl := ft.field.Template
+
+			if !strings.HasPrefix(text[i:], tmpl) {
+				continue
+			}
+
+			if found == nil {
+				found = ft
+				n = len(tmpl)
+				continue
+			}
+
+			if len(tmpl) < n || ft.global != found.global {
+				break
+			}
+
+			if err == nil {
+				err = errors.Errorf("fields %v and %v have the same template %q",
+					found.field.Name, ft.field.Name, tmpl)
+			}
 		}
 
-		matches := strings.Split(string(str), field.Template)
-		l := len(matches)
-		if l == 1 {
-			result = append(result, str)
+		if found == nil {
+			verbatim += text[i : i+1]
+			i++
 			continue
 		}
 
-		for _, s := range matches[:l-1] {
-			// if s is the empty string, the template was found at the
-			// beginning of the string, so we don't need to add the string
-			// itself.
-			if s != "" {
-				// Otherwise, append the string to result.
-				result = append(result, Text(s))
+		for k := i + 1; k < i+n && err == nil; k++ {
+			for _, ft := range fts {
+				tmpl := ft.field.Template
+				if k+len(tmpl) > i+n && strings.HasPrefix(text[k:], tmpl) {
+					err = errors.Errorf("templates of fields %v (%q) and %v (%q) overlap in %q",
+						found.field.Name, found.field.Template, ft.field.Name, tmpl, text)
+					break
+				}
 			}
-			result = append(result, FieldView{S: field.Template, F: field, Global: global})
 		}
 
-		last := matches[l-1]
-		// if last is no the empty string, there is a text segment trailing, so add it.
-		if last != "" {
-			result = append(result, Text(matches[l-1]))
+		if verbatim != "" {
+			view = append(view, Text(verbatim))
+			verbatim = ""
 		}
+
+		view = append(view, FieldView{S: found.field.Template, F: found.field, Global: found.global})
+		i += n
+	}
+
+	if verbatim != "" {
+		view = append(view, Text(verbatim))
 	}
 
-	return result
+	return view, err
 }
 
-// View renders a template into a RuleView by applying the rules.
-func View(rules Rules, template string) RuleView {
-	data := RuleView{Text(rules.Prefix + template)}
+// view renders the parts of a template into a RuleView. Verbatim text is
+// split with substitute(), placeholders are added as fields. The returned
+// view is complete even if an error is returned.
+func (r *Rules) view(parts []rules.Part) (data RuleView, err error) {
+	fts := r.fieldTemplates()
 
-	for _, field := range rules.Fields {
-		data = applyField(field, data, false)
+	for _, part := range parts {
+		switch part.Type {
+		case rules.TextPart:
+			v, e := substitute(part.Text, fts)
+			if e != nil && err == nil {
+				err = e
+			}
+			data = append(data, v...)
+		case rules.PlaceholderPart:
+			f, global, e := r.placeholderField(part)
+			if e != nil && err == nil {
+				err = e
+			}
+			data = append(data, FieldView{S: placeholderText(part), F: f, Global: global})
+		case rules.OptionalPart:
+			v, e := r.view(part.Parts)
+			if e != nil && err == nil {
+				err = e
+			}
+			data = append(data, OptionalView(v))
+		case rules.AlternationPart:
+			var av AlternationView
+			for _, alt := range part.Alternatives {
+				v, e := r.view(alt)
+				if e != nil && err == nil {
+					err = e
+				}
+				av = append(av, v)
+			}
+			data = append(data, av)
+		case rules.RepeatPart:
+			v, e := r.view(part.Parts)
+			if e != nil && err == nil {
+				err = e
+			}
+
+			sep, e := unquoteString(part.Separator)
+			if e != nil && err == nil {
+				err = e
+			}
+
+			data = append(data, RepeatView{V: v, Separator: sep})
+		case rules.WildcardPart:
+			data = append(data, WildcardView{})
+		}
 	}
 
-	for _, field := range rules.GlobalFields {
-		data = applyField(field, data, true)
+	return data, err
+}
+
+// findBackreference returns the first backreference like \1 or (?P=name)
+// within the regexp s, or an empty string. RE2 does not support them.
+func findBackreference(s string) string {
+	for i := 0; i < len(s); i++ {
+		if strings.HasPrefix(s[i:], "(?P=") {
+			end := strings.IndexByte(s[i:], ')')
+			if end < 0 {
+				return s[i:]
+			}
+			return s[i : i+end+1]
+		}
+
+		if s[i] != '\\' || i+1 == len(s) {
+			continue
+		}
+
+		if c := s[i+1]; (c >= '1' && c <= '9') || c == 'k' {
+			return s[i : i+2]
+		}
+
+		// skip the escaped character
+		i++
+	}
+
+	return ""
+}
+
+// rawView renders the parts of a raw template, text parts are kept as they
+// are and placeholders are added as fields.
+func (r *Rules) rawView(parts []rules.Part) (data RegexpView, err error) {
+	for _, part := range parts {
+		switch part.Type {
+		case rules.TextPart:
+			if ref := findBackreference(part.Text); ref != "" && err == nil {
+				err = errors.Errorf("backreference %v is not supported, regular expressions use the RE2 syntax", ref)
+			}
+			data = append(data, Text(part.Text))
+		case rules.PlaceholderPart:
+			f, global, e := r.placeholderField(part)
+			if e != nil && err == nil {
+				err = e
+			}
+			data = append(data, FieldView{S: placeholderText(part), F: f, Global: global})
+		}
+	}
+
+	return data, err
+}
+
+// templateView renders a template without the prefix.
+func (r *Rules) templateView(t Template) (RuleView, error) {
+	if !t.Raw {
+		return r.view(t.Parts)
 	}
 
+	v, err := r.rawView(t.Parts)
+	return RuleView{v}, err
+}
+
+// prefixedView renders a template including the prefix. For templates which
+// are not raw, fields may span the border between prefix and template.
+func (r *Rules) prefixedView(prefix []rules.Part, t Template) (RuleView, error) {
+	if !t.Raw {
+		return r.view(joinParts(prefix, t.Parts))
+	}
+
+	pv, err := r.view(prefix)
+	if err != nil {
+		return pv, err
+	}
+
+	tv, err := r.templateView(t)
+	return append(pv, tv...), err
+}
+
+// captures collects the fields within the regexp of a template, every
+// occurrence of a field is captured in a named group. Repeated sections
+// containing fields are captured as a whole and matched element by element
+// afterwards, since a group within a repetition only holds the last value.
+type captures struct {
+	fields  []Field
+	repeats []repeatCapture
+}
+
+// repeatCapture is the regexp for a repeated section, it matches the first
+// element with the fields captured in c and the remaining elements in the
+// group named restName.
+type repeatCapture struct {
+	re string
+	c  *captures
+}
+
+// captureName is the prefix for the names of the groups.
+const captureName = "erpel_field"
+
+// repeatName is the prefix for the names of the groups for repeated sections,
+// restName the name of the group for the remaining elements.
+const (
+	repeatName = "erpel_repeat"
+	restName   = "erpel_rest"
+)
+
+// group returns the regexp for the field, a named group unless c is nil.
+// Groups within the pattern of the field are turned into non-capturing
+// groups, so they do not interfere with the groups for the fields.
+func (c *captures) group(f Field) string {
+	if c == nil {
+		return "(?:" + f.Pattern.String() + ")"
+	}
+
+	c.fields = append(c.fields, f)
+	return fmt.Sprintf("(?P<%s%d>%s)", captureName, len(c.fields)-1, nonCapturing(f.Pattern))
+}
+
+// repeat returns the regexp for the repeated section, a named group unless c
+// is nil or the section does not contain any fields.
+func (c *captures) repeat(rv RepeatView) string {
+	sep := regexp.QuoteMeta(rv.Separator)
+	elem := "(?:" + rv.V.regexp(nil) + ")"
+	all := elem + "(?:" + sep + elem + ")*"
+
+	if c == nil {
+		return all
+	}
+
+	inner := &captures{}
+	first := rv.V.regexp(inner)
+	if len(inner.fields) == 0 && len(inner.repeats) == 0 {
+		return all
+	}
+
+	c.repeats = append(c.repeats, repeatCapture{
+		re: fmt.Sprintf("^(?:%s)(?:%s(?P<%s>%s))?$", first, sep, restName, all),
+		c:  inner,
+	})
+	return fmt.Sprintf("(?P<%s%d>%s)", repeatName, len(c.repeats)-1, all)
+}
+
+// nonCapturing returns the pattern of re with all capturing groups replaced
+// by non-capturing groups.
+func nonCapturing(re *regexp.Regexp) string {
+	if re.NumSubexp() == 0 {
+		return re.String()
+	}
+
+	tree, err := syntax.Parse(re.String(), syntax.Perl)
+	if err != nil {
+		// cannot happen, the pattern has been compiled before
+		return re.String()
+	}
+
+	return stripCaptures(tree).String()
+}
+
+// stripCaptures removes all capturing groups from the tree.
+func stripCaptures(re *syntax.Regexp) *syntax.Regexp {
+	for i, sub := range re.Sub {
+		re.Sub[i] = stripCaptures(sub)
+	}
+
+	if re.Op == syntax.OpCapture {
+		return re.Sub[0]
+	}
+
+	return re
+}
+
+// regexp returns the regular expression for the view. Fields which need to
+// be validated are added to c unless it is nil.
+func (v RuleView) regexp(c *captures) string {
+	var s string
+
+	for _, item := range v {
+		switch item := item.(type) {
+		case Text:
+			s += regexp.QuoteMeta(string(item))
+		case FieldView:
+			s += c.group(item.F)
+		case OptionalView:
+			s += "(?:" + RuleView(item).regexp(c) + ")?"
+		case AlternationView:
+			list := make([]string, 0, len(item))
+			for _, v := range item {
+				list = append(list, v.regexp(c))
+			}
+			s += "(?:" + strings.Join(list, "|") + ")"
+		case RepeatView:
+			s += c.repeat(item)
+		case WildcardView:
+			s += ".*"
+		case RegexpView:
+			s += "(?:"
+			for _, item := range item {
+				switch item := item.(type) {
+				case Text:
+					s += string(item)
+				case FieldView:
+					s += c.group(item.F)
+				}
+			}
+			s += ")"
+		}
+	}
+
+	return s
+}
+
+// View renders a template into a RuleView by applying the rules.
+func View(r Rules, template Template) RuleView {
+	// errors are reported when the rules are parsed
+	prefix, _ := parsePrefix(r.Prefix)
+	data, _ := r.prefixedView(prefix, template)
+
 	return data
 }
 
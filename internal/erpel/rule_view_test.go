@@ -40,6 +40,10 @@ field num {
     pattern = '\d+'
 }
 
+field session {
+    pattern = '[a-zA-Z0-9+/]+'
+}
+
 ------------------
 
 Jun  2 23:17:13 mail dovecot: lda(user@host.tld): sieve: msgid=<20160602211704.9125E5A063@localhost>: stored mail into mailbox 'INBOX'
@@ -76,6 +80,36 @@ var ruleViewTests = []struct {
 			"[num]", "/", "[num]",
 		},
 	},
+	{
+		template: "Jun  2 23:17:13 mail dovecot: IMAP({{username}}): session=<{{session}}>, mpid={{num:\\d{1,3}}} \\{{num}}",
+		result: []string{
+			"{timestamp}",
+			" mail dovecot: IMAP(",
+			"[username]",
+			"): session=<",
+			"[session]",
+			">, mpid=",
+			"[num]",
+			" {{num}}",
+		},
+	},
+	{
+		template: "IMAP(username@domain.tld): (Logged out|Disconnected)[[ bytes=123/123]]",
+		result: []string{
+			"IMAP(",
+			"[username]",
+			"): ",
+			"(Logged out|Disconnected)",
+			"[[ bytes=[num]/[num]]]",
+		},
+	},
+	{
+		template: `to={{repeat: <user@host.tld>, sep=", "}}`,
+		result: []string{
+			"to=",
+			`{{repeat: <[mailaddress]>, sep=", "}}`,
+		},
+	},
 	{
 		template: "foobar dovecot: IMAP(username@domain.tld): Disconnected: Logged out bytes=123/123",
 		result: []string{
@@ -109,7 +143,13 @@ func TestRuleView(t *testing.T) {
 	rules := parseRules(t, ruleViewTestConfig)
 
 	for i, test := range ruleViewTests {
-		res := View(rules, test.template)
+		tmpl, err := ParseTemplate(test.template)
+		if err != nil {
+			t.Errorf("test %d: ParseTemplate(): %v", i, err)
+			continue
+		}
+
+		res := View(rules, tmpl)
 
 		max := len(test.result)
 		if len(res) != max {
@@ -0,0 +1,188 @@
+package erpel
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// DefaultExclude lists the patterns for files which are not rules files by
+// default, like backups of editors and files left by package managers.
+var DefaultExclude = []string{
+	"*~", "#*#", "*.swp", "*.bak", "*.orig", "*.rej",
+	"*.dpkg-old", "*.dpkg-new", "*.dpkg-dist", "*.dpkg-bak", "*.dpkg-tmp",
+	"*.rpmnew", "*.rpmsave", "*.rpmorig", "*.pacnew", "*.pacsave",
+	"*.ucf-old", "*.ucf-new", "*.ucf-dist",
+}
+
+// FileFilter selects the rules files within the rules directories. Patterns
+// are matched against the file name and the path relative to the directory,
+// with the syntax of filepath.Match.
+type FileFilter struct {
+	// Recursive is set if subdirectories are searched, they are skipped
+	// otherwise.
+	Recursive bool
+
+	// Include lists the patterns for rules files, all files are included
+	// if it is empty.
+	Include []string
+
+	// Exclude lists the patterns for files which are skipped.
+	Exclude []string
+}
+
+// matchAny returns true if name or the base name of name matches one of the
+// patterns.
+func matchAny(patterns []string, name string) (bool, error) {
+	for _, pattern := range patterns {
+		for _, s := range []string{filepath.Base(name), name} {
+			ok, err := filepath.Match(pattern, s)
+			if err != nil {
+				return false, errors.Errorf("invalid pattern %q", pattern)
+			}
+
+			if ok {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// fieldsExt is the extension of files with fields included by rules files.
+const fieldsExt = ".fields"
+
+// selects returns true if the file with the name relative to the rules
+// directory is a rules file. Hidden files and included field files are always
+// skipped.
+func (f FileFilter) selects(name string) (bool, error) {
+	if strings.HasPrefix(filepath.Base(name), ".") || filepath.Ext(name) == fieldsExt {
+		return false, nil
+	}
+
+	excluded, err := matchAny(f.Exclude, name)
+	if err != nil || excluded {
+		return false, err
+	}
+
+	if len(f.Include) == 0 {
+		return true, nil
+	}
+
+	return matchAny(f.Include, name)
+}
+
+// RulesFile is a rules file found in a rules directory.
+type RulesFile struct {
+	// Name is the path relative to the rules directory, with slashes as
+	// separators.
+	Name string
+
+	// Dir is the rules directory the file was found in.
+	Dir string
+
+	Filename string
+}
+
+// findFiles returns the files in dir selected by the filter, indexed by the
+// path relative to dir. A directory which does not exist contains no files.
+func findFiles(dir string, f FileFilter) (map[string]string, error) {
+	files := make(map[string]string)
+
+	err := filepath.Walk(dir, func(path string, fi os.FileInfo, err error) error {
+		if os.IsNotExist(err) && path == dir {
+			return filepath.SkipDir
+		}
+
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if path == dir {
+			return nil
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if fi.IsDir() {
+			if !f.Recursive || strings.HasPrefix(fi.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		ok, err := f.selects(name)
+		if err != nil || !ok {
+			return err
+		}
+
+		files[filepath.ToSlash(name)] = path
+		return nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	return files, nil
+}
+
+// FindRulesFiles returns the rules files in the directories selected by the
+// filter, sorted by the path relative to the directory. A file in a later
+// directory overrides the file with the same relative path in an earlier
+// directory.
+func FindRulesFiles(dirs []string, f FileFilter) ([]RulesFile, error) {
+	for _, pattern := range append(f.Include, f.Exclude...) {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return nil, errors.Errorf("invalid pattern %q", pattern)
+		}
+	}
+
+	found := make(map[string]RulesFile)
+	for _, dir := range dirs {
+		files, err := findFiles(dir, f)
+		if err != nil {
+			return nil, err
+		}
+
+		for name, path := range files {
+			found[name] = RulesFile{Name: name, Dir: dir, Filename: path}
+		}
+	}
+
+	names := make([]string, 0, len(found))
+	for name := range found {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	files := make([]RulesFile, 0, len(names))
+	for _, name := range names {
+		files = append(files, found[name])
+	}
+
+	return files, nil
+}
+
+// RulesFiles returns the names of the rules files in dir, hidden files and
+// the files matched by DefaultExclude are skipped.
+func RulesFiles(dir string) ([]string, error) {
+	files, err := FindRulesFiles([]string{dir}, FileFilter{Exclude: DefaultExclude})
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(files))
+	for _, f := range files {
+		names = append(names, f.Filename)
+	}
+
+	return names, nil
+}
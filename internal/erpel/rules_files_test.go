@@ -0,0 +1,111 @@
+package erpel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// createFiles creates empty files with the names relative to dir.
+func createFiles(t *testing.T, dir string, names []string) {
+	for _, name := range names {
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := ioutil.WriteFile(filename, nil, 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+var testFindRulesFiles = []struct {
+	dirs   []string
+	filter FileFilter
+	files  []string
+}{
+	{
+		dirs:   []string{"a"},
+		filter: FileFilter{Exclude: DefaultExclude},
+		files:  []string{"a/dovecot", "a/postfix"},
+	},
+	{
+		dirs:  []string{"a"},
+		files: []string{"a/dovecot", "a/dovecot~", "a/postfix", "a/postfix.dpkg-old"},
+	},
+	{
+		dirs:   []string{"a", "b"},
+		filter: FileFilter{Exclude: DefaultExclude},
+		files:  []string{"b/dovecot", "a/postfix", "b/sshd"},
+	},
+	{
+		dirs:   []string{"b", "a"},
+		filter: FileFilter{Exclude: DefaultExclude},
+		files:  []string{"a/dovecot", "a/postfix", "b/sshd"},
+	},
+	{
+		dirs:   []string{"a", "missing"},
+		filter: FileFilter{Recursive: true, Exclude: DefaultExclude},
+		files:  []string{"a/dovecot", "a/mail/exim", "a/postfix"},
+	},
+	{
+		dirs:   []string{"a"},
+		filter: FileFilter{Recursive: true, Include: []string{"mail/*", "dovecot"}},
+		files:  []string{"a/dovecot", "a/mail/exim"},
+	},
+	{
+		dirs:   []string{"a", "b"},
+		filter: FileFilter{Include: []string{"*o*"}, Exclude: []string{"*~", "post*"}},
+		files:  []string{"b/dovecot"},
+	},
+}
+
+func TestFindRulesFiles(t *testing.T) {
+	dir, err := ioutil.TempDir("", "erpel-rules-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	createFiles(t, dir, []string{
+		"a/dovecot", "a/dovecot~", "a/.hidden", "a/postfix", "a/postfix.dpkg-old",
+		"a/mail/exim", "a/.git/config", "a/fields/mail.fields",
+		"b/dovecot", "b/sshd",
+	})
+
+	for i, test := range testFindRulesFiles {
+		var dirs []string
+		for _, d := range test.dirs {
+			dirs = append(dirs, filepath.Join(dir, d))
+		}
+
+		files, err := FindRulesFiles(dirs, test.filter)
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+
+		var want []RulesFile
+		for _, f := range test.files {
+			// the name is relative to the rules directory
+			i := strings.Index(f, "/")
+			want = append(want, RulesFile{
+				Name:     f[i+1:],
+				Dir:      filepath.Join(dir, f[:i]),
+				Filename: filepath.Join(dir, filepath.FromSlash(f)),
+			})
+		}
+
+		if !reflect.DeepEqual(files, want) {
+			t.Errorf("test %d: wrong files, want:\n  %v\ngot:\n  %v", i, want, files)
+		}
+	}
+
+	if _, err := FindRulesFiles([]string{dir}, FileFilter{Include: []string{"["}}); err == nil {
+		t.Errorf("expected error for invalid pattern not found")
+	}
+}
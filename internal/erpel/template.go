@@ -0,0 +1,329 @@
+package erpel
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/fd0/erpel/internal/rules"
+	"github.com/pkg/errors"
+)
+
+// Template is a message template from a rules file. Dynamic parts of a
+// message are either marked by the template of a field, which is replaced by
+// the field's pattern, or by an explicit placeholder like {{name}} or
+// {{name:pattern}}.
+type Template struct {
+	// Text is the template as written in the rules file.
+	Text string
+
+	Parts []rules.Part
+
+	// Match configures which part of a message the template must match.
+	Match MatchMode
+
+	// Raw is set for templates which are regular expressions.
+	Raw bool
+
+	// LineNumber is the line of the template in the rules file, it is zero
+	// if unknown.
+	LineNumber int
+
+	// Category and Severity override the values from the rules file.
+	Category string
+	Severity string
+
+	// Threshold is set for templates whose messages are only ignored as
+	// long as the threshold is not exceeded. The messages are counted per
+	// value of the field GroupBy, if set.
+	Threshold *Threshold
+	GroupBy   string
+
+	// Sequence is the name of a sequence of templates which needs to be
+	// completed within a window, the messages are linked by the value of
+	// the field Key. Within is set for the first step of the sequence.
+	Sequence string
+	Key      string
+	Within   *Window
+
+	// Expect is set for messages which are expected to be seen regularly,
+	// an alert is raised when the message is missing.
+	Expect *Schedule
+
+	// ID identifies the template within the rules file, it is set with the
+	// annotation @id. Templates without an ID are identified by a hash,
+	// see Rules.TemplateID.
+	ID string
+
+	// Active restricts the times the template is applied.
+	Active *ActiveSchedule
+
+	// Expires is the date after which the template is not applied any
+	// more, it is zero if it does not expire.
+	Expires time.Time
+
+	// Metadata documents the template.
+	Metadata Metadata
+
+	// step is the index of the template within the sequence
+	step int
+}
+
+// position returns a description of the template for error messages.
+func (t Template) position() string {
+	if t.LineNumber == 0 {
+		return t.Text
+	}
+
+	return fmt.Sprintf("line %d: %v", t.LineNumber, t.Text)
+}
+
+// MatchMode defines which part of a message a template needs to match.
+type MatchMode int
+
+// These are the match modes for templates.
+const (
+	// MatchDefault uses the match mode of the rules file, which defaults
+	// to MatchFull.
+	MatchDefault MatchMode = iota
+
+	// MatchFull requires the template to match the whole message.
+	MatchFull
+
+	// MatchPrefix requires the template to match the beginning of the
+	// message, directly after the prefix of the rules file.
+	MatchPrefix
+
+	// MatchContains requires the template to match anywhere after the
+	// prefix of the rules file.
+	MatchContains
+)
+
+var matchModes = map[string]MatchMode{
+	"full":     MatchFull,
+	"prefix":   MatchPrefix,
+	"contains": MatchContains,
+}
+
+// parseMatchMode returns the match mode for the name.
+func parseMatchMode(s string) (MatchMode, error) {
+	m, ok := matchModes[s]
+	if !ok {
+		return MatchDefault, errors.Errorf("invalid match mode %q", s)
+	}
+
+	return m, nil
+}
+
+func (m MatchMode) String() string {
+	for name, mode := range matchModes {
+		if mode == m {
+			return name
+		}
+	}
+
+	return "default"
+}
+
+// ParseTemplate parses s as a template.
+func ParseTemplate(s string) (Template, error) {
+	t, err := rules.ParseTemplate(s)
+	if err != nil {
+		return Template{}, err
+	}
+
+	return Template{Text: t.Line, Parts: t.Parts, Raw: t.Raw}, nil
+}
+
+// parseTemplate returns a Template for t, the annotations are evaluated.
+func parseTemplate(t rules.Template) (Template, error) {
+	tmpl := Template{
+		Text:       t.Line,
+		Parts:      t.Parts,
+		Raw:        t.Raw,
+		LineNumber: t.LineNumber,
+	}
+
+	for key, value := range t.Options {
+		v, err := unquoteString(value)
+		if err != nil {
+			return Template{}, errors.WithMessage(err, value)
+		}
+
+		switch key {
+		case "match":
+			tmpl.Match, err = parseMatchMode(v)
+			if err != nil {
+				return Template{}, err
+			}
+		case "category":
+			tmpl.Category = v
+		case "severity":
+			if err = checkSeverity(v); err != nil {
+				return Template{}, err
+			}
+			tmpl.Severity = v
+		case "threshold":
+			th, err := parseThreshold(v)
+			if err != nil {
+				return Template{}, err
+			}
+			tmpl.Threshold = &th
+		case "group_by":
+			tmpl.GroupBy = v
+		case "sequence":
+			tmpl.Sequence = v
+		case "key":
+			tmpl.Key = v
+		case "expect":
+			sched, err := parseSchedule(v)
+			if err != nil {
+				return Template{}, err
+			}
+			tmpl.Expect = &sched
+		case "id":
+			tmpl.ID = v
+		case "active":
+			tmpl.Active, err = parseActive(v)
+			if err != nil {
+				return Template{}, err
+			}
+		case "expires":
+			tmpl.Expires, err = parseExpires(v)
+			if err != nil {
+				return Template{}, err
+			}
+		case "within":
+			w, err := parseWindow(v)
+			if err != nil {
+				return Template{}, err
+			}
+			tmpl.Within = &w
+		default:
+			ok, err := tmpl.Metadata.set(key, value)
+			if err != nil {
+				return Template{}, err
+			}
+
+			if !ok {
+				return Template{}, errors.Errorf("unknown annotation %q", key)
+			}
+		}
+	}
+
+	if tmpl.GroupBy != "" && tmpl.Threshold == nil {
+		return Template{}, errors.New("group_by needs a threshold")
+	}
+
+	if (tmpl.Key != "" || tmpl.Within != nil) && tmpl.Sequence == "" {
+		return Template{}, errors.New("key and within need a sequence")
+	}
+
+	if tmpl.Sequence != "" && tmpl.Key == "" {
+		return Template{}, errors.Errorf("sequence %q needs a key", tmpl.Sequence)
+	}
+
+	if tmpl.Sequence != "" && tmpl.Threshold != nil {
+		return Template{}, errors.New("a template in a sequence cannot have a threshold")
+	}
+
+	return tmpl, nil
+}
+
+// parsePrefix parses the prefix of a rules file. In contrast to templates,
+// leading and trailing white space is significant.
+func parsePrefix(s string) ([]rules.Part, error) {
+	t, err := rules.ParseTemplate(s)
+	if err != nil {
+		return nil, err
+	}
+
+	trimmed := strings.TrimLeft(s, " \t")
+	leading := s[:len(s)-len(trimmed)]
+	trailing := trimmed[len(t.Line):]
+
+	parts := t.Parts
+	parts = joinParts([]rules.Part{{Type: rules.TextPart, Text: leading}}, parts)
+	parts = joinParts(parts, []rules.Part{{Type: rules.TextPart, Text: trailing}})
+
+	return parts, nil
+}
+
+// joinParts returns the concatenation of a and b. Text parts at the boundary
+// are merged, so that field templates are found across it. Empty text parts
+// are removed.
+func joinParts(a, b []rules.Part) []rules.Part {
+	parts := make([]rules.Part, 0, len(a)+len(b))
+
+	for _, part := range append(a[:len(a):len(a)], b...) {
+		if part.Type != rules.TextPart {
+			parts = append(parts, part)
+			continue
+		}
+
+		if part.Text == "" {
+			continue
+		}
+
+		l := len(parts)
+		if l > 0 && parts[l-1].Type == rules.TextPart {
+			parts[l-1].Text += part.Text
+			continue
+		}
+
+		parts = append(parts, part)
+	}
+
+	return parts
+}
+
+// placeholderText returns the source text for a placeholder.
+func placeholderText(part rules.Part) string {
+	if part.Pattern != "" {
+		return "{{" + part.Text + ":" + part.Pattern + "}}"
+	}
+
+	return "{{" + part.Text + "}}"
+}
+
+// lookupField returns the field with the given name. Local fields take
+// precedence over global fields.
+func (r *Rules) lookupField(name string) (f Field, global bool, ok bool) {
+	if f, ok := r.Fields[name]; ok {
+		return f, false, true
+	}
+
+	if f, ok := r.GlobalFields[name]; ok {
+		return f, true, true
+	}
+
+	return Field{}, false, false
+}
+
+// placeholderField returns the field for a placeholder. When the placeholder
+// sets a pattern, it is used instead of the pattern of the field, and the
+// field does not need to be defined. The returned field always has the name
+// set.
+func (r *Rules) placeholderField(part rules.Part) (Field, bool, error) {
+	f, global, ok := r.lookupField(part.Text)
+	f.Name = part.Text
+
+	if part.Pattern == "" {
+		if !ok {
+			return f, global, errors.Errorf("unknown field %q in placeholder %v", part.Text, placeholderText(part))
+		}
+
+		return f, global, nil
+	}
+
+	pattern, err := regexp.Compile(part.Pattern)
+	if err != nil {
+		return f, global, errors.WithMessage(err, placeholderText(part))
+	}
+
+	f.Pattern = pattern
+	f.Samples = nil
+
+	return f, global, nil
+}
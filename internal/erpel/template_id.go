@@ -0,0 +1,70 @@
+package erpel
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// validID matches IDs set explicitly with the annotation @id.
+var validID = regexp.MustCompile(`^[a-zA-Z0-9_.-]+$`)
+
+// hashLength is the number of hex digits of the hash used as the ID for
+// templates without an explicit ID.
+const hashLength = 8
+
+// localID returns the ID of the template within the rules file, either the
+// one set with @id or a hash of the template text. Whitespace is normalized
+// before hashing, so the ID only changes when the template itself changes.
+func (t Template) localID() string {
+	if t.ID != "" {
+		return t.ID
+	}
+
+	text := strings.Join(strings.Fields(t.Text), " ")
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])[:hashLength]
+}
+
+// TemplateID returns the stable ID of the template, which consists of the
+// name of the rules file and the ID within the file, e.g. "dovecot/login" or
+// "dovecot/3fa9c1d2".
+func (r *Rules) TemplateID(t Template) string {
+	return r.idPrefix() + t.localID()
+}
+
+// idPrefix returns the prefix of the IDs of all templates of the rules, it is
+// empty for rules not loaded from a file.
+func (r *Rules) idPrefix() string {
+	if r.Filename == "" {
+		return ""
+	}
+
+	return r.name() + "/"
+}
+
+// idPrefixOf returns the prefix of the template ID as returned by idPrefix.
+func idPrefixOf(id string) string {
+	return id[:strings.LastIndex(id, "/")+1]
+}
+
+// checkIDs returns an error if two templates have the same ID.
+func (r *Rules) checkIDs() error {
+	seen := make(map[string]Template, len(r.Templates))
+	for _, t := range r.Templates {
+		if t.ID != "" && !validID.MatchString(t.ID) {
+			return errors.Errorf("%v: invalid ID %q, only letters, digits, '_', '.' and '-' are allowed", t.position(), t.ID)
+		}
+
+		id := t.localID()
+		if other, ok := seen[id]; ok {
+			return errors.Errorf("%v: ID %q is already used for %v", t.position(), id, other.position())
+		}
+		seen[id] = t
+	}
+
+	return nil
+}
@@ -0,0 +1,74 @@
+package erpel
+
+import (
+	"testing"
+)
+
+func templateIDs(t *testing.T, data string) map[string]string {
+	rules, err := ParseRules(nil, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename = "/etc/erpel/rules.d/dovecot"
+
+	ids := make(map[string]string)
+	for _, tmpl := range rules.Templates {
+		ids[tmpl.Text] = rules.TemplateID(tmpl)
+	}
+
+	return ids
+}
+
+func TestTemplateID(t *testing.T) {
+	ids := templateIDs(t, "---\nfoo bar\n@id = 'login'\nlogin ok\nbaz\n")
+	reordered := templateIDs(t, "---\nbaz\n\nfoo   bar\n@id = 'login'\nlogin ok\n")
+
+	if ids["login ok"] != "dovecot/login" {
+		t.Errorf("wrong ID for explicit template: %v", ids["login ok"])
+	}
+
+	for _, text := range []string{"baz", "login ok"} {
+		if ids[text] != reordered[text] {
+			t.Errorf("ID for %q changed after reordering: %v != %v", text, ids[text], reordered[text])
+		}
+	}
+
+	// whitespace within the template is normalized
+	if ids["foo bar"] != reordered["foo   bar"] {
+		t.Errorf("ID changed with whitespace: %v != %v", ids["foo bar"], reordered["foo   bar"])
+	}
+
+	if ids["foo bar"] == ids["baz"] {
+		t.Errorf("same ID for different templates: %v", ids["foo bar"])
+	}
+
+	if len(ids["baz"]) != len("dovecot/")+hashLength {
+		t.Errorf("unexpected ID %q", ids["baz"])
+	}
+
+	// files within subdirectories of the rules directory use the relative path
+	rules, err := ParseRules(nil, "---\n@id = 'login'\nlogin ok\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rules.Filename, rules.Name = "/etc/erpel/rules.d/mail/dovecot", "mail/dovecot"
+
+	if id := rules.TemplateID(rules.Templates[0]); id != "mail/dovecot/login" {
+		t.Errorf("wrong ID for template in subdirectory: %v", id)
+	}
+}
+
+var testInvalidIDs = []string{
+	"---\n@id = 'a'\nfoo\n@id = 'a'\nbar\n",
+	"---\nfoo\nfoo\n",
+	"---\n@id = 'a b'\nfoo\n",
+	"---\n@id = 'a/b'\nfoo\n",
+}
+
+func TestTemplateIDInvalid(t *testing.T) {
+	for i, data := range testInvalidIDs {
+		if _, err := ParseRules(nil, data); err == nil {
+			t.Errorf("test %d: expected error not found", i)
+		}
+	}
+}
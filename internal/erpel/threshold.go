@@ -0,0 +1,189 @@
+package erpel
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Threshold is the number of messages matched by a template within a time
+// window which are ignored. When more messages are matched, a summary is
+// reported.
+type Threshold struct {
+	Count  int
+	Window time.Duration
+}
+
+func (t Threshold) String() string {
+	return fmt.Sprintf("%d/%v", t.Count, t.Window)
+}
+
+// parseDuration parses a duration like time.ParseDuration, in addition days
+// can be specified with the suffix "d".
+func parseDuration(s string) (time.Duration, error) {
+	if strings.HasSuffix(s, "d") {
+		days, err := strconv.Atoi(strings.TrimSuffix(s, "d"))
+		if err != nil {
+			return 0, errors.Errorf("invalid duration %q", s)
+		}
+
+		return time.Duration(days) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	return d, nil
+}
+
+// parseThreshold parses a threshold like "50/10m".
+func parseThreshold(s string) (Threshold, error) {
+	data := strings.SplitN(s, "/", 2)
+	if len(data) != 2 {
+		return Threshold{}, errors.Errorf("invalid threshold %q, format is count/duration", s)
+	}
+
+	count, err := strconv.Atoi(strings.TrimSpace(data[0]))
+	if err != nil || count < 0 {
+		return Threshold{}, errors.Errorf("invalid count in threshold %q", s)
+	}
+
+	window, err := parseDuration(strings.TrimSpace(data[1]))
+	if err != nil || window < time.Second {
+		return Threshold{}, errors.Errorf("invalid duration in threshold %q", s)
+	}
+
+	return Threshold{Count: count, Window: window}, nil
+}
+
+// counter records the times of the messages within the current window.
+type counter struct {
+	Times    []int64 `json:"times"`
+	Reported int64   `json:"reported,omitempty"`
+	Window   int64   `json:"window"`
+}
+
+// Counters keep track of the messages matched by templates with a
+// threshold. They are saved to the state directory, so that windows can span
+// several runs.
+type Counters struct {
+	Entries map[string]*counter `json:"entries"`
+}
+
+// NewCounters returns a new, empty Counters.
+func NewCounters() *Counters {
+	return &Counters{Entries: make(map[string]*counter)}
+}
+
+// LoadCounters reads counters from the file. If the file does not exist,
+// empty counters are returned.
+func LoadCounters(filename string) (*Counters, error) {
+	buf, err := ioutil.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return NewCounters(), nil
+	}
+
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	c := NewCounters()
+	if err = json.Unmarshal(buf, c); err != nil {
+		return nil, errors.WithMessage(err, filename)
+	}
+
+	if c.Entries == nil {
+		c.Entries = make(map[string]*counter)
+	}
+
+	return c, nil
+}
+
+// Save removes all expired counters and writes the rest to the file.
+func (c *Counters) Save(filename string, now time.Time) error {
+	for key, cnt := range c.Entries {
+		cnt.expire(now.Unix())
+		if len(cnt.Times) == 0 && now.Unix()-cnt.Reported >= cnt.Window {
+			delete(c.Entries, key)
+		}
+	}
+
+	buf, err := json.Marshal(c)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return errors.WithStack(ioutil.WriteFile(filename, buf, 0600))
+}
+
+// expire removes the times which are outside of the window.
+func (cnt *counter) expire(now int64) {
+	i := 0
+	for i < len(cnt.Times) && now-cnt.Times[i] >= cnt.Window {
+		i++
+	}
+
+	cnt.Times = cnt.Times[i:]
+}
+
+// add records a message for the key. If the threshold is exceeded and no
+// summary has been reported within the window, report is true and count is
+// the number of messages within the window.
+func (c *Counters) add(key string, th Threshold, now time.Time) (count int, report bool) {
+	cnt, ok := c.Entries[key]
+	if !ok {
+		cnt = &counter{}
+		c.Entries[key] = cnt
+	}
+	cnt.Window = int64(th.Window / time.Second)
+
+	ts := now.Unix()
+	cnt.expire(ts)
+	cnt.Times = append(cnt.Times, ts)
+
+	if len(cnt.Times) <= th.Count {
+		return len(cnt.Times), false
+	}
+
+	if cnt.Reported != 0 && ts-cnt.Reported < cnt.Window {
+		return len(cnt.Times), false
+	}
+
+	cnt.Reported = ts
+	return len(cnt.Times), true
+}
+
+// thresholdKey returns the key for the counter of a template and the value of
+// the field the messages are grouped by.
+func thresholdKey(r *Rules, t Template, group string) string {
+	return r.TemplateID(t) + "\x00" + group
+}
+
+// migrate renames the counters saved with the keys used before template IDs,
+// which started with the filename and the text of the template.
+func (c *Counters) migrate(rules []Rules) {
+	for i := range rules {
+		r := &rules[i]
+		for _, t := range r.Templates {
+			if t.Threshold == nil {
+				continue
+			}
+
+			prefix := r.Filename + "\x00" + t.Text + "\x00"
+			for key, cnt := range c.Entries {
+				if strings.HasPrefix(key, prefix) {
+					delete(c.Entries, key)
+					c.Entries[thresholdKey(r, t, strings.TrimPrefix(key, prefix))] = cnt
+				}
+			}
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package erpel
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var thresholdTests = []struct {
+	s   string
+	th  Threshold
+	err bool
+}{
+	{s: "50/10m", th: Threshold{Count: 50, Window: 10 * time.Minute}},
+	{s: "1 / 2h", th: Threshold{Count: 1, Window: 2 * time.Hour}},
+	{s: "5/1d", th: Threshold{Count: 5, Window: 24 * time.Hour}},
+	{s: "50", err: true},
+	{s: "x/10m", err: true},
+	{s: "-1/10m", err: true},
+	{s: "5/foo", err: true},
+	{s: "5/100ms", err: true},
+}
+
+func TestParseThreshold(t *testing.T) {
+	for i, test := range thresholdTests {
+		th, err := parseThreshold(test.s)
+		if test.err {
+			if err == nil {
+				t.Errorf("test %d: expected error for %q not found", i, test.s)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("test %d: unexpected error: %v", i, err)
+			continue
+		}
+
+		if th != test.th {
+			t.Errorf("test %d: want %v, got %v", i, test.th, th)
+		}
+	}
+}
+
+func TestCountersSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "erpel-counters-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	filename := filepath.Join(dir, "thresholds.json")
+
+	c, err := LoadCounters(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	th := Threshold{Count: 2, Window: time.Minute}
+	now := time.Unix(1000000, 0)
+
+	c.add("a", th, now)
+	c.add("a", th, now.Add(10*time.Second))
+	c.add("b", th, now)
+
+	if err = c.Save(filename, now.Add(30*time.Second)); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = LoadCounters(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the third message within the window exceeds the threshold
+	count, report := c.add("a", th, now.Add(40*time.Second))
+	if count != 3 || !report {
+		t.Errorf("want count 3 and report, got %v, %v", count, report)
+	}
+
+	// counters without messages in the window are removed
+	if err = c.Save(filename, now.Add(5*time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	c, err = LoadCounters(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(c.Entries) != 0 {
+		t.Errorf("expired counters were not removed: %v", c.Entries)
+	}
+}
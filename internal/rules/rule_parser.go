@@ -2,6 +2,7 @@
 package rules
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -13,9 +14,14 @@ import (
 type State struct {
 	// used to temporarily store values while parsing
 	name, value string
+	pattern     string
+	separator   string
 	inField     bool
 
-	currentField Field
+	currentField   Field
+	currentParts   []Part
+	currentOptions map[string]string
+	groups         []group
 
 	// global options
 	Options map[string]string
@@ -23,8 +29,11 @@ type State struct {
 	// collection of all fields encountered during parsing
 	Fields map[string]Field
 
+	// Includes lists the (quoted) names of the files to include
+	Includes []string
+
 	// all message templates
-	Templates []string
+	Templates []Template
 	// some samples that must match the rules
 	Samples []string
 }
@@ -32,6 +41,66 @@ type State struct {
 // Field is a dynamic part of a message.
 type Field map[string]string
 
+// Template is a message template, split into parts.
+type Template struct {
+	// Line is the template as written in the rules file.
+	Line  string
+	Parts []Part
+
+	// LineNumber is the number of the line in the rules file, starting at one.
+	LineNumber int
+
+	// Raw is set for templates which are regular expressions, marked by
+	// the prefix "re:". The text parts are regexp syntax in this case.
+	Raw bool
+
+	// Options holds the annotations given for this template.
+	Options map[string]string
+}
+
+// PartType is the type of a part of a template.
+type PartType int
+
+// These are the types of the parts of a template.
+const (
+	TextPart PartType = iota
+	PlaceholderPart
+	OptionalPart
+	AlternationPart
+	RepeatPart
+	WildcardPart
+)
+
+// Part is a section of a template.
+type Part struct {
+	Type PartType
+
+	// Text is the verbatim text for text parts and the name of the field
+	// for placeholders.
+	Text string
+
+	// Pattern is the pattern set for a placeholder in the template, it
+	// overrides the pattern configured for the field.
+	Pattern string
+
+	// Parts holds the contents of an optional or repeated section.
+	Parts []Part
+
+	// Separator is the quoted string which separates the elements of a
+	// repeated section.
+	Separator string
+
+	// Alternatives lists the alternatives of an alternation.
+	Alternatives [][]Part
+}
+
+// group is an optional section or alternation which is being parsed.
+type group struct {
+	// parts of the enclosing section
+	parts        []Part
+	alternatives [][]Part
+}
+
 func (c *State) newField(name string) {
 	name = strings.TrimSpace(name)
 	f := make(Field)
@@ -57,12 +126,101 @@ func (c *State) set(key, value string) {
 	}
 }
 
-func (c *State) addTemplate(s string) {
-	s = strings.TrimSpace(s)
+func (c *State) addInclude(filename string) {
+	c.Includes = append(c.Includes, filename)
+}
+
+func (c *State) addText(s string) {
+	l := len(c.currentParts)
+	if l > 0 && c.currentParts[l-1].Type == TextPart {
+		c.currentParts[l-1].Text += s
+		return
+	}
+
+	c.currentParts = append(c.currentParts, Part{Type: TextPart, Text: s})
+}
+
+func (c *State) addPlaceholder() {
+	c.currentParts = append(c.currentParts, Part{
+		Type:    PlaceholderPart,
+		Text:    c.name,
+		Pattern: c.pattern,
+	})
+}
+
+func (c *State) beginGroup() {
+	c.groups = append(c.groups, group{parts: c.currentParts})
+	c.currentParts = nil
+}
+
+// endGroup returns the innermost group and restores the parts of the
+// enclosing section.
+func (c *State) endGroup() group {
+	l := len(c.groups)
+	g := c.groups[l-1]
+	c.groups = c.groups[:l-1]
+
+	c.currentParts, g.parts = g.parts, c.currentParts
+	return g
+}
+
+func (c *State) endOptional() {
+	g := c.endGroup()
+	c.currentParts = append(c.currentParts, Part{Type: OptionalPart, Parts: g.parts})
+}
+
+func (c *State) nextAlternative() {
+	g := &c.groups[len(c.groups)-1]
+	g.alternatives = append(g.alternatives, c.currentParts)
+	c.currentParts = nil
+}
+
+func (c *State) endAlternation() {
+	g := c.endGroup()
+	c.currentParts = append(c.currentParts, Part{
+		Type:         AlternationPart,
+		Alternatives: append(g.alternatives, g.parts),
+	})
+}
+
+func (c *State) endRepeat() {
+	g := c.endGroup()
+	c.currentParts = append(c.currentParts, Part{
+		Type:      RepeatPart,
+		Parts:     g.parts,
+		Separator: c.separator,
+	})
+	c.separator = ""
+}
+
+func (c *State) addWildcard() {
+	c.currentParts = append(c.currentParts, Part{Type: WildcardPart})
+}
+
+func (c *State) annotate(key, value string) {
+	if c.currentOptions == nil {
+		c.currentOptions = make(map[string]string)
+	}
+
+	c.currentOptions[strings.TrimSpace(key)] = strings.TrimSpace(value)
+}
+
+func (c *State) addTemplate(s string, line int, raw bool) {
+	parts := c.currentParts
+	c.currentParts = nil
+
 	if len(s) == 0 {
 		return
 	}
-	c.Templates = append(c.Templates, s)
+
+	c.Templates = append(c.Templates, Template{
+		Line:       s,
+		Parts:      parts,
+		LineNumber: line,
+		Raw:        raw,
+		Options:    c.currentOptions,
+	})
+	c.currentOptions = nil
 }
 
 func (c *State) addSample(s string) {
@@ -73,6 +231,19 @@ func (c *State) addSample(s string) {
 	c.Samples = append(c.Samples, s)
 }
 
+// lineNumber returns the number of the line for the position within the
+// buffer, starting at one.
+func (p *ruleParser) lineNumber(pos int) int {
+	n := 1
+	for _, r := range p.buffer[:pos] {
+		if r == '\n' {
+			n++
+		}
+	}
+
+	return n
+}
+
 // Parse returns the state for a configuration.
 func Parse(data string) (State, error) {
 	fields := make(map[string]Field)
@@ -93,5 +264,36 @@ func Parse(data string) (State, error) {
 	}
 	c.Execute()
 
+	if c.currentOptions != nil {
+		return State{}, errors.New("annotation at the end of the templates section is not followed by a template")
+	}
+
 	return c.State, nil
 }
+
+// ParseTemplate parses a single template line.
+func ParseTemplate(data string) (Template, error) {
+	c := &ruleParser{
+		Buffer: data,
+	}
+
+	c.Init()
+	err := c.Parse(int(ruleTemplate))
+	if err != nil {
+		return Template{}, errors.WithMessage(err, data)
+	}
+	c.Execute()
+
+	var t Template
+	if len(c.Templates) > 0 {
+		t = c.Templates[0]
+	}
+
+	// make sure the whole line has been consumed
+	rest := strings.TrimLeft(data, " \t")[len(t.Line):]
+	if strings.TrimSpace(rest) != "" {
+		return Template{}, errors.WithStack(fmt.Errorf("invalid template %q, parse error near %q", data, rest))
+	}
+
+	return t, nil
+}
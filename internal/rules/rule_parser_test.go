@@ -1,6 +1,21 @@
 package rules
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
+
+// textTemplates returns templates consisting of verbatim text only.
+func textTemplates(lines ...string) (templates []Template) {
+	for _, line := range lines {
+		templates = append(templates, Template{
+			Line:  line,
+			Parts: []Part{{Type: TextPart, Text: line}},
+		})
+	}
+
+	return templates
+}
 
 func equalMap(t testing.TB, name string, want map[string]string, got map[string]string) {
 	var keys []string
@@ -75,6 +90,16 @@ var testRuleConfigs = []struct {
 			},
 		},
 	},
+	{
+		cfg: "include = 'fields/mail.fields'\ninclude_x = 'foo'\n  include=\"b\" # comment",
+		state: State{
+			Fields:   map[string]Field{},
+			Includes: []string{"'fields/mail.fields'", `"b"`},
+			Options: map[string]string{
+				"include_x": "'foo'",
+			},
+		},
+	},
 	{
 		cfg: `# comment, nothing more`,
 		state: State{
@@ -221,13 +246,13 @@ field {
 `,
 		state: State{
 			Fields: map[string]Field{},
-			Templates: []string{
+			Templates: textTemplates(
 				"line 1",
 				"line 2",
 				"field {",
 				"foo = bar",
 				"}",
-			},
+			),
 		},
 	},
 	{
@@ -281,13 +306,13 @@ sample line 2....
 					"z": `"foobar"`,
 				},
 			},
-			Templates: []string{
+			Templates: textTemplates(
 				"line 1",
 				"line 2",
 				"field {",
 				"foo = bar",
 				"}",
-			},
+			),
 			Samples: []string{
 				"sample line 1",
 				"sample line 2....",
@@ -315,13 +340,13 @@ sample line 2....
 `,
 		state: State{
 			Fields: map[string]Field{},
-			Templates: []string{
+			Templates: textTemplates(
 				"line 1",
 				"line 2",
 				"field {",
 				"foo = bar",
 				"}",
-			},
+			),
 			Samples: []string{
 				"sample line 1",
 				"sample line 2....",
@@ -341,6 +366,10 @@ func TestParseRuleConfig(t *testing.T) {
 		equalFields(t, test.state.Fields, state.Fields)
 		equalMap(t, "Options", test.state.Options, state.Options)
 
+		if !reflect.DeepEqual(test.state.Includes, state.Includes) {
+			t.Errorf("test %v: wrong includes, want %q, got %q", i, test.state.Includes, state.Includes)
+		}
+
 		if len(state.Templates) != len(test.state.Templates) {
 			t.Errorf("test %v: unexpected number of template lines returned: want %d, got %d",
 				i, len(test.state.Templates), len(state.Templates))
@@ -349,8 +378,11 @@ func TestParseRuleConfig(t *testing.T) {
 		}
 
 		for j := range test.state.Templates {
-			if test.state.Templates[j] != state.Templates[j] {
-				t.Errorf("test %v: template[%d]: want %q, got %q",
+			// line numbers are tested in TestParseRulesTemplates
+			state.Templates[j].LineNumber = 0
+
+			if !reflect.DeepEqual(test.state.Templates[j], state.Templates[j]) {
+				t.Errorf("test %v: template[%d]: want %+v, got %+v",
 					i, j, test.state.Templates[j], state.Templates[j])
 			}
 		}
@@ -370,3 +402,315 @@ func TestParseRuleConfig(t *testing.T) {
 		}
 	}
 }
+
+var testTemplates = []struct {
+	data     string
+	template Template
+}{
+	{
+		data: "foo bar",
+		template: Template{
+			Line:  "foo bar",
+			Parts: []Part{{Type: TextPart, Text: "foo bar"}},
+		},
+	},
+	{
+		data: "  foo {bar} }} mailbox { auto }  ",
+		template: Template{
+			Line:  "foo {bar} }} mailbox { auto }",
+			Parts: []Part{{Type: TextPart, Text: "foo {bar} }} mailbox { auto }"}},
+		},
+	},
+	{
+		data: "bytes={{num}}/{{ num }}",
+		template: Template{
+			Line: "bytes={{num}}/{{ num }}",
+			Parts: []Part{
+				{Type: TextPart, Text: "bytes="},
+				{Type: PlaceholderPart, Text: "num"},
+				{Type: TextPart, Text: "/"},
+				{Type: PlaceholderPart, Text: "num"},
+			},
+		},
+	},
+	{
+		data: `{{num:\d{1,3}}}: {{user-name:[a-z]+}}`,
+		template: Template{
+			Line: `{{num:\d{1,3}}}: {{user-name:[a-z]+}}`,
+			Parts: []Part{
+				{Type: PlaceholderPart, Text: "num", Pattern: `\d{1,3}`},
+				{Type: TextPart, Text: ": "},
+				{Type: PlaceholderPart, Text: "user-name", Pattern: `[a-z]+`},
+			},
+		},
+	},
+	{
+		data: `{{x:\}+}}{{y:{{2}}}}`,
+		template: Template{
+			Line: `{{x:\}+}}{{y:{{2}}}}`,
+			Parts: []Part{
+				{Type: PlaceholderPart, Text: "x", Pattern: `\}+`},
+				{Type: PlaceholderPart, Text: "y", Pattern: `{{2}}`},
+			},
+		},
+	},
+	{
+		data: `literal \{{num}} and \\ and C:\Windows`,
+		template: Template{
+			Line:  `literal \{{num}} and \\ and C:\Windows`,
+			Parts: []Part{{Type: TextPart, Text: `literal {{num}} and \ and C:\Windows`}},
+		},
+	},
+	{
+		data: `trailing space:\  `,
+		template: Template{
+			Line:  `trailing space:\ `,
+			Parts: []Part{{Type: TextPart, Text: `trailing space: `}},
+		},
+	},
+	{
+		data: "grüße {{name}}",
+		template: Template{
+			Line: "grüße {{name}}",
+			Parts: []Part{
+				{Type: TextPart, Text: "grüße "},
+				{Type: PlaceholderPart, Text: "name"},
+			},
+		},
+	},
+	{
+		data: "Login: rip={{IP}}[[, mpid={{num}}]], (TLS|SSL)",
+		template: Template{
+			Line: "Login: rip={{IP}}[[, mpid={{num}}]], (TLS|SSL)",
+			Parts: []Part{
+				{Type: TextPart, Text: "Login: rip="},
+				{Type: PlaceholderPart, Text: "IP"},
+				{Type: OptionalPart, Parts: []Part{
+					{Type: TextPart, Text: ", mpid="},
+					{Type: PlaceholderPart, Text: "num"},
+				}},
+				{Type: TextPart, Text: ", "},
+				{Type: AlternationPart, Alternatives: [][]Part{
+					{{Type: TextPart, Text: "TLS"}},
+					{{Type: TextPart, Text: "SSL"}},
+				}},
+			},
+		},
+	},
+	{
+		data: "imap(user) [x]] (a|[[b (c|d)]]|)",
+		template: Template{
+			Line: "imap(user) [x]] (a|[[b (c|d)]]|)",
+			Parts: []Part{
+				{Type: TextPart, Text: "imap(user) [x]] "},
+				{Type: AlternationPart, Alternatives: [][]Part{
+					{{Type: TextPart, Text: "a"}},
+					{{Type: OptionalPart, Parts: []Part{
+						{Type: TextPart, Text: "b "},
+						{Type: AlternationPart, Alternatives: [][]Part{
+							{{Type: TextPart, Text: "c"}},
+							{{Type: TextPart, Text: "d"}},
+						}},
+					}}},
+					nil,
+				}},
+			},
+		},
+	},
+	{
+		data: `literal \[[x]] and \(a|b)`,
+		template: Template{
+			Line:  `literal \[[x]] and \(a|b)`,
+			Parts: []Part{{Type: TextPart, Text: "literal [[x]] and (a|b)"}},
+		},
+	},
+	{
+		data: `to={{repeat: <{{mailaddress}}>, sep=", "}} {{repeat:[[x]]{{num}}}}.`,
+		template: Template{
+			Line: `to={{repeat: <{{mailaddress}}>, sep=", "}} {{repeat:[[x]]{{num}}}}.`,
+			Parts: []Part{
+				{Type: TextPart, Text: "to="},
+				{Type: RepeatPart, Separator: `", "`, Parts: []Part{
+					{Type: TextPart, Text: "<"},
+					{Type: PlaceholderPart, Text: "mailaddress"},
+					{Type: TextPart, Text: ">"},
+				}},
+				{Type: TextPart, Text: " "},
+				{Type: RepeatPart, Parts: []Part{
+					{Type: OptionalPart, Parts: []Part{
+						{Type: TextPart, Text: "x"},
+					}},
+					{Type: PlaceholderPart, Text: "num"},
+				}},
+				{Type: TextPart, Text: "."},
+			},
+		},
+	},
+	{
+		data: `{{repeat: {{repeat:a, sep='-'}}, sep=' '}}`,
+		template: Template{
+			Line: `{{repeat: {{repeat:a, sep='-'}}, sep=' '}}`,
+			Parts: []Part{
+				{Type: RepeatPart, Separator: `' '`, Parts: []Part{
+					{Type: RepeatPart, Separator: `'-'`, Parts: []Part{
+						{Type: TextPart, Text: "a"},
+					}},
+				}},
+			},
+		},
+	},
+	{
+		data: "Kernel panic... at ...",
+		template: Template{
+			Line: "Kernel panic... at ...",
+			Parts: []Part{
+				{Type: TextPart, Text: "Kernel panic"},
+				{Type: WildcardPart},
+				{Type: TextPart, Text: " at "},
+				{Type: WildcardPart},
+			},
+		},
+	},
+	{
+		data: "Starting foo...",
+		template: Template{
+			Line:  "Starting foo...",
+			Parts: []Part{{Type: TextPart, Text: "Starting foo"}, {Type: WildcardPart}},
+		},
+	},
+	{
+		data: `Starting foo\...`,
+		template: Template{
+			Line:  `Starting foo\...`,
+			Parts: []Part{{Type: TextPart, Text: "Starting foo..."}},
+		},
+	},
+	{
+		data:     "",
+		template: Template{},
+	},
+}
+
+func TestParseTemplate(t *testing.T) {
+	for i, test := range testTemplates {
+		tmpl, err := ParseTemplate(test.data)
+		if err != nil {
+			t.Errorf("test %d: failed to parse: %v", i, err)
+			continue
+		}
+
+		tmpl.LineNumber = 0
+
+		if !reflect.DeepEqual(tmpl, test.template) {
+			t.Errorf("test %d: wrong template returned:\n  want %+v\n   got %+v", i, test.template, tmpl)
+		}
+	}
+}
+
+var testInvalidTemplates = []string{
+	"foo {{bar",
+	"foo {{}}",
+	"foo {{bar:}}",
+	"foo {{bar baz}}",
+	"foo {{bar:{x}}",
+	"foo [[bar",
+	"foo {{repeat: bar",
+	"foo {{repeat: bar, sep=x}}",
+}
+
+func TestParseInvalidTemplate(t *testing.T) {
+	for i, data := range testInvalidTemplates {
+		_, err := ParseTemplate(data)
+		if err == nil {
+			t.Errorf("test %d: expected error for invalid template %q not found", i, data)
+		}
+	}
+}
+
+func TestParseRulesTemplates(t *testing.T) {
+	state, err := Parse("---\nbytes={{num}}\\ \nfoo\n\n  re: (a|b)+ {{num:\\d{2}}}\\s*{{user}} \n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Template{
+		{
+			Line: `bytes={{num}}\ `,
+			Parts: []Part{
+				{Type: TextPart, Text: "bytes="},
+				{Type: PlaceholderPart, Text: "num"},
+				{Type: TextPart, Text: " "},
+			},
+			LineNumber: 2,
+		},
+		{
+			Line:       "foo",
+			Parts:      []Part{{Type: TextPart, Text: "foo"}},
+			LineNumber: 3,
+		},
+		{
+			Line: `re: (a|b)+ {{num:\d{2}}}\s*{{user}}`,
+			Parts: []Part{
+				{Type: TextPart, Text: "(a|b)+ "},
+				{Type: PlaceholderPart, Text: "num", Pattern: `\d{2}`},
+				{Type: TextPart, Text: `\s*`},
+				{Type: PlaceholderPart, Text: "user"},
+			},
+			LineNumber: 5,
+			Raw:        true,
+		},
+	}
+
+	if !reflect.DeepEqual(state.Templates, want) {
+		t.Errorf("wrong templates returned:\n  want %+v\n   got %+v", want, state.Templates)
+	}
+
+	_, err = Parse("---\nfoo {{num\n")
+	if err == nil {
+		t.Errorf("expected error for invalid template not found")
+	}
+}
+
+func TestParseRulesAnnotations(t *testing.T) {
+	state, err := Parse(`
+---
+@match = 'prefix'
+@foo = "bar" # comment
+first
+second
+
+@match = 'contains'
+# comment
+third
+\@literal
+`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []map[string]string{
+		{"match": "'prefix'", "foo": `"bar"`},
+		nil,
+		{"match": "'contains'"},
+		nil,
+	}
+
+	if len(state.Templates) != len(want) {
+		t.Fatalf("wrong number of templates returned, want %d, got %d", len(want), len(state.Templates))
+	}
+
+	for i, opts := range want {
+		if !reflect.DeepEqual(opts, state.Templates[i].Options) {
+			t.Errorf("template %d: wrong options, want %v, got %v", i, opts, state.Templates[i].Options)
+		}
+	}
+
+	if state.Templates[3].Line != `\@literal` {
+		t.Errorf("wrong template %q", state.Templates[3].Line)
+	}
+
+	_, err = Parse("---\nfoo\n@match = 'full'\n")
+	if err == nil {
+		t.Errorf("expected error for annotation at the end not found")
+	}
+}
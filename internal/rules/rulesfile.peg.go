@@ -18,6 +18,7 @@ const (
 	ruleLine
 	ruleName
 	ruleStatement
+	ruleInclude
 	ruleField
 	ruleFieldName
 	ruleFieldData
@@ -30,7 +31,35 @@ const (
 	ruleRawString
 	ruleSeparator
 	ruleTemplates
+	ruleAnnotation
 	ruleTemplate
+	ruleTextTemplate
+	ruleTemplatePart
+	ruleTemplateText
+	ruleTextEnd
+	ruleTrailingSpace
+	ruleEscape
+	rulePunct
+	ruleOptional
+	ruleOptionalPart
+	ruleOptionalText
+	ruleAlternation
+	ruleAlternativePart
+	ruleAlternativeText
+	ruleRepeat
+	ruleRepeatPart
+	ruleRepeatText
+	ruleRepeatSeparator
+	ruleSeparatorKey
+	ruleRawTemplate
+	ruleRawPart
+	ruleRawText
+	ruleWildcard
+	rulePlaceholder
+	rulePlaceholderName
+	rulePlaceholderPattern
+	rulePatternChar
+	rulePatternGroup
 	ruleSamples
 	ruleSample
 	ruleComment
@@ -48,6 +77,27 @@ const (
 	ruleAction7
 	ruleAction8
 	ruleAction9
+	ruleAction10
+	ruleAction11
+	ruleAction12
+	ruleAction13
+	ruleAction14
+	ruleAction15
+	ruleAction16
+	ruleAction17
+	ruleAction18
+	ruleAction19
+	ruleAction20
+	ruleAction21
+	ruleAction22
+	ruleAction23
+	ruleAction24
+	ruleAction25
+	ruleAction26
+	ruleAction27
+	ruleAction28
+	ruleAction29
+	ruleAction30
 
 	rulePre
 	ruleIn
@@ -60,6 +110,7 @@ var rul3s = [...]string{
 	"Line",
 	"Name",
 	"Statement",
+	"Include",
 	"Field",
 	"FieldName",
 	"FieldData",
@@ -72,7 +123,35 @@ var rul3s = [...]string{
 	"RawString",
 	"Separator",
 	"Templates",
+	"Annotation",
 	"Template",
+	"TextTemplate",
+	"TemplatePart",
+	"TemplateText",
+	"TextEnd",
+	"TrailingSpace",
+	"Escape",
+	"Punct",
+	"Optional",
+	"OptionalPart",
+	"OptionalText",
+	"Alternation",
+	"AlternativePart",
+	"AlternativeText",
+	"Repeat",
+	"RepeatPart",
+	"RepeatText",
+	"RepeatSeparator",
+	"SeparatorKey",
+	"RawTemplate",
+	"RawPart",
+	"RawText",
+	"Wildcard",
+	"Placeholder",
+	"PlaceholderName",
+	"PlaceholderPattern",
+	"PatternChar",
+	"PatternGroup",
 	"Samples",
 	"Sample",
 	"Comment",
@@ -90,6 +169,27 @@ var rul3s = [...]string{
 	"Action7",
 	"Action8",
 	"Action9",
+	"Action10",
+	"Action11",
+	"Action12",
+	"Action13",
+	"Action14",
+	"Action15",
+	"Action16",
+	"Action17",
+	"Action18",
+	"Action19",
+	"Action20",
+	"Action21",
+	"Action22",
+	"Action23",
+	"Action24",
+	"Action25",
+	"Action26",
+	"Action27",
+	"Action28",
+	"Action29",
+	"Action30",
 
 	"Pre_",
 	"_In_",
@@ -410,7 +510,7 @@ type ruleParser struct {
 
 	Buffer string
 	buffer []rune
-	rules  [35]func() bool
+	rules  [85]func() bool
 	Parse  func(rule ...int) error
 	Reset  func()
 	Pretty bool
@@ -495,26 +595,69 @@ func (p *ruleParser) Execute() {
 			text = string(_buffer[begin:end])
 
 		case ruleAction0:
-			p.name = buffer[begin:end]
+			p.name = text
 		case ruleAction1:
 			p.set(p.name, p.value)
 		case ruleAction2:
-			p.inField = false
+			p.addInclude(p.value)
 		case ruleAction3:
-			p.inField = true
-			p.newField(buffer[begin:end])
+			p.inField = false
 		case ruleAction4:
-			p.value = buffer[begin:end]
+			p.inField = true
+			p.newField(text)
 		case ruleAction5:
-			p.value = buffer[begin:end]
+			p.value = text
 		case ruleAction6:
-			p.value = buffer[begin:end]
+			p.value = text
 		case ruleAction7:
-			p.value = buffer[begin:end]
+			p.value = text
 		case ruleAction8:
-			p.addTemplate(buffer[begin:end])
+			p.value = text
 		case ruleAction9:
-			p.addSample(buffer[begin:end])
+			p.annotate(p.name, p.value)
+		case ruleAction10:
+			p.addTemplate(text, p.lineNumber(begin), false)
+		case ruleAction11:
+			p.addText(text)
+		case ruleAction12:
+			p.addText(text)
+		case ruleAction13:
+			p.beginGroup()
+		case ruleAction14:
+			p.endOptional()
+		case ruleAction15:
+			p.addText(text)
+		case ruleAction16:
+			p.beginGroup()
+		case ruleAction17:
+			p.nextAlternative()
+		case ruleAction18:
+			p.endAlternation()
+		case ruleAction19:
+			p.addText(text)
+		case ruleAction20:
+			p.beginGroup()
+		case ruleAction21:
+			p.endRepeat()
+		case ruleAction22:
+			p.addText(text)
+		case ruleAction23:
+			p.separator = p.value
+		case ruleAction24:
+			p.addTemplate(text, p.lineNumber(begin), true)
+		case ruleAction25:
+			p.addText(text)
+		case ruleAction26:
+			p.addWildcard()
+		case ruleAction27:
+			p.addPlaceholder()
+		case ruleAction28:
+			p.name = text
+			p.pattern = ""
+		case ruleAction29:
+			p.pattern = text
+		case ruleAction30:
+			p.addSample(text)
 
 		}
 	}
@@ -652,7 +795,7 @@ func (p *ruleParser) Init() {
 			position, tokenIndex, depth = position0, tokenIndex0, depth0
 			return false
 		},
-		/* 1 Line <- <((Field / Statement)? s Comment?)> */
+		/* 1 Line <- <((Field / Include / Statement)? s Comment?)> */
 		func() bool {
 			position10, tokenIndex10, depth10 := position, tokenIndex, depth
 			{
@@ -667,6 +810,12 @@ func (p *ruleParser) Init() {
 						}
 						goto l14
 					l15:
+						position, tokenIndex, depth = position14, tokenIndex14, depth14
+						if !_rules[ruleInclude]() {
+							goto l16
+						}
+						goto l14
+					l16:
 						position, tokenIndex, depth = position14, tokenIndex14, depth14
 						if !_rules[ruleStatement]() {
 							goto l12
@@ -682,15 +831,15 @@ func (p *ruleParser) Init() {
 					goto l10
 				}
 				{
-					position16, tokenIndex16, depth16 := position, tokenIndex, depth
+					position17, tokenIndex17, depth17 := position, tokenIndex, depth
 					if !_rules[ruleComment]() {
-						goto l16
+						goto l17
 					}
-					goto l17
-				l16:
-					position, tokenIndex, depth = position16, tokenIndex16, depth16
+					goto l18
+				l17:
+					position, tokenIndex, depth = position17, tokenIndex17, depth17
 				}
-			l17:
+			l18:
 				depth--
 				add(ruleLine, position11)
 			}
@@ -701,1162 +850,3327 @@ func (p *ruleParser) Init() {
 		},
 		/* 2 Name <- <(<([a-z] / [A-Z] / [0-9] / '-' / '_')+> Action0)> */
 		func() bool {
-			position18, tokenIndex18, depth18 := position, tokenIndex, depth
+			position19, tokenIndex19, depth19 := position, tokenIndex, depth
 			{
-				position19 := position
+				position20 := position
 				depth++
 				{
-					position20 := position
+					position21 := position
 					depth++
 					{
-						position23, tokenIndex23, depth23 := position, tokenIndex, depth
+						position24, tokenIndex24, depth24 := position, tokenIndex, depth
 						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l24
-						}
-						position++
-						goto l23
-					l24:
-						position, tokenIndex, depth = position23, tokenIndex23, depth23
-						if c := buffer[position]; c < rune('A') || c > rune('Z') {
 							goto l25
 						}
 						position++
-						goto l23
+						goto l24
 					l25:
-						position, tokenIndex, depth = position23, tokenIndex23, depth23
-						if c := buffer[position]; c < rune('0') || c > rune('9') {
+						position, tokenIndex, depth = position24, tokenIndex24, depth24
+						if c := buffer[position]; c < rune('A') || c > rune('Z') {
 							goto l26
 						}
 						position++
-						goto l23
+						goto l24
 					l26:
-						position, tokenIndex, depth = position23, tokenIndex23, depth23
-						if buffer[position] != rune('-') {
+						position, tokenIndex, depth = position24, tokenIndex24, depth24
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
 							goto l27
 						}
 						position++
-						goto l23
+						goto l24
 					l27:
-						position, tokenIndex, depth = position23, tokenIndex23, depth23
+						position, tokenIndex, depth = position24, tokenIndex24, depth24
+						if buffer[position] != rune('-') {
+							goto l28
+						}
+						position++
+						goto l24
+					l28:
+						position, tokenIndex, depth = position24, tokenIndex24, depth24
 						if buffer[position] != rune('_') {
-							goto l18
+							goto l19
 						}
 						position++
 					}
-				l23:
-				l21:
+				l24:
+				l22:
 					{
-						position22, tokenIndex22, depth22 := position, tokenIndex, depth
+						position23, tokenIndex23, depth23 := position, tokenIndex, depth
 						{
-							position28, tokenIndex28, depth28 := position, tokenIndex, depth
+							position29, tokenIndex29, depth29 := position, tokenIndex, depth
 							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l29
-							}
-							position++
-							goto l28
-						l29:
-							position, tokenIndex, depth = position28, tokenIndex28, depth28
-							if c := buffer[position]; c < rune('A') || c > rune('Z') {
 								goto l30
 							}
 							position++
-							goto l28
+							goto l29
 						l30:
-							position, tokenIndex, depth = position28, tokenIndex28, depth28
-							if c := buffer[position]; c < rune('0') || c > rune('9') {
+							position, tokenIndex, depth = position29, tokenIndex29, depth29
+							if c := buffer[position]; c < rune('A') || c > rune('Z') {
 								goto l31
 							}
 							position++
-							goto l28
+							goto l29
 						l31:
-							position, tokenIndex, depth = position28, tokenIndex28, depth28
-							if buffer[position] != rune('-') {
+							position, tokenIndex, depth = position29, tokenIndex29, depth29
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
 								goto l32
 							}
 							position++
-							goto l28
+							goto l29
 						l32:
-							position, tokenIndex, depth = position28, tokenIndex28, depth28
+							position, tokenIndex, depth = position29, tokenIndex29, depth29
+							if buffer[position] != rune('-') {
+								goto l33
+							}
+							position++
+							goto l29
+						l33:
+							position, tokenIndex, depth = position29, tokenIndex29, depth29
 							if buffer[position] != rune('_') {
-								goto l22
+								goto l23
 							}
 							position++
 						}
-					l28:
-						goto l21
-					l22:
-						position, tokenIndex, depth = position22, tokenIndex22, depth22
+					l29:
+						goto l22
+					l23:
+						position, tokenIndex, depth = position23, tokenIndex23, depth23
 					}
 					depth--
-					add(rulePegText, position20)
+					add(rulePegText, position21)
 				}
 				if !_rules[ruleAction0]() {
-					goto l18
+					goto l19
 				}
 				depth--
-				add(ruleName, position19)
+				add(ruleName, position20)
 			}
 			return true
-		l18:
-			position, tokenIndex, depth = position18, tokenIndex18, depth18
+		l19:
+			position, tokenIndex, depth = position19, tokenIndex19, depth19
 			return false
 		},
 		/* 3 Statement <- <(s Name s '=' s Value Action1)> */
 		func() bool {
-			position33, tokenIndex33, depth33 := position, tokenIndex, depth
+			position34, tokenIndex34, depth34 := position, tokenIndex, depth
 			{
-				position34 := position
+				position35 := position
 				depth++
 				if !_rules[rules]() {
-					goto l33
+					goto l34
 				}
 				if !_rules[ruleName]() {
-					goto l33
+					goto l34
 				}
 				if !_rules[rules]() {
-					goto l33
+					goto l34
 				}
 				if buffer[position] != rune('=') {
-					goto l33
+					goto l34
 				}
 				position++
 				if !_rules[rules]() {
-					goto l33
+					goto l34
 				}
 				if !_rules[ruleValue]() {
-					goto l33
+					goto l34
 				}
 				if !_rules[ruleAction1]() {
-					goto l33
+					goto l34
+				}
+				depth--
+				add(ruleStatement, position35)
+			}
+			return true
+		l34:
+			position, tokenIndex, depth = position34, tokenIndex34, depth34
+			return false
+		},
+		/* 4 Include <- <(s (('i' / 'I') ('n' / 'N') ('c' / 'C') ('l' / 'L') ('u' / 'U') ('d' / 'D') ('e' / 'E')) s '=' s String Action2)> */
+		func() bool {
+			position36, tokenIndex36, depth36 := position, tokenIndex, depth
+			{
+				position37 := position
+				depth++
+				if !_rules[rules]() {
+					goto l36
+				}
+				{
+					position38, tokenIndex38, depth38 := position, tokenIndex, depth
+					if buffer[position] != rune('i') {
+						goto l39
+					}
+					position++
+					goto l38
+				l39:
+					position, tokenIndex, depth = position38, tokenIndex38, depth38
+					if buffer[position] != rune('I') {
+						goto l36
+					}
+					position++
+				}
+			l38:
+				{
+					position40, tokenIndex40, depth40 := position, tokenIndex, depth
+					if buffer[position] != rune('n') {
+						goto l41
+					}
+					position++
+					goto l40
+				l41:
+					position, tokenIndex, depth = position40, tokenIndex40, depth40
+					if buffer[position] != rune('N') {
+						goto l36
+					}
+					position++
+				}
+			l40:
+				{
+					position42, tokenIndex42, depth42 := position, tokenIndex, depth
+					if buffer[position] != rune('c') {
+						goto l43
+					}
+					position++
+					goto l42
+				l43:
+					position, tokenIndex, depth = position42, tokenIndex42, depth42
+					if buffer[position] != rune('C') {
+						goto l36
+					}
+					position++
+				}
+			l42:
+				{
+					position44, tokenIndex44, depth44 := position, tokenIndex, depth
+					if buffer[position] != rune('l') {
+						goto l45
+					}
+					position++
+					goto l44
+				l45:
+					position, tokenIndex, depth = position44, tokenIndex44, depth44
+					if buffer[position] != rune('L') {
+						goto l36
+					}
+					position++
+				}
+			l44:
+				{
+					position46, tokenIndex46, depth46 := position, tokenIndex, depth
+					if buffer[position] != rune('u') {
+						goto l47
+					}
+					position++
+					goto l46
+				l47:
+					position, tokenIndex, depth = position46, tokenIndex46, depth46
+					if buffer[position] != rune('U') {
+						goto l36
+					}
+					position++
+				}
+			l46:
+				{
+					position48, tokenIndex48, depth48 := position, tokenIndex, depth
+					if buffer[position] != rune('d') {
+						goto l49
+					}
+					position++
+					goto l48
+				l49:
+					position, tokenIndex, depth = position48, tokenIndex48, depth48
+					if buffer[position] != rune('D') {
+						goto l36
+					}
+					position++
+				}
+			l48:
+				{
+					position50, tokenIndex50, depth50 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l51
+					}
+					position++
+					goto l50
+				l51:
+					position, tokenIndex, depth = position50, tokenIndex50, depth50
+					if buffer[position] != rune('E') {
+						goto l36
+					}
+					position++
+				}
+			l50:
+				if !_rules[rules]() {
+					goto l36
+				}
+				if buffer[position] != rune('=') {
+					goto l36
+				}
+				position++
+				if !_rules[rules]() {
+					goto l36
+				}
+				if !_rules[ruleString]() {
+					goto l36
+				}
+				if !_rules[ruleAction2]() {
+					goto l36
 				}
 				depth--
-				add(ruleStatement, position34)
+				add(ruleInclude, position37)
 			}
 			return true
-		l33:
-			position, tokenIndex, depth = position33, tokenIndex33, depth33
+		l36:
+			position, tokenIndex, depth = position36, tokenIndex36, depth36
 			return false
 		},
-		/* 4 Field <- <(s (('f' / 'F') ('i' / 'I') ('e' / 'E') ('l' / 'L') ('d' / 'D')) s FieldName s '{' FieldData '}' Action2)> */
+		/* 5 Field <- <(s (('f' / 'F') ('i' / 'I') ('e' / 'E') ('l' / 'L') ('d' / 'D')) s FieldName s '{' FieldData '}' Action3)> */
 		func() bool {
-			position35, tokenIndex35, depth35 := position, tokenIndex, depth
+			position52, tokenIndex52, depth52 := position, tokenIndex, depth
 			{
-				position36 := position
+				position53 := position
 				depth++
 				if !_rules[rules]() {
-					goto l35
+					goto l52
 				}
 				{
-					position37, tokenIndex37, depth37 := position, tokenIndex, depth
+					position54, tokenIndex54, depth54 := position, tokenIndex, depth
 					if buffer[position] != rune('f') {
-						goto l38
+						goto l55
 					}
 					position++
-					goto l37
-				l38:
-					position, tokenIndex, depth = position37, tokenIndex37, depth37
+					goto l54
+				l55:
+					position, tokenIndex, depth = position54, tokenIndex54, depth54
 					if buffer[position] != rune('F') {
-						goto l35
+						goto l52
 					}
 					position++
 				}
-			l37:
+			l54:
 				{
-					position39, tokenIndex39, depth39 := position, tokenIndex, depth
+					position56, tokenIndex56, depth56 := position, tokenIndex, depth
 					if buffer[position] != rune('i') {
-						goto l40
+						goto l57
 					}
 					position++
-					goto l39
-				l40:
-					position, tokenIndex, depth = position39, tokenIndex39, depth39
+					goto l56
+				l57:
+					position, tokenIndex, depth = position56, tokenIndex56, depth56
 					if buffer[position] != rune('I') {
-						goto l35
+						goto l52
 					}
 					position++
 				}
-			l39:
+			l56:
 				{
-					position41, tokenIndex41, depth41 := position, tokenIndex, depth
+					position58, tokenIndex58, depth58 := position, tokenIndex, depth
 					if buffer[position] != rune('e') {
-						goto l42
+						goto l59
 					}
 					position++
-					goto l41
-				l42:
-					position, tokenIndex, depth = position41, tokenIndex41, depth41
+					goto l58
+				l59:
+					position, tokenIndex, depth = position58, tokenIndex58, depth58
 					if buffer[position] != rune('E') {
-						goto l35
+						goto l52
 					}
 					position++
 				}
-			l41:
+			l58:
 				{
-					position43, tokenIndex43, depth43 := position, tokenIndex, depth
+					position60, tokenIndex60, depth60 := position, tokenIndex, depth
 					if buffer[position] != rune('l') {
-						goto l44
+						goto l61
 					}
 					position++
-					goto l43
-				l44:
-					position, tokenIndex, depth = position43, tokenIndex43, depth43
+					goto l60
+				l61:
+					position, tokenIndex, depth = position60, tokenIndex60, depth60
 					if buffer[position] != rune('L') {
-						goto l35
+						goto l52
 					}
 					position++
 				}
-			l43:
+			l60:
 				{
-					position45, tokenIndex45, depth45 := position, tokenIndex, depth
+					position62, tokenIndex62, depth62 := position, tokenIndex, depth
 					if buffer[position] != rune('d') {
-						goto l46
+						goto l63
 					}
 					position++
-					goto l45
-				l46:
-					position, tokenIndex, depth = position45, tokenIndex45, depth45
+					goto l62
+				l63:
+					position, tokenIndex, depth = position62, tokenIndex62, depth62
 					if buffer[position] != rune('D') {
-						goto l35
+						goto l52
 					}
 					position++
 				}
-			l45:
+			l62:
 				if !_rules[rules]() {
-					goto l35
+					goto l52
 				}
 				if !_rules[ruleFieldName]() {
-					goto l35
+					goto l52
 				}
 				if !_rules[rules]() {
-					goto l35
+					goto l52
 				}
 				if buffer[position] != rune('{') {
-					goto l35
+					goto l52
 				}
 				position++
 				if !_rules[ruleFieldData]() {
-					goto l35
+					goto l52
 				}
 				if buffer[position] != rune('}') {
-					goto l35
+					goto l52
 				}
 				position++
-				if !_rules[ruleAction2]() {
-					goto l35
+				if !_rules[ruleAction3]() {
+					goto l52
 				}
 				depth--
-				add(ruleField, position36)
+				add(ruleField, position53)
 			}
 			return true
-		l35:
-			position, tokenIndex, depth = position35, tokenIndex35, depth35
+		l52:
+			position, tokenIndex, depth = position52, tokenIndex52, depth52
 			return false
 		},
-		/* 5 FieldName <- <(<([a-z] / [A-Z] / [0-9] / '-' / '_')+> Action3)> */
+		/* 6 FieldName <- <(<([a-z] / [A-Z] / [0-9] / '-' / '_')+> Action4)> */
 		func() bool {
-			position47, tokenIndex47, depth47 := position, tokenIndex, depth
+			position64, tokenIndex64, depth64 := position, tokenIndex, depth
 			{
-				position48 := position
+				position65 := position
 				depth++
 				{
-					position49 := position
+					position66 := position
 					depth++
 					{
-						position52, tokenIndex52, depth52 := position, tokenIndex, depth
+						position69, tokenIndex69, depth69 := position, tokenIndex, depth
 						if c := buffer[position]; c < rune('a') || c > rune('z') {
-							goto l53
+							goto l70
 						}
 						position++
-						goto l52
-					l53:
-						position, tokenIndex, depth = position52, tokenIndex52, depth52
+						goto l69
+					l70:
+						position, tokenIndex, depth = position69, tokenIndex69, depth69
 						if c := buffer[position]; c < rune('A') || c > rune('Z') {
-							goto l54
+							goto l71
 						}
 						position++
-						goto l52
-					l54:
-						position, tokenIndex, depth = position52, tokenIndex52, depth52
+						goto l69
+					l71:
+						position, tokenIndex, depth = position69, tokenIndex69, depth69
 						if c := buffer[position]; c < rune('0') || c > rune('9') {
-							goto l55
+							goto l72
 						}
 						position++
-						goto l52
-					l55:
-						position, tokenIndex, depth = position52, tokenIndex52, depth52
+						goto l69
+					l72:
+						position, tokenIndex, depth = position69, tokenIndex69, depth69
 						if buffer[position] != rune('-') {
-							goto l56
+							goto l73
 						}
 						position++
-						goto l52
-					l56:
-						position, tokenIndex, depth = position52, tokenIndex52, depth52
+						goto l69
+					l73:
+						position, tokenIndex, depth = position69, tokenIndex69, depth69
 						if buffer[position] != rune('_') {
-							goto l47
+							goto l64
 						}
 						position++
 					}
-				l52:
-				l50:
+				l69:
+				l67:
 					{
-						position51, tokenIndex51, depth51 := position, tokenIndex, depth
+						position68, tokenIndex68, depth68 := position, tokenIndex, depth
 						{
-							position57, tokenIndex57, depth57 := position, tokenIndex, depth
+							position74, tokenIndex74, depth74 := position, tokenIndex, depth
 							if c := buffer[position]; c < rune('a') || c > rune('z') {
-								goto l58
+								goto l75
 							}
 							position++
-							goto l57
-						l58:
-							position, tokenIndex, depth = position57, tokenIndex57, depth57
+							goto l74
+						l75:
+							position, tokenIndex, depth = position74, tokenIndex74, depth74
 							if c := buffer[position]; c < rune('A') || c > rune('Z') {
-								goto l59
+								goto l76
 							}
 							position++
-							goto l57
-						l59:
-							position, tokenIndex, depth = position57, tokenIndex57, depth57
+							goto l74
+						l76:
+							position, tokenIndex, depth = position74, tokenIndex74, depth74
 							if c := buffer[position]; c < rune('0') || c > rune('9') {
-								goto l60
+								goto l77
 							}
 							position++
-							goto l57
-						l60:
-							position, tokenIndex, depth = position57, tokenIndex57, depth57
+							goto l74
+						l77:
+							position, tokenIndex, depth = position74, tokenIndex74, depth74
 							if buffer[position] != rune('-') {
-								goto l61
+								goto l78
 							}
 							position++
-							goto l57
-						l61:
-							position, tokenIndex, depth = position57, tokenIndex57, depth57
+							goto l74
+						l78:
+							position, tokenIndex, depth = position74, tokenIndex74, depth74
 							if buffer[position] != rune('_') {
-								goto l51
+								goto l68
 							}
 							position++
 						}
-					l57:
-						goto l50
-					l51:
-						position, tokenIndex, depth = position51, tokenIndex51, depth51
+					l74:
+						goto l67
+					l68:
+						position, tokenIndex, depth = position68, tokenIndex68, depth68
 					}
 					depth--
-					add(rulePegText, position49)
+					add(rulePegText, position66)
 				}
-				if !_rules[ruleAction3]() {
-					goto l47
+				if !_rules[ruleAction4]() {
+					goto l64
 				}
 				depth--
-				add(ruleFieldName, position48)
+				add(ruleFieldName, position65)
 			}
 			return true
-		l47:
-			position, tokenIndex, depth = position47, tokenIndex47, depth47
+		l64:
+			position, tokenIndex, depth = position64, tokenIndex64, depth64
 			return false
 		},
-		/* 6 FieldData <- <((FieldStatement EOL)* FieldStatement?)> */
+		/* 7 FieldData <- <((FieldStatement EOL)* FieldStatement?)> */
 		func() bool {
 			{
-				position63 := position
+				position80 := position
 				depth++
-			l64:
+			l81:
 				{
-					position65, tokenIndex65, depth65 := position, tokenIndex, depth
+					position82, tokenIndex82, depth82 := position, tokenIndex, depth
 					if !_rules[ruleFieldStatement]() {
-						goto l65
+						goto l82
 					}
 					if !_rules[ruleEOL]() {
-						goto l65
+						goto l82
 					}
-					goto l64
-				l65:
-					position, tokenIndex, depth = position65, tokenIndex65, depth65
+					goto l81
+				l82:
+					position, tokenIndex, depth = position82, tokenIndex82, depth82
 				}
 				{
-					position66, tokenIndex66, depth66 := position, tokenIndex, depth
+					position83, tokenIndex83, depth83 := position, tokenIndex, depth
 					if !_rules[ruleFieldStatement]() {
-						goto l66
+						goto l83
 					}
-					goto l67
-				l66:
-					position, tokenIndex, depth = position66, tokenIndex66, depth66
+					goto l84
+				l83:
+					position, tokenIndex, depth = position83, tokenIndex83, depth83
 				}
-			l67:
+			l84:
 				depth--
-				add(ruleFieldData, position63)
+				add(ruleFieldData, position80)
 			}
 			return true
 		},
-		/* 7 FieldStatement <- <(Statement? s Comment?)> */
+		/* 8 FieldStatement <- <(Statement? s Comment?)> */
 		func() bool {
-			position68, tokenIndex68, depth68 := position, tokenIndex, depth
+			position85, tokenIndex85, depth85 := position, tokenIndex, depth
 			{
-				position69 := position
+				position86 := position
 				depth++
 				{
-					position70, tokenIndex70, depth70 := position, tokenIndex, depth
+					position87, tokenIndex87, depth87 := position, tokenIndex, depth
 					if !_rules[ruleStatement]() {
-						goto l70
+						goto l87
 					}
-					goto l71
-				l70:
-					position, tokenIndex, depth = position70, tokenIndex70, depth70
+					goto l88
+				l87:
+					position, tokenIndex, depth = position87, tokenIndex87, depth87
 				}
-			l71:
+			l88:
 				if !_rules[rules]() {
-					goto l68
+					goto l85
 				}
 				{
-					position72, tokenIndex72, depth72 := position, tokenIndex, depth
+					position89, tokenIndex89, depth89 := position, tokenIndex, depth
 					if !_rules[ruleComment]() {
-						goto l72
+						goto l89
 					}
-					goto l73
-				l72:
-					position, tokenIndex, depth = position72, tokenIndex72, depth72
+					goto l90
+				l89:
+					position, tokenIndex, depth = position89, tokenIndex89, depth89
 				}
-			l73:
+			l90:
 				depth--
-				add(ruleFieldStatement, position69)
+				add(ruleFieldStatement, position86)
 			}
 			return true
-		l68:
-			position, tokenIndex, depth = position68, tokenIndex68, depth68
+		l85:
+			position, tokenIndex, depth = position85, tokenIndex85, depth85
 			return false
 		},
-		/* 8 Value <- <(List / String)> */
+		/* 9 Value <- <(List / String)> */
 		func() bool {
-			position74, tokenIndex74, depth74 := position, tokenIndex, depth
+			position91, tokenIndex91, depth91 := position, tokenIndex, depth
 			{
-				position75 := position
+				position92 := position
 				depth++
 				{
-					position76, tokenIndex76, depth76 := position, tokenIndex, depth
+					position93, tokenIndex93, depth93 := position, tokenIndex, depth
 					if !_rules[ruleList]() {
-						goto l77
+						goto l94
 					}
-					goto l76
-				l77:
-					position, tokenIndex, depth = position76, tokenIndex76, depth76
+					goto l93
+				l94:
+					position, tokenIndex, depth = position93, tokenIndex93, depth93
 					if !_rules[ruleString]() {
-						goto l74
+						goto l91
 					}
 				}
-			l76:
+			l93:
 				depth--
-				add(ruleValue, position75)
+				add(ruleValue, position92)
 			}
 			return true
-		l74:
-			position, tokenIndex, depth = position74, tokenIndex74, depth74
+		l91:
+			position, tokenIndex, depth = position91, tokenIndex91, depth91
 			return false
 		},
-		/* 9 String <- <(DoubleQuotedString / SingleQuotedString / RawString)> */
+		/* 10 String <- <(DoubleQuotedString / SingleQuotedString / RawString)> */
 		func() bool {
-			position78, tokenIndex78, depth78 := position, tokenIndex, depth
+			position95, tokenIndex95, depth95 := position, tokenIndex, depth
 			{
-				position79 := position
+				position96 := position
 				depth++
 				{
-					position80, tokenIndex80, depth80 := position, tokenIndex, depth
+					position97, tokenIndex97, depth97 := position, tokenIndex, depth
 					if !_rules[ruleDoubleQuotedString]() {
-						goto l81
+						goto l98
 					}
-					goto l80
-				l81:
-					position, tokenIndex, depth = position80, tokenIndex80, depth80
+					goto l97
+				l98:
+					position, tokenIndex, depth = position97, tokenIndex97, depth97
 					if !_rules[ruleSingleQuotedString]() {
-						goto l82
+						goto l99
 					}
-					goto l80
-				l82:
-					position, tokenIndex, depth = position80, tokenIndex80, depth80
+					goto l97
+				l99:
+					position, tokenIndex, depth = position97, tokenIndex97, depth97
 					if !_rules[ruleRawString]() {
-						goto l78
+						goto l95
 					}
 				}
-			l80:
+			l97:
 				depth--
-				add(ruleString, position79)
+				add(ruleString, position96)
 			}
 			return true
-		l78:
-			position, tokenIndex, depth = position78, tokenIndex78, depth78
+		l95:
+			position, tokenIndex, depth = position95, tokenIndex95, depth95
 			return false
 		},
-		/* 10 List <- <(<('[' s (s String s ',' s)* s String s ']')> Action4)> */
+		/* 11 List <- <(<('[' s (s String s ',' s)* s String s ']')> Action5)> */
 		func() bool {
-			position83, tokenIndex83, depth83 := position, tokenIndex, depth
+			position100, tokenIndex100, depth100 := position, tokenIndex, depth
 			{
-				position84 := position
+				position101 := position
 				depth++
 				{
-					position85 := position
+					position102 := position
 					depth++
 					if buffer[position] != rune('[') {
-						goto l83
+						goto l100
 					}
 					position++
 					if !_rules[rules]() {
-						goto l83
+						goto l100
 					}
-				l86:
+				l103:
 					{
-						position87, tokenIndex87, depth87 := position, tokenIndex, depth
+						position104, tokenIndex104, depth104 := position, tokenIndex, depth
 						if !_rules[rules]() {
-							goto l87
+							goto l104
 						}
 						if !_rules[ruleString]() {
-							goto l87
+							goto l104
 						}
 						if !_rules[rules]() {
-							goto l87
+							goto l104
 						}
 						if buffer[position] != rune(',') {
-							goto l87
+							goto l104
 						}
 						position++
 						if !_rules[rules]() {
-							goto l87
+							goto l104
 						}
-						goto l86
-					l87:
-						position, tokenIndex, depth = position87, tokenIndex87, depth87
+						goto l103
+					l104:
+						position, tokenIndex, depth = position104, tokenIndex104, depth104
 					}
 					if !_rules[rules]() {
-						goto l83
+						goto l100
 					}
 					if !_rules[ruleString]() {
-						goto l83
+						goto l100
 					}
 					if !_rules[rules]() {
-						goto l83
+						goto l100
 					}
 					if buffer[position] != rune(']') {
-						goto l83
+						goto l100
 					}
 					position++
 					depth--
-					add(rulePegText, position85)
+					add(rulePegText, position102)
 				}
-				if !_rules[ruleAction4]() {
-					goto l83
+				if !_rules[ruleAction5]() {
+					goto l100
 				}
 				depth--
-				add(ruleList, position84)
+				add(ruleList, position101)
 			}
 			return true
-		l83:
-			position, tokenIndex, depth = position83, tokenIndex83, depth83
+		l100:
+			position, tokenIndex, depth = position100, tokenIndex100, depth100
 			return false
 		},
-		/* 11 SingleQuotedString <- <(<('\'' (('\\' '\'') / (!EOL !'\'' .))* '\'')> Action5)> */
+		/* 12 SingleQuotedString <- <(<('\'' (('\\' '\'') / (!EOL !'\'' .))* '\'')> Action6)> */
 		func() bool {
-			position88, tokenIndex88, depth88 := position, tokenIndex, depth
+			position105, tokenIndex105, depth105 := position, tokenIndex, depth
 			{
-				position89 := position
+				position106 := position
 				depth++
 				{
-					position90 := position
+					position107 := position
 					depth++
 					if buffer[position] != rune('\'') {
-						goto l88
+						goto l105
 					}
 					position++
-				l91:
+				l108:
 					{
-						position92, tokenIndex92, depth92 := position, tokenIndex, depth
+						position109, tokenIndex109, depth109 := position, tokenIndex, depth
 						{
-							position93, tokenIndex93, depth93 := position, tokenIndex, depth
+							position110, tokenIndex110, depth110 := position, tokenIndex, depth
 							if buffer[position] != rune('\\') {
-								goto l94
+								goto l111
 							}
 							position++
 							if buffer[position] != rune('\'') {
-								goto l94
+								goto l111
 							}
 							position++
-							goto l93
-						l94:
-							position, tokenIndex, depth = position93, tokenIndex93, depth93
+							goto l110
+						l111:
+							position, tokenIndex, depth = position110, tokenIndex110, depth110
 							{
-								position95, tokenIndex95, depth95 := position, tokenIndex, depth
+								position112, tokenIndex112, depth112 := position, tokenIndex, depth
 								if !_rules[ruleEOL]() {
-									goto l95
+									goto l112
 								}
-								goto l92
-							l95:
-								position, tokenIndex, depth = position95, tokenIndex95, depth95
+								goto l109
+							l112:
+								position, tokenIndex, depth = position112, tokenIndex112, depth112
 							}
 							{
-								position96, tokenIndex96, depth96 := position, tokenIndex, depth
+								position113, tokenIndex113, depth113 := position, tokenIndex, depth
 								if buffer[position] != rune('\'') {
-									goto l96
+									goto l113
 								}
 								position++
-								goto l92
-							l96:
-								position, tokenIndex, depth = position96, tokenIndex96, depth96
+								goto l109
+							l113:
+								position, tokenIndex, depth = position113, tokenIndex113, depth113
 							}
 							if !matchDot() {
-								goto l92
+								goto l109
 							}
 						}
-					l93:
-						goto l91
-					l92:
-						position, tokenIndex, depth = position92, tokenIndex92, depth92
+					l110:
+						goto l108
+					l109:
+						position, tokenIndex, depth = position109, tokenIndex109, depth109
 					}
 					if buffer[position] != rune('\'') {
-						goto l88
+						goto l105
 					}
 					position++
 					depth--
-					add(rulePegText, position90)
+					add(rulePegText, position107)
 				}
-				if !_rules[ruleAction5]() {
-					goto l88
+				if !_rules[ruleAction6]() {
+					goto l105
 				}
 				depth--
-				add(ruleSingleQuotedString, position89)
+				add(ruleSingleQuotedString, position106)
 			}
 			return true
-		l88:
-			position, tokenIndex, depth = position88, tokenIndex88, depth88
+		l105:
+			position, tokenIndex, depth = position105, tokenIndex105, depth105
 			return false
 		},
-		/* 12 DoubleQuotedString <- <(<('"' (('\\' '"') / (!EOL !'"' .))* '"')> Action6)> */
+		/* 13 DoubleQuotedString <- <(<('"' (('\\' '"') / (!EOL !'"' .))* '"')> Action7)> */
 		func() bool {
-			position97, tokenIndex97, depth97 := position, tokenIndex, depth
+			position114, tokenIndex114, depth114 := position, tokenIndex, depth
 			{
-				position98 := position
+				position115 := position
 				depth++
 				{
-					position99 := position
+					position116 := position
 					depth++
 					if buffer[position] != rune('"') {
-						goto l97
+						goto l114
 					}
 					position++
-				l100:
+				l117:
 					{
-						position101, tokenIndex101, depth101 := position, tokenIndex, depth
+						position118, tokenIndex118, depth118 := position, tokenIndex, depth
 						{
-							position102, tokenIndex102, depth102 := position, tokenIndex, depth
+							position119, tokenIndex119, depth119 := position, tokenIndex, depth
 							if buffer[position] != rune('\\') {
-								goto l103
+								goto l120
 							}
 							position++
 							if buffer[position] != rune('"') {
-								goto l103
+								goto l120
 							}
 							position++
-							goto l102
-						l103:
-							position, tokenIndex, depth = position102, tokenIndex102, depth102
+							goto l119
+						l120:
+							position, tokenIndex, depth = position119, tokenIndex119, depth119
 							{
-								position104, tokenIndex104, depth104 := position, tokenIndex, depth
+								position121, tokenIndex121, depth121 := position, tokenIndex, depth
 								if !_rules[ruleEOL]() {
-									goto l104
+									goto l121
 								}
-								goto l101
-							l104:
-								position, tokenIndex, depth = position104, tokenIndex104, depth104
+								goto l118
+							l121:
+								position, tokenIndex, depth = position121, tokenIndex121, depth121
 							}
 							{
-								position105, tokenIndex105, depth105 := position, tokenIndex, depth
+								position122, tokenIndex122, depth122 := position, tokenIndex, depth
 								if buffer[position] != rune('"') {
-									goto l105
+									goto l122
 								}
 								position++
-								goto l101
-							l105:
-								position, tokenIndex, depth = position105, tokenIndex105, depth105
+								goto l118
+							l122:
+								position, tokenIndex, depth = position122, tokenIndex122, depth122
 							}
 							if !matchDot() {
-								goto l101
+								goto l118
 							}
 						}
-					l102:
-						goto l100
-					l101:
-						position, tokenIndex, depth = position101, tokenIndex101, depth101
+					l119:
+						goto l117
+					l118:
+						position, tokenIndex, depth = position118, tokenIndex118, depth118
 					}
 					if buffer[position] != rune('"') {
-						goto l97
+						goto l114
 					}
 					position++
 					depth--
-					add(rulePegText, position99)
+					add(rulePegText, position116)
 				}
-				if !_rules[ruleAction6]() {
-					goto l97
+				if !_rules[ruleAction7]() {
+					goto l114
 				}
 				depth--
-				add(ruleDoubleQuotedString, position98)
+				add(ruleDoubleQuotedString, position115)
 			}
 			return true
-		l97:
-			position, tokenIndex, depth = position97, tokenIndex97, depth97
+		l114:
+			position, tokenIndex, depth = position114, tokenIndex114, depth114
 			return false
 		},
-		/* 13 RawString <- <(<('`' (!'`' .)* '`')> Action7)> */
+		/* 14 RawString <- <(<('`' (!'`' .)* '`')> Action8)> */
 		func() bool {
-			position106, tokenIndex106, depth106 := position, tokenIndex, depth
+			position123, tokenIndex123, depth123 := position, tokenIndex, depth
 			{
-				position107 := position
+				position124 := position
 				depth++
 				{
-					position108 := position
+					position125 := position
 					depth++
 					if buffer[position] != rune('`') {
-						goto l106
+						goto l123
 					}
 					position++
-				l109:
+				l126:
 					{
-						position110, tokenIndex110, depth110 := position, tokenIndex, depth
+						position127, tokenIndex127, depth127 := position, tokenIndex, depth
 						{
-							position111, tokenIndex111, depth111 := position, tokenIndex, depth
+							position128, tokenIndex128, depth128 := position, tokenIndex, depth
 							if buffer[position] != rune('`') {
-								goto l111
+								goto l128
 							}
 							position++
-							goto l110
-						l111:
-							position, tokenIndex, depth = position111, tokenIndex111, depth111
+							goto l127
+						l128:
+							position, tokenIndex, depth = position128, tokenIndex128, depth128
 						}
 						if !matchDot() {
-							goto l110
+							goto l127
 						}
-						goto l109
-					l110:
-						position, tokenIndex, depth = position110, tokenIndex110, depth110
+						goto l126
+					l127:
+						position, tokenIndex, depth = position127, tokenIndex127, depth127
 					}
 					if buffer[position] != rune('`') {
-						goto l106
+						goto l123
 					}
 					position++
 					depth--
-					add(rulePegText, position108)
+					add(rulePegText, position125)
 				}
-				if !_rules[ruleAction7]() {
-					goto l106
+				if !_rules[ruleAction8]() {
+					goto l123
 				}
 				depth--
-				add(ruleRawString, position107)
+				add(ruleRawString, position124)
 			}
 			return true
-		l106:
-			position, tokenIndex, depth = position106, tokenIndex106, depth106
+		l123:
+			position, tokenIndex, depth = position123, tokenIndex123, depth123
 			return false
 		},
-		/* 14 Separator <- <(s ('-' '-' '-') '-'* s EOL)> */
+		/* 15 Separator <- <(s ('-' '-' '-') '-'* s EOL)> */
 		func() bool {
-			position112, tokenIndex112, depth112 := position, tokenIndex, depth
+			position129, tokenIndex129, depth129 := position, tokenIndex, depth
 			{
-				position113 := position
+				position130 := position
 				depth++
 				if !_rules[rules]() {
-					goto l112
+					goto l129
 				}
 				if buffer[position] != rune('-') {
-					goto l112
+					goto l129
 				}
 				position++
 				if buffer[position] != rune('-') {
-					goto l112
+					goto l129
 				}
 				position++
 				if buffer[position] != rune('-') {
-					goto l112
+					goto l129
 				}
 				position++
-			l114:
+			l131:
 				{
-					position115, tokenIndex115, depth115 := position, tokenIndex, depth
+					position132, tokenIndex132, depth132 := position, tokenIndex, depth
 					if buffer[position] != rune('-') {
-						goto l115
+						goto l132
 					}
 					position++
-					goto l114
-				l115:
-					position, tokenIndex, depth = position115, tokenIndex115, depth115
+					goto l131
+				l132:
+					position, tokenIndex, depth = position132, tokenIndex132, depth132
 				}
 				if !_rules[rules]() {
-					goto l112
+					goto l129
 				}
 				if !_rules[ruleEOL]() {
-					goto l112
+					goto l129
 				}
 				depth--
-				add(ruleSeparator, position113)
+				add(ruleSeparator, position130)
 			}
 			return true
-		l112:
-			position, tokenIndex, depth = position112, tokenIndex112, depth112
+		l129:
+			position, tokenIndex, depth = position129, tokenIndex129, depth129
 			return false
 		},
-		/* 15 Templates <- <(!Separator (Comment / Template) EOL)*> */
+		/* 16 Templates <- <(!Separator (Comment / Annotation / Template) EOL)*> */
 		func() bool {
 			{
-				position117 := position
+				position134 := position
 				depth++
-			l118:
+			l135:
 				{
-					position119, tokenIndex119, depth119 := position, tokenIndex, depth
+					position136, tokenIndex136, depth136 := position, tokenIndex, depth
 					{
-						position120, tokenIndex120, depth120 := position, tokenIndex, depth
+						position137, tokenIndex137, depth137 := position, tokenIndex, depth
 						if !_rules[ruleSeparator]() {
-							goto l120
+							goto l137
 						}
-						goto l119
-					l120:
-						position, tokenIndex, depth = position120, tokenIndex120, depth120
+						goto l136
+					l137:
+						position, tokenIndex, depth = position137, tokenIndex137, depth137
 					}
 					{
-						position121, tokenIndex121, depth121 := position, tokenIndex, depth
+						position138, tokenIndex138, depth138 := position, tokenIndex, depth
 						if !_rules[ruleComment]() {
-							goto l122
+							goto l139
+						}
+						goto l138
+					l139:
+						position, tokenIndex, depth = position138, tokenIndex138, depth138
+						if !_rules[ruleAnnotation]() {
+							goto l140
 						}
-						goto l121
-					l122:
-						position, tokenIndex, depth = position121, tokenIndex121, depth121
+						goto l138
+					l140:
+						position, tokenIndex, depth = position138, tokenIndex138, depth138
 						if !_rules[ruleTemplate]() {
-							goto l119
+							goto l136
 						}
 					}
-				l121:
+				l138:
 					if !_rules[ruleEOL]() {
-						goto l119
+						goto l136
 					}
-					goto l118
-				l119:
-					position, tokenIndex, depth = position119, tokenIndex119, depth119
+					goto l135
+				l136:
+					position, tokenIndex, depth = position136, tokenIndex136, depth136
 				}
 				depth--
-				add(ruleTemplates, position117)
+				add(ruleTemplates, position134)
 			}
 			return true
 		},
-		/* 16 Template <- <(s <(!EOL .)*> Action8)> */
+		/* 17 Annotation <- <(s '@' Name s '=' s Value s Comment? Action9)> */
 		func() bool {
-			position123, tokenIndex123, depth123 := position, tokenIndex, depth
+			position141, tokenIndex141, depth141 := position, tokenIndex, depth
 			{
-				position124 := position
+				position142 := position
 				depth++
 				if !_rules[rules]() {
-					goto l123
+					goto l141
 				}
-				{
-					position125 := position
-					depth++
-				l126:
-					{
-						position127, tokenIndex127, depth127 := position, tokenIndex, depth
-						{
-							position128, tokenIndex128, depth128 := position, tokenIndex, depth
-							if !_rules[ruleEOL]() {
-								goto l128
-							}
-							goto l127
-						l128:
-							position, tokenIndex, depth = position128, tokenIndex128, depth128
-						}
-						if !matchDot() {
-							goto l127
-						}
-						goto l126
-					l127:
-						position, tokenIndex, depth = position127, tokenIndex127, depth127
-					}
-					depth--
-					add(rulePegText, position125)
+				if buffer[position] != rune('@') {
+					goto l141
 				}
-				if !_rules[ruleAction8]() {
-					goto l123
+				position++
+				if !_rules[ruleName]() {
+					goto l141
 				}
-				depth--
-				add(ruleTemplate, position124)
-			}
-			return true
-		l123:
-			position, tokenIndex, depth = position123, tokenIndex123, depth123
-			return false
-		},
-		/* 17 Samples <- <((Comment / Sample) EOL)*> */
-		func() bool {
-			{
-				position130 := position
-				depth++
-			l131:
+				if !_rules[rules]() {
+					goto l141
+				}
+				if buffer[position] != rune('=') {
+					goto l141
+				}
+				position++
+				if !_rules[rules]() {
+					goto l141
+				}
+				if !_rules[ruleValue]() {
+					goto l141
+				}
+				if !_rules[rules]() {
+					goto l141
+				}
+				{
+					position143, tokenIndex143, depth143 := position, tokenIndex, depth
+					if !_rules[ruleComment]() {
+						goto l143
+					}
+					goto l144
+				l143:
+					position, tokenIndex, depth = position143, tokenIndex143, depth143
+				}
+			l144:
+				if !_rules[ruleAction9]() {
+					goto l141
+				}
+				depth--
+				add(ruleAnnotation, position142)
+			}
+			return true
+		l141:
+			position, tokenIndex, depth = position141, tokenIndex141, depth141
+			return false
+		},
+		/* 18 Template <- <(s (RawTemplate / TextTemplate) s)> */
+		func() bool {
+			position145, tokenIndex145, depth145 := position, tokenIndex, depth
+			{
+				position146 := position
+				depth++
+				if !_rules[rules]() {
+					goto l145
+				}
+				{
+					position147, tokenIndex147, depth147 := position, tokenIndex, depth
+					if !_rules[ruleRawTemplate]() {
+						goto l148
+					}
+					goto l147
+				l148:
+					position, tokenIndex, depth = position147, tokenIndex147, depth147
+					if !_rules[ruleTextTemplate]() {
+						goto l145
+					}
+				}
+			l147:
+				if !_rules[rules]() {
+					goto l145
+				}
+				depth--
+				add(ruleTemplate, position146)
+			}
+			return true
+		l145:
+			position, tokenIndex, depth = position145, tokenIndex145, depth145
+			return false
+		},
+		/* 19 TextTemplate <- <(<TemplatePart*> Action10)> */
+		func() bool {
+			position149, tokenIndex149, depth149 := position, tokenIndex, depth
+			{
+				position150 := position
+				depth++
+				{
+					position151 := position
+					depth++
+				l152:
+					{
+						position153, tokenIndex153, depth153 := position, tokenIndex, depth
+						if !_rules[ruleTemplatePart]() {
+							goto l153
+						}
+						goto l152
+					l153:
+						position, tokenIndex, depth = position153, tokenIndex153, depth153
+					}
+					depth--
+					add(rulePegText, position151)
+				}
+				if !_rules[ruleAction10]() {
+					goto l149
+				}
+				depth--
+				add(ruleTextTemplate, position150)
+			}
+			return true
+		l149:
+			position, tokenIndex, depth = position149, tokenIndex149, depth149
+			return false
+		},
+		/* 20 TemplatePart <- <(Repeat / Wildcard / Placeholder / Optional / Alternation / Escape / TemplateText)> */
+		func() bool {
+			position154, tokenIndex154, depth154 := position, tokenIndex, depth
+			{
+				position155 := position
+				depth++
+				{
+					position156, tokenIndex156, depth156 := position, tokenIndex, depth
+					if !_rules[ruleRepeat]() {
+						goto l157
+					}
+					goto l156
+				l157:
+					position, tokenIndex, depth = position156, tokenIndex156, depth156
+					if !_rules[ruleWildcard]() {
+						goto l158
+					}
+					goto l156
+				l158:
+					position, tokenIndex, depth = position156, tokenIndex156, depth156
+					if !_rules[rulePlaceholder]() {
+						goto l159
+					}
+					goto l156
+				l159:
+					position, tokenIndex, depth = position156, tokenIndex156, depth156
+					if !_rules[ruleOptional]() {
+						goto l160
+					}
+					goto l156
+				l160:
+					position, tokenIndex, depth = position156, tokenIndex156, depth156
+					if !_rules[ruleAlternation]() {
+						goto l161
+					}
+					goto l156
+				l161:
+					position, tokenIndex, depth = position156, tokenIndex156, depth156
+					if !_rules[ruleEscape]() {
+						goto l162
+					}
+					goto l156
+				l162:
+					position, tokenIndex, depth = position156, tokenIndex156, depth156
+					if !_rules[ruleTemplateText]() {
+						goto l154
+					}
+				}
+			l156:
+				depth--
+				add(ruleTemplatePart, position155)
+			}
+			return true
+		l154:
+			position, tokenIndex, depth = position154, tokenIndex154, depth154
+			return false
+		},
+		/* 21 TemplateText <- <(<(!TextEnd .)+> Action11)> */
+		func() bool {
+			position163, tokenIndex163, depth163 := position, tokenIndex, depth
+			{
+				position164 := position
+				depth++
+				{
+					position165 := position
+					depth++
+					{
+						position168, tokenIndex168, depth168 := position, tokenIndex, depth
+						if !_rules[ruleTextEnd]() {
+							goto l168
+						}
+						goto l163
+					l168:
+						position, tokenIndex, depth = position168, tokenIndex168, depth168
+					}
+					if !matchDot() {
+						goto l163
+					}
+				l166:
+					{
+						position167, tokenIndex167, depth167 := position, tokenIndex, depth
+						{
+							position169, tokenIndex169, depth169 := position, tokenIndex, depth
+							if !_rules[ruleTextEnd]() {
+								goto l169
+							}
+							goto l167
+						l169:
+							position, tokenIndex, depth = position169, tokenIndex169, depth169
+						}
+						if !matchDot() {
+							goto l167
+						}
+						goto l166
+					l167:
+						position, tokenIndex, depth = position167, tokenIndex167, depth167
+					}
+					depth--
+					add(rulePegText, position165)
+				}
+				if !_rules[ruleAction11]() {
+					goto l163
+				}
+				depth--
+				add(ruleTemplateText, position164)
+			}
+			return true
+		l163:
+			position, tokenIndex, depth = position163, tokenIndex163, depth163
+			return false
+		},
+		/* 22 TextEnd <- <(('{' '{') / ('[' '[') / ('.' '.' '.') / Alternation / Escape / TrailingSpace / EOL)> */
+		func() bool {
+			position170, tokenIndex170, depth170 := position, tokenIndex, depth
+			{
+				position171 := position
+				depth++
+				{
+					position172, tokenIndex172, depth172 := position, tokenIndex, depth
+					if buffer[position] != rune('{') {
+						goto l173
+					}
+					position++
+					if buffer[position] != rune('{') {
+						goto l173
+					}
+					position++
+					goto l172
+				l173:
+					position, tokenIndex, depth = position172, tokenIndex172, depth172
+					if buffer[position] != rune('[') {
+						goto l174
+					}
+					position++
+					if buffer[position] != rune('[') {
+						goto l174
+					}
+					position++
+					goto l172
+				l174:
+					position, tokenIndex, depth = position172, tokenIndex172, depth172
+					if buffer[position] != rune('.') {
+						goto l175
+					}
+					position++
+					if buffer[position] != rune('.') {
+						goto l175
+					}
+					position++
+					if buffer[position] != rune('.') {
+						goto l175
+					}
+					position++
+					goto l172
+				l175:
+					position, tokenIndex, depth = position172, tokenIndex172, depth172
+					if !_rules[ruleAlternation]() {
+						goto l176
+					}
+					goto l172
+				l176:
+					position, tokenIndex, depth = position172, tokenIndex172, depth172
+					if !_rules[ruleEscape]() {
+						goto l177
+					}
+					goto l172
+				l177:
+					position, tokenIndex, depth = position172, tokenIndex172, depth172
+					if !_rules[ruleTrailingSpace]() {
+						goto l178
+					}
+					goto l172
+				l178:
+					position, tokenIndex, depth = position172, tokenIndex172, depth172
+					if !_rules[ruleEOL]() {
+						goto l170
+					}
+				}
+			l172:
+				depth--
+				add(ruleTextEnd, position171)
+			}
+			return true
+		l170:
+			position, tokenIndex, depth = position170, tokenIndex170, depth170
+			return false
+		},
+		/* 23 TrailingSpace <- <(s (EOL / EOF))> */
+		func() bool {
+			position179, tokenIndex179, depth179 := position, tokenIndex, depth
+			{
+				position180 := position
+				depth++
+				if !_rules[rules]() {
+					goto l179
+				}
+				{
+					position181, tokenIndex181, depth181 := position, tokenIndex, depth
+					if !_rules[ruleEOL]() {
+						goto l182
+					}
+					goto l181
+				l182:
+					position, tokenIndex, depth = position181, tokenIndex181, depth181
+					if !_rules[ruleEOF]() {
+						goto l179
+					}
+				}
+			l181:
+				depth--
+				add(ruleTrailingSpace, position180)
+			}
+			return true
+		l179:
+			position, tokenIndex, depth = position179, tokenIndex179, depth179
+			return false
+		},
+		/* 24 Escape <- <('\\' <Punct> Action12)> */
+		func() bool {
+			position183, tokenIndex183, depth183 := position, tokenIndex, depth
+			{
+				position184 := position
+				depth++
+				if buffer[position] != rune('\\') {
+					goto l183
+				}
+				position++
+				{
+					position185 := position
+					depth++
+					if !_rules[rulePunct]() {
+						goto l183
+					}
+					depth--
+					add(rulePegText, position185)
+				}
+				if !_rules[ruleAction12]() {
+					goto l183
+				}
+				depth--
+				add(ruleEscape, position184)
+			}
+			return true
+		l183:
+			position, tokenIndex, depth = position183, tokenIndex183, depth183
+			return false
+		},
+		/* 25 Punct <- <([ -/] / [:-@] / '[' / '\\' / ']' / '^' / '_' / '`' / [{-~])> */
+		func() bool {
+			position186, tokenIndex186, depth186 := position, tokenIndex, depth
+			{
+				position187 := position
+				depth++
+				{
+					position188, tokenIndex188, depth188 := position, tokenIndex, depth
+					if c := buffer[position]; c < rune(' ') || c > rune('/') {
+						goto l189
+					}
+					position++
+					goto l188
+				l189:
+					position, tokenIndex, depth = position188, tokenIndex188, depth188
+					if c := buffer[position]; c < rune(':') || c > rune('@') {
+						goto l190
+					}
+					position++
+					goto l188
+				l190:
+					position, tokenIndex, depth = position188, tokenIndex188, depth188
+					if buffer[position] != rune('[') {
+						goto l191
+					}
+					position++
+					goto l188
+				l191:
+					position, tokenIndex, depth = position188, tokenIndex188, depth188
+					if buffer[position] != rune('\\') {
+						goto l192
+					}
+					position++
+					goto l188
+				l192:
+					position, tokenIndex, depth = position188, tokenIndex188, depth188
+					if buffer[position] != rune(']') {
+						goto l193
+					}
+					position++
+					goto l188
+				l193:
+					position, tokenIndex, depth = position188, tokenIndex188, depth188
+					if buffer[position] != rune('^') {
+						goto l194
+					}
+					position++
+					goto l188
+				l194:
+					position, tokenIndex, depth = position188, tokenIndex188, depth188
+					if buffer[position] != rune('_') {
+						goto l195
+					}
+					position++
+					goto l188
+				l195:
+					position, tokenIndex, depth = position188, tokenIndex188, depth188
+					if buffer[position] != rune('`') {
+						goto l196
+					}
+					position++
+					goto l188
+				l196:
+					position, tokenIndex, depth = position188, tokenIndex188, depth188
+					if c := buffer[position]; c < rune('{') || c > rune('~') {
+						goto l186
+					}
+					position++
+				}
+			l188:
+				depth--
+				add(rulePunct, position187)
+			}
+			return true
+		l186:
+			position, tokenIndex, depth = position186, tokenIndex186, depth186
+			return false
+		},
+		/* 26 Optional <- <('[' '[' Action13 OptionalPart* (']' ']') Action14)> */
+		func() bool {
+			position197, tokenIndex197, depth197 := position, tokenIndex, depth
+			{
+				position198 := position
+				depth++
+				if buffer[position] != rune('[') {
+					goto l197
+				}
+				position++
+				if buffer[position] != rune('[') {
+					goto l197
+				}
+				position++
+				if !_rules[ruleAction13]() {
+					goto l197
+				}
+			l199:
+				{
+					position200, tokenIndex200, depth200 := position, tokenIndex, depth
+					if !_rules[ruleOptionalPart]() {
+						goto l200
+					}
+					goto l199
+				l200:
+					position, tokenIndex, depth = position200, tokenIndex200, depth200
+				}
+				if buffer[position] != rune(']') {
+					goto l197
+				}
+				position++
+				if buffer[position] != rune(']') {
+					goto l197
+				}
+				position++
+				if !_rules[ruleAction14]() {
+					goto l197
+				}
+				depth--
+				add(ruleOptional, position198)
+			}
+			return true
+		l197:
+			position, tokenIndex, depth = position197, tokenIndex197, depth197
+			return false
+		},
+		/* 27 OptionalPart <- <(Repeat / Wildcard / Placeholder / Optional / Alternation / Escape / OptionalText)> */
+		func() bool {
+			position201, tokenIndex201, depth201 := position, tokenIndex, depth
+			{
+				position202 := position
+				depth++
+				{
+					position203, tokenIndex203, depth203 := position, tokenIndex, depth
+					if !_rules[ruleRepeat]() {
+						goto l204
+					}
+					goto l203
+				l204:
+					position, tokenIndex, depth = position203, tokenIndex203, depth203
+					if !_rules[ruleWildcard]() {
+						goto l205
+					}
+					goto l203
+				l205:
+					position, tokenIndex, depth = position203, tokenIndex203, depth203
+					if !_rules[rulePlaceholder]() {
+						goto l206
+					}
+					goto l203
+				l206:
+					position, tokenIndex, depth = position203, tokenIndex203, depth203
+					if !_rules[ruleOptional]() {
+						goto l207
+					}
+					goto l203
+				l207:
+					position, tokenIndex, depth = position203, tokenIndex203, depth203
+					if !_rules[ruleAlternation]() {
+						goto l208
+					}
+					goto l203
+				l208:
+					position, tokenIndex, depth = position203, tokenIndex203, depth203
+					if !_rules[ruleEscape]() {
+						goto l209
+					}
+					goto l203
+				l209:
+					position, tokenIndex, depth = position203, tokenIndex203, depth203
+					if !_rules[ruleOptionalText]() {
+						goto l201
+					}
+				}
+			l203:
+				depth--
+				add(ruleOptionalPart, position202)
+			}
+			return true
+		l201:
+			position, tokenIndex, depth = position201, tokenIndex201, depth201
+			return false
+		},
+		/* 28 OptionalText <- <(<(!TextEnd !(']' ']') .)+> Action15)> */
+		func() bool {
+			position210, tokenIndex210, depth210 := position, tokenIndex, depth
+			{
+				position211 := position
+				depth++
+				{
+					position212 := position
+					depth++
+					{
+						position215, tokenIndex215, depth215 := position, tokenIndex, depth
+						if !_rules[ruleTextEnd]() {
+							goto l215
+						}
+						goto l210
+					l215:
+						position, tokenIndex, depth = position215, tokenIndex215, depth215
+					}
+					{
+						position216, tokenIndex216, depth216 := position, tokenIndex, depth
+						if buffer[position] != rune(']') {
+							goto l216
+						}
+						position++
+						if buffer[position] != rune(']') {
+							goto l216
+						}
+						position++
+						goto l210
+					l216:
+						position, tokenIndex, depth = position216, tokenIndex216, depth216
+					}
+					if !matchDot() {
+						goto l210
+					}
+				l213:
+					{
+						position214, tokenIndex214, depth214 := position, tokenIndex, depth
+						{
+							position217, tokenIndex217, depth217 := position, tokenIndex, depth
+							if !_rules[ruleTextEnd]() {
+								goto l217
+							}
+							goto l214
+						l217:
+							position, tokenIndex, depth = position217, tokenIndex217, depth217
+						}
+						{
+							position218, tokenIndex218, depth218 := position, tokenIndex, depth
+							if buffer[position] != rune(']') {
+								goto l218
+							}
+							position++
+							if buffer[position] != rune(']') {
+								goto l218
+							}
+							position++
+							goto l214
+						l218:
+							position, tokenIndex, depth = position218, tokenIndex218, depth218
+						}
+						if !matchDot() {
+							goto l214
+						}
+						goto l213
+					l214:
+						position, tokenIndex, depth = position214, tokenIndex214, depth214
+					}
+					depth--
+					add(rulePegText, position212)
+				}
+				if !_rules[ruleAction15]() {
+					goto l210
+				}
+				depth--
+				add(ruleOptionalText, position211)
+			}
+			return true
+		l210:
+			position, tokenIndex, depth = position210, tokenIndex210, depth210
+			return false
+		},
+		/* 29 Alternation <- <('(' Action16 AlternativePart* ('|' Action17 AlternativePart*)+ ')' Action18)> */
+		func() bool {
+			position219, tokenIndex219, depth219 := position, tokenIndex, depth
+			{
+				position220 := position
+				depth++
+				if buffer[position] != rune('(') {
+					goto l219
+				}
+				position++
+				if !_rules[ruleAction16]() {
+					goto l219
+				}
+			l221:
+				{
+					position222, tokenIndex222, depth222 := position, tokenIndex, depth
+					if !_rules[ruleAlternativePart]() {
+						goto l222
+					}
+					goto l221
+				l222:
+					position, tokenIndex, depth = position222, tokenIndex222, depth222
+				}
+				if buffer[position] != rune('|') {
+					goto l219
+				}
+				position++
+				if !_rules[ruleAction17]() {
+					goto l219
+				}
+			l225:
+				{
+					position226, tokenIndex226, depth226 := position, tokenIndex, depth
+					if !_rules[ruleAlternativePart]() {
+						goto l226
+					}
+					goto l225
+				l226:
+					position, tokenIndex, depth = position226, tokenIndex226, depth226
+				}
+			l223:
+				{
+					position224, tokenIndex224, depth224 := position, tokenIndex, depth
+					if buffer[position] != rune('|') {
+						goto l224
+					}
+					position++
+					if !_rules[ruleAction17]() {
+						goto l224
+					}
+				l227:
+					{
+						position228, tokenIndex228, depth228 := position, tokenIndex, depth
+						if !_rules[ruleAlternativePart]() {
+							goto l228
+						}
+						goto l227
+					l228:
+						position, tokenIndex, depth = position228, tokenIndex228, depth228
+					}
+					goto l223
+				l224:
+					position, tokenIndex, depth = position224, tokenIndex224, depth224
+				}
+				if buffer[position] != rune(')') {
+					goto l219
+				}
+				position++
+				if !_rules[ruleAction18]() {
+					goto l219
+				}
+				depth--
+				add(ruleAlternation, position220)
+			}
+			return true
+		l219:
+			position, tokenIndex, depth = position219, tokenIndex219, depth219
+			return false
+		},
+		/* 30 AlternativePart <- <(Repeat / Wildcard / Placeholder / Optional / Alternation / Escape / AlternativeText)> */
+		func() bool {
+			position229, tokenIndex229, depth229 := position, tokenIndex, depth
+			{
+				position230 := position
+				depth++
+				{
+					position231, tokenIndex231, depth231 := position, tokenIndex, depth
+					if !_rules[ruleRepeat]() {
+						goto l232
+					}
+					goto l231
+				l232:
+					position, tokenIndex, depth = position231, tokenIndex231, depth231
+					if !_rules[ruleWildcard]() {
+						goto l233
+					}
+					goto l231
+				l233:
+					position, tokenIndex, depth = position231, tokenIndex231, depth231
+					if !_rules[rulePlaceholder]() {
+						goto l234
+					}
+					goto l231
+				l234:
+					position, tokenIndex, depth = position231, tokenIndex231, depth231
+					if !_rules[ruleOptional]() {
+						goto l235
+					}
+					goto l231
+				l235:
+					position, tokenIndex, depth = position231, tokenIndex231, depth231
+					if !_rules[ruleAlternation]() {
+						goto l236
+					}
+					goto l231
+				l236:
+					position, tokenIndex, depth = position231, tokenIndex231, depth231
+					if !_rules[ruleEscape]() {
+						goto l237
+					}
+					goto l231
+				l237:
+					position, tokenIndex, depth = position231, tokenIndex231, depth231
+					if !_rules[ruleAlternativeText]() {
+						goto l229
+					}
+				}
+			l231:
+				depth--
+				add(ruleAlternativePart, position230)
+			}
+			return true
+		l229:
+			position, tokenIndex, depth = position229, tokenIndex229, depth229
+			return false
+		},
+		/* 31 AlternativeText <- <(<(!TextEnd !(']' ']') !('|' / ')') .)+> Action19)> */
+		func() bool {
+			position238, tokenIndex238, depth238 := position, tokenIndex, depth
+			{
+				position239 := position
+				depth++
+				{
+					position240 := position
+					depth++
+					{
+						position243, tokenIndex243, depth243 := position, tokenIndex, depth
+						if !_rules[ruleTextEnd]() {
+							goto l243
+						}
+						goto l238
+					l243:
+						position, tokenIndex, depth = position243, tokenIndex243, depth243
+					}
+					{
+						position244, tokenIndex244, depth244 := position, tokenIndex, depth
+						if buffer[position] != rune(']') {
+							goto l244
+						}
+						position++
+						if buffer[position] != rune(']') {
+							goto l244
+						}
+						position++
+						goto l238
+					l244:
+						position, tokenIndex, depth = position244, tokenIndex244, depth244
+					}
+					{
+						position245, tokenIndex245, depth245 := position, tokenIndex, depth
+						{
+							position246, tokenIndex246, depth246 := position, tokenIndex, depth
+							if buffer[position] != rune('|') {
+								goto l247
+							}
+							position++
+							goto l246
+						l247:
+							position, tokenIndex, depth = position246, tokenIndex246, depth246
+							if buffer[position] != rune(')') {
+								goto l245
+							}
+							position++
+						}
+					l246:
+						goto l238
+					l245:
+						position, tokenIndex, depth = position245, tokenIndex245, depth245
+					}
+					if !matchDot() {
+						goto l238
+					}
+				l241:
+					{
+						position242, tokenIndex242, depth242 := position, tokenIndex, depth
+						{
+							position248, tokenIndex248, depth248 := position, tokenIndex, depth
+							if !_rules[ruleTextEnd]() {
+								goto l248
+							}
+							goto l242
+						l248:
+							position, tokenIndex, depth = position248, tokenIndex248, depth248
+						}
+						{
+							position249, tokenIndex249, depth249 := position, tokenIndex, depth
+							if buffer[position] != rune(']') {
+								goto l249
+							}
+							position++
+							if buffer[position] != rune(']') {
+								goto l249
+							}
+							position++
+							goto l242
+						l249:
+							position, tokenIndex, depth = position249, tokenIndex249, depth249
+						}
+						{
+							position250, tokenIndex250, depth250 := position, tokenIndex, depth
+							{
+								position251, tokenIndex251, depth251 := position, tokenIndex, depth
+								if buffer[position] != rune('|') {
+									goto l252
+								}
+								position++
+								goto l251
+							l252:
+								position, tokenIndex, depth = position251, tokenIndex251, depth251
+								if buffer[position] != rune(')') {
+									goto l250
+								}
+								position++
+							}
+						l251:
+							goto l242
+						l250:
+							position, tokenIndex, depth = position250, tokenIndex250, depth250
+						}
+						if !matchDot() {
+							goto l242
+						}
+						goto l241
+					l242:
+						position, tokenIndex, depth = position242, tokenIndex242, depth242
+					}
+					depth--
+					add(rulePegText, position240)
+				}
+				if !_rules[ruleAction19]() {
+					goto l238
+				}
+				depth--
+				add(ruleAlternativeText, position239)
+			}
+			return true
+		l238:
+			position, tokenIndex, depth = position238, tokenIndex238, depth238
+			return false
+		},
+		/* 32 Repeat <- <('{' '{' ('r' / 'R') ('e' / 'E') ('p' / 'P') ('e' / 'E') ('a' / 'A') ('t' / 'T') ':' s Action20 RepeatPart* RepeatSeparator? s ('}' '}') Action21)> */
+		func() bool {
+			position253, tokenIndex253, depth253 := position, tokenIndex, depth
+			{
+				position254 := position
+				depth++
+				if buffer[position] != rune('{') {
+					goto l253
+				}
+				position++
+				if buffer[position] != rune('{') {
+					goto l253
+				}
+				position++
+				{
+					position255, tokenIndex255, depth255 := position, tokenIndex, depth
+					if buffer[position] != rune('r') {
+						goto l256
+					}
+					position++
+					goto l255
+				l256:
+					position, tokenIndex, depth = position255, tokenIndex255, depth255
+					if buffer[position] != rune('R') {
+						goto l253
+					}
+					position++
+				}
+			l255:
+				{
+					position257, tokenIndex257, depth257 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l258
+					}
+					position++
+					goto l257
+				l258:
+					position, tokenIndex, depth = position257, tokenIndex257, depth257
+					if buffer[position] != rune('E') {
+						goto l253
+					}
+					position++
+				}
+			l257:
+				{
+					position259, tokenIndex259, depth259 := position, tokenIndex, depth
+					if buffer[position] != rune('p') {
+						goto l260
+					}
+					position++
+					goto l259
+				l260:
+					position, tokenIndex, depth = position259, tokenIndex259, depth259
+					if buffer[position] != rune('P') {
+						goto l253
+					}
+					position++
+				}
+			l259:
+				{
+					position261, tokenIndex261, depth261 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l262
+					}
+					position++
+					goto l261
+				l262:
+					position, tokenIndex, depth = position261, tokenIndex261, depth261
+					if buffer[position] != rune('E') {
+						goto l253
+					}
+					position++
+				}
+			l261:
+				{
+					position263, tokenIndex263, depth263 := position, tokenIndex, depth
+					if buffer[position] != rune('a') {
+						goto l264
+					}
+					position++
+					goto l263
+				l264:
+					position, tokenIndex, depth = position263, tokenIndex263, depth263
+					if buffer[position] != rune('A') {
+						goto l253
+					}
+					position++
+				}
+			l263:
+				{
+					position265, tokenIndex265, depth265 := position, tokenIndex, depth
+					if buffer[position] != rune('t') {
+						goto l266
+					}
+					position++
+					goto l265
+				l266:
+					position, tokenIndex, depth = position265, tokenIndex265, depth265
+					if buffer[position] != rune('T') {
+						goto l253
+					}
+					position++
+				}
+			l265:
+				if buffer[position] != rune(':') {
+					goto l253
+				}
+				position++
+				if !_rules[rules]() {
+					goto l253
+				}
+				if !_rules[ruleAction20]() {
+					goto l253
+				}
+			l267:
+				{
+					position268, tokenIndex268, depth268 := position, tokenIndex, depth
+					if !_rules[ruleRepeatPart]() {
+						goto l268
+					}
+					goto l267
+				l268:
+					position, tokenIndex, depth = position268, tokenIndex268, depth268
+				}
+				{
+					position269, tokenIndex269, depth269 := position, tokenIndex, depth
+					if !_rules[ruleRepeatSeparator]() {
+						goto l269
+					}
+					goto l270
+				l269:
+					position, tokenIndex, depth = position269, tokenIndex269, depth269
+				}
+			l270:
+				if !_rules[rules]() {
+					goto l253
+				}
+				if buffer[position] != rune('}') {
+					goto l253
+				}
+				position++
+				if buffer[position] != rune('}') {
+					goto l253
+				}
+				position++
+				if !_rules[ruleAction21]() {
+					goto l253
+				}
+				depth--
+				add(ruleRepeat, position254)
+			}
+			return true
+		l253:
+			position, tokenIndex, depth = position253, tokenIndex253, depth253
+			return false
+		},
+		/* 33 RepeatPart <- <(Repeat / Wildcard / Placeholder / Optional / Alternation / Escape / RepeatText)> */
+		func() bool {
+			position271, tokenIndex271, depth271 := position, tokenIndex, depth
+			{
+				position272 := position
+				depth++
+				{
+					position273, tokenIndex273, depth273 := position, tokenIndex, depth
+					if !_rules[ruleRepeat]() {
+						goto l274
+					}
+					goto l273
+				l274:
+					position, tokenIndex, depth = position273, tokenIndex273, depth273
+					if !_rules[ruleWildcard]() {
+						goto l275
+					}
+					goto l273
+				l275:
+					position, tokenIndex, depth = position273, tokenIndex273, depth273
+					if !_rules[rulePlaceholder]() {
+						goto l276
+					}
+					goto l273
+				l276:
+					position, tokenIndex, depth = position273, tokenIndex273, depth273
+					if !_rules[ruleOptional]() {
+						goto l277
+					}
+					goto l273
+				l277:
+					position, tokenIndex, depth = position273, tokenIndex273, depth273
+					if !_rules[ruleAlternation]() {
+						goto l278
+					}
+					goto l273
+				l278:
+					position, tokenIndex, depth = position273, tokenIndex273, depth273
+					if !_rules[ruleEscape]() {
+						goto l279
+					}
+					goto l273
+				l279:
+					position, tokenIndex, depth = position273, tokenIndex273, depth273
+					if !_rules[ruleRepeatText]() {
+						goto l271
+					}
+				}
+			l273:
+				depth--
+				add(ruleRepeatPart, position272)
+			}
+			return true
+		l271:
+			position, tokenIndex, depth = position271, tokenIndex271, depth271
+			return false
+		},
+		/* 34 RepeatText <- <(<(!TextEnd !SeparatorKey !(s ('}' '}')) .)+> Action22)> */
+		func() bool {
+			position280, tokenIndex280, depth280 := position, tokenIndex, depth
+			{
+				position281 := position
+				depth++
+				{
+					position282 := position
+					depth++
+					{
+						position285, tokenIndex285, depth285 := position, tokenIndex, depth
+						if !_rules[ruleTextEnd]() {
+							goto l285
+						}
+						goto l280
+					l285:
+						position, tokenIndex, depth = position285, tokenIndex285, depth285
+					}
+					{
+						position286, tokenIndex286, depth286 := position, tokenIndex, depth
+						if !_rules[ruleSeparatorKey]() {
+							goto l286
+						}
+						goto l280
+					l286:
+						position, tokenIndex, depth = position286, tokenIndex286, depth286
+					}
+					{
+						position287, tokenIndex287, depth287 := position, tokenIndex, depth
+						if !_rules[rules]() {
+							goto l287
+						}
+						if buffer[position] != rune('}') {
+							goto l287
+						}
+						position++
+						if buffer[position] != rune('}') {
+							goto l287
+						}
+						position++
+						goto l280
+					l287:
+						position, tokenIndex, depth = position287, tokenIndex287, depth287
+					}
+					if !matchDot() {
+						goto l280
+					}
+				l283:
+					{
+						position284, tokenIndex284, depth284 := position, tokenIndex, depth
+						{
+							position288, tokenIndex288, depth288 := position, tokenIndex, depth
+							if !_rules[ruleTextEnd]() {
+								goto l288
+							}
+							goto l284
+						l288:
+							position, tokenIndex, depth = position288, tokenIndex288, depth288
+						}
+						{
+							position289, tokenIndex289, depth289 := position, tokenIndex, depth
+							if !_rules[ruleSeparatorKey]() {
+								goto l289
+							}
+							goto l284
+						l289:
+							position, tokenIndex, depth = position289, tokenIndex289, depth289
+						}
+						{
+							position290, tokenIndex290, depth290 := position, tokenIndex, depth
+							if !_rules[rules]() {
+								goto l290
+							}
+							if buffer[position] != rune('}') {
+								goto l290
+							}
+							position++
+							if buffer[position] != rune('}') {
+								goto l290
+							}
+							position++
+							goto l284
+						l290:
+							position, tokenIndex, depth = position290, tokenIndex290, depth290
+						}
+						if !matchDot() {
+							goto l284
+						}
+						goto l283
+					l284:
+						position, tokenIndex, depth = position284, tokenIndex284, depth284
+					}
+					depth--
+					add(rulePegText, position282)
+				}
+				if !_rules[ruleAction22]() {
+					goto l280
+				}
+				depth--
+				add(ruleRepeatText, position281)
+			}
+			return true
+		l280:
+			position, tokenIndex, depth = position280, tokenIndex280, depth280
+			return false
+		},
+		/* 35 RepeatSeparator <- <(SeparatorKey s String Action23)> */
+		func() bool {
+			position291, tokenIndex291, depth291 := position, tokenIndex, depth
+			{
+				position292 := position
+				depth++
+				if !_rules[ruleSeparatorKey]() {
+					goto l291
+				}
+				if !_rules[rules]() {
+					goto l291
+				}
+				if !_rules[ruleString]() {
+					goto l291
+				}
+				if !_rules[ruleAction23]() {
+					goto l291
+				}
+				depth--
+				add(ruleRepeatSeparator, position292)
+			}
+			return true
+		l291:
+			position, tokenIndex, depth = position291, tokenIndex291, depth291
+			return false
+		},
+		/* 36 SeparatorKey <- <(s ',' s (('s' / 'S') ('e' / 'E') ('p' / 'P')) s '=')> */
+		func() bool {
+			position293, tokenIndex293, depth293 := position, tokenIndex, depth
+			{
+				position294 := position
+				depth++
+				if !_rules[rules]() {
+					goto l293
+				}
+				if buffer[position] != rune(',') {
+					goto l293
+				}
+				position++
+				if !_rules[rules]() {
+					goto l293
+				}
+				{
+					position295, tokenIndex295, depth295 := position, tokenIndex, depth
+					if buffer[position] != rune('s') {
+						goto l296
+					}
+					position++
+					goto l295
+				l296:
+					position, tokenIndex, depth = position295, tokenIndex295, depth295
+					if buffer[position] != rune('S') {
+						goto l293
+					}
+					position++
+				}
+			l295:
+				{
+					position297, tokenIndex297, depth297 := position, tokenIndex, depth
+					if buffer[position] != rune('e') {
+						goto l298
+					}
+					position++
+					goto l297
+				l298:
+					position, tokenIndex, depth = position297, tokenIndex297, depth297
+					if buffer[position] != rune('E') {
+						goto l293
+					}
+					position++
+				}
+			l297:
+				{
+					position299, tokenIndex299, depth299 := position, tokenIndex, depth
+					if buffer[position] != rune('p') {
+						goto l300
+					}
+					position++
+					goto l299
+				l300:
+					position, tokenIndex, depth = position299, tokenIndex299, depth299
+					if buffer[position] != rune('P') {
+						goto l293
+					}
+					position++
+				}
+			l299:
+				if !_rules[rules]() {
+					goto l293
+				}
+				if buffer[position] != rune('=') {
+					goto l293
+				}
+				position++
+				depth--
+				add(ruleSeparatorKey, position294)
+			}
+			return true
+		l293:
+			position, tokenIndex, depth = position293, tokenIndex293, depth293
+			return false
+		},
+		/* 37 RawTemplate <- <(<(('r' / 'R') ('e' / 'E') ':' s RawPart*)> Action24)> */
+		func() bool {
+			position301, tokenIndex301, depth301 := position, tokenIndex, depth
+			{
+				position302 := position
+				depth++
+				{
+					position303 := position
+					depth++
+					{
+						position304, tokenIndex304, depth304 := position, tokenIndex, depth
+						if buffer[position] != rune('r') {
+							goto l305
+						}
+						position++
+						goto l304
+					l305:
+						position, tokenIndex, depth = position304, tokenIndex304, depth304
+						if buffer[position] != rune('R') {
+							goto l301
+						}
+						position++
+					}
+				l304:
+					{
+						position306, tokenIndex306, depth306 := position, tokenIndex, depth
+						if buffer[position] != rune('e') {
+							goto l307
+						}
+						position++
+						goto l306
+					l307:
+						position, tokenIndex, depth = position306, tokenIndex306, depth306
+						if buffer[position] != rune('E') {
+							goto l301
+						}
+						position++
+					}
+				l306:
+					if buffer[position] != rune(':') {
+						goto l301
+					}
+					position++
+					if !_rules[rules]() {
+						goto l301
+					}
+				l308:
+					{
+						position309, tokenIndex309, depth309 := position, tokenIndex, depth
+						if !_rules[ruleRawPart]() {
+							goto l309
+						}
+						goto l308
+					l309:
+						position, tokenIndex, depth = position309, tokenIndex309, depth309
+					}
+					depth--
+					add(rulePegText, position303)
+				}
+				if !_rules[ruleAction24]() {
+					goto l301
+				}
+				depth--
+				add(ruleRawTemplate, position302)
+			}
+			return true
+		l301:
+			position, tokenIndex, depth = position301, tokenIndex301, depth301
+			return false
+		},
+		/* 38 RawPart <- <(Placeholder / RawText)> */
+		func() bool {
+			position310, tokenIndex310, depth310 := position, tokenIndex, depth
+			{
+				position311 := position
+				depth++
+				{
+					position312, tokenIndex312, depth312 := position, tokenIndex, depth
+					if !_rules[rulePlaceholder]() {
+						goto l313
+					}
+					goto l312
+				l313:
+					position, tokenIndex, depth = position312, tokenIndex312, depth312
+					if !_rules[ruleRawText]() {
+						goto l310
+					}
+				}
+			l312:
+				depth--
+				add(ruleRawPart, position311)
+			}
+			return true
+		l310:
+			position, tokenIndex, depth = position310, tokenIndex310, depth310
+			return false
+		},
+		/* 39 RawText <- <(<(!('{' '{') !TrailingSpace .)+> Action25)> */
+		func() bool {
+			position314, tokenIndex314, depth314 := position, tokenIndex, depth
+			{
+				position315 := position
+				depth++
+				{
+					position316 := position
+					depth++
+					{
+						position319, tokenIndex319, depth319 := position, tokenIndex, depth
+						if buffer[position] != rune('{') {
+							goto l319
+						}
+						position++
+						if buffer[position] != rune('{') {
+							goto l319
+						}
+						position++
+						goto l314
+					l319:
+						position, tokenIndex, depth = position319, tokenIndex319, depth319
+					}
+					{
+						position320, tokenIndex320, depth320 := position, tokenIndex, depth
+						if !_rules[ruleTrailingSpace]() {
+							goto l320
+						}
+						goto l314
+					l320:
+						position, tokenIndex, depth = position320, tokenIndex320, depth320
+					}
+					if !matchDot() {
+						goto l314
+					}
+				l317:
+					{
+						position318, tokenIndex318, depth318 := position, tokenIndex, depth
+						{
+							position321, tokenIndex321, depth321 := position, tokenIndex, depth
+							if buffer[position] != rune('{') {
+								goto l321
+							}
+							position++
+							if buffer[position] != rune('{') {
+								goto l321
+							}
+							position++
+							goto l318
+						l321:
+							position, tokenIndex, depth = position321, tokenIndex321, depth321
+						}
+						{
+							position322, tokenIndex322, depth322 := position, tokenIndex, depth
+							if !_rules[ruleTrailingSpace]() {
+								goto l322
+							}
+							goto l318
+						l322:
+							position, tokenIndex, depth = position322, tokenIndex322, depth322
+						}
+						if !matchDot() {
+							goto l318
+						}
+						goto l317
+					l318:
+						position, tokenIndex, depth = position318, tokenIndex318, depth318
+					}
+					depth--
+					add(rulePegText, position316)
+				}
+				if !_rules[ruleAction25]() {
+					goto l314
+				}
+				depth--
+				add(ruleRawText, position315)
+			}
+			return true
+		l314:
+			position, tokenIndex, depth = position314, tokenIndex314, depth314
+			return false
+		},
+		/* 40 Wildcard <- <('.' '.' '.' Action26)> */
+		func() bool {
+			position323, tokenIndex323, depth323 := position, tokenIndex, depth
+			{
+				position324 := position
+				depth++
+				if buffer[position] != rune('.') {
+					goto l323
+				}
+				position++
+				if buffer[position] != rune('.') {
+					goto l323
+				}
+				position++
+				if buffer[position] != rune('.') {
+					goto l323
+				}
+				position++
+				if !_rules[ruleAction26]() {
+					goto l323
+				}
+				depth--
+				add(ruleWildcard, position324)
+			}
+			return true
+		l323:
+			position, tokenIndex, depth = position323, tokenIndex323, depth323
+			return false
+		},
+		/* 41 Placeholder <- <('{' '{' s !(('r' / 'R') ('e' / 'E') ('p' / 'P') ('e' / 'E') ('a' / 'A') ('t' / 'T') ':') PlaceholderName s (':' PlaceholderPattern)? ('}' '}') Action27)> */
+		func() bool {
+			position325, tokenIndex325, depth325 := position, tokenIndex, depth
+			{
+				position326 := position
+				depth++
+				if buffer[position] != rune('{') {
+					goto l325
+				}
+				position++
+				if buffer[position] != rune('{') {
+					goto l325
+				}
+				position++
+				if !_rules[rules]() {
+					goto l325
+				}
+				{
+					position327, tokenIndex327, depth327 := position, tokenIndex, depth
+					{
+						position328, tokenIndex328, depth328 := position, tokenIndex, depth
+						if buffer[position] != rune('r') {
+							goto l329
+						}
+						position++
+						goto l328
+					l329:
+						position, tokenIndex, depth = position328, tokenIndex328, depth328
+						if buffer[position] != rune('R') {
+							goto l327
+						}
+						position++
+					}
+				l328:
+					{
+						position330, tokenIndex330, depth330 := position, tokenIndex, depth
+						if buffer[position] != rune('e') {
+							goto l331
+						}
+						position++
+						goto l330
+					l331:
+						position, tokenIndex, depth = position330, tokenIndex330, depth330
+						if buffer[position] != rune('E') {
+							goto l327
+						}
+						position++
+					}
+				l330:
+					{
+						position332, tokenIndex332, depth332 := position, tokenIndex, depth
+						if buffer[position] != rune('p') {
+							goto l333
+						}
+						position++
+						goto l332
+					l333:
+						position, tokenIndex, depth = position332, tokenIndex332, depth332
+						if buffer[position] != rune('P') {
+							goto l327
+						}
+						position++
+					}
+				l332:
+					{
+						position334, tokenIndex334, depth334 := position, tokenIndex, depth
+						if buffer[position] != rune('e') {
+							goto l335
+						}
+						position++
+						goto l334
+					l335:
+						position, tokenIndex, depth = position334, tokenIndex334, depth334
+						if buffer[position] != rune('E') {
+							goto l327
+						}
+						position++
+					}
+				l334:
+					{
+						position336, tokenIndex336, depth336 := position, tokenIndex, depth
+						if buffer[position] != rune('a') {
+							goto l337
+						}
+						position++
+						goto l336
+					l337:
+						position, tokenIndex, depth = position336, tokenIndex336, depth336
+						if buffer[position] != rune('A') {
+							goto l327
+						}
+						position++
+					}
+				l336:
+					{
+						position338, tokenIndex338, depth338 := position, tokenIndex, depth
+						if buffer[position] != rune('t') {
+							goto l339
+						}
+						position++
+						goto l338
+					l339:
+						position, tokenIndex, depth = position338, tokenIndex338, depth338
+						if buffer[position] != rune('T') {
+							goto l327
+						}
+						position++
+					}
+				l338:
+					if buffer[position] != rune(':') {
+						goto l327
+					}
+					position++
+					goto l325
+				l327:
+					position, tokenIndex, depth = position327, tokenIndex327, depth327
+				}
+				if !_rules[rulePlaceholderName]() {
+					goto l325
+				}
+				if !_rules[rules]() {
+					goto l325
+				}
+				{
+					position340, tokenIndex340, depth340 := position, tokenIndex, depth
+					if buffer[position] != rune(':') {
+						goto l340
+					}
+					position++
+					if !_rules[rulePlaceholderPattern]() {
+						goto l340
+					}
+					goto l341
+				l340:
+					position, tokenIndex, depth = position340, tokenIndex340, depth340
+				}
+			l341:
+				if buffer[position] != rune('}') {
+					goto l325
+				}
+				position++
+				if buffer[position] != rune('}') {
+					goto l325
+				}
+				position++
+				if !_rules[ruleAction27]() {
+					goto l325
+				}
+				depth--
+				add(rulePlaceholder, position326)
+			}
+			return true
+		l325:
+			position, tokenIndex, depth = position325, tokenIndex325, depth325
+			return false
+		},
+		/* 42 PlaceholderName <- <(<([a-z] / [A-Z] / [0-9] / '-' / '_')+> Action28)> */
+		func() bool {
+			position342, tokenIndex342, depth342 := position, tokenIndex, depth
+			{
+				position343 := position
+				depth++
 				{
-					position132, tokenIndex132, depth132 := position, tokenIndex, depth
+					position344 := position
+					depth++
+					{
+						position347, tokenIndex347, depth347 := position, tokenIndex, depth
+						if c := buffer[position]; c < rune('a') || c > rune('z') {
+							goto l348
+						}
+						position++
+						goto l347
+					l348:
+						position, tokenIndex, depth = position347, tokenIndex347, depth347
+						if c := buffer[position]; c < rune('A') || c > rune('Z') {
+							goto l349
+						}
+						position++
+						goto l347
+					l349:
+						position, tokenIndex, depth = position347, tokenIndex347, depth347
+						if c := buffer[position]; c < rune('0') || c > rune('9') {
+							goto l350
+						}
+						position++
+						goto l347
+					l350:
+						position, tokenIndex, depth = position347, tokenIndex347, depth347
+						if buffer[position] != rune('-') {
+							goto l351
+						}
+						position++
+						goto l347
+					l351:
+						position, tokenIndex, depth = position347, tokenIndex347, depth347
+						if buffer[position] != rune('_') {
+							goto l342
+						}
+						position++
+					}
+				l347:
+				l345:
+					{
+						position346, tokenIndex346, depth346 := position, tokenIndex, depth
+						{
+							position352, tokenIndex352, depth352 := position, tokenIndex, depth
+							if c := buffer[position]; c < rune('a') || c > rune('z') {
+								goto l353
+							}
+							position++
+							goto l352
+						l353:
+							position, tokenIndex, depth = position352, tokenIndex352, depth352
+							if c := buffer[position]; c < rune('A') || c > rune('Z') {
+								goto l354
+							}
+							position++
+							goto l352
+						l354:
+							position, tokenIndex, depth = position352, tokenIndex352, depth352
+							if c := buffer[position]; c < rune('0') || c > rune('9') {
+								goto l355
+							}
+							position++
+							goto l352
+						l355:
+							position, tokenIndex, depth = position352, tokenIndex352, depth352
+							if buffer[position] != rune('-') {
+								goto l356
+							}
+							position++
+							goto l352
+						l356:
+							position, tokenIndex, depth = position352, tokenIndex352, depth352
+							if buffer[position] != rune('_') {
+								goto l346
+							}
+							position++
+						}
+					l352:
+						goto l345
+					l346:
+						position, tokenIndex, depth = position346, tokenIndex346, depth346
+					}
+					depth--
+					add(rulePegText, position344)
+				}
+				if !_rules[ruleAction28]() {
+					goto l342
+				}
+				depth--
+				add(rulePlaceholderName, position343)
+			}
+			return true
+		l342:
+			position, tokenIndex, depth = position342, tokenIndex342, depth342
+			return false
+		},
+		/* 43 PlaceholderPattern <- <(<PatternChar+> Action29)> */
+		func() bool {
+			position357, tokenIndex357, depth357 := position, tokenIndex, depth
+			{
+				position358 := position
+				depth++
+				{
+					position359 := position
+					depth++
+					if !_rules[rulePatternChar]() {
+						goto l357
+					}
+				l360:
+					{
+						position361, tokenIndex361, depth361 := position, tokenIndex, depth
+						if !_rules[rulePatternChar]() {
+							goto l361
+						}
+						goto l360
+					l361:
+						position, tokenIndex, depth = position361, tokenIndex361, depth361
+					}
+					depth--
+					add(rulePegText, position359)
+				}
+				if !_rules[ruleAction29]() {
+					goto l357
+				}
+				depth--
+				add(rulePlaceholderPattern, position358)
+			}
+			return true
+		l357:
+			position, tokenIndex, depth = position357, tokenIndex357, depth357
+			return false
+		},
+		/* 44 PatternChar <- <(PatternGroup / ('\\' !EOL .) / (!'{' !'}' !EOL .))> */
+		func() bool {
+			position362, tokenIndex362, depth362 := position, tokenIndex, depth
+			{
+				position363 := position
+				depth++
+				{
+					position364, tokenIndex364, depth364 := position, tokenIndex, depth
+					if !_rules[rulePatternGroup]() {
+						goto l365
+					}
+					goto l364
+				l365:
+					position, tokenIndex, depth = position364, tokenIndex364, depth364
+					if buffer[position] != rune('\\') {
+						goto l366
+					}
+					position++
+					{
+						position367, tokenIndex367, depth367 := position, tokenIndex, depth
+						if !_rules[ruleEOL]() {
+							goto l367
+						}
+						goto l366
+					l367:
+						position, tokenIndex, depth = position367, tokenIndex367, depth367
+					}
+					if !matchDot() {
+						goto l366
+					}
+					goto l364
+				l366:
+					position, tokenIndex, depth = position364, tokenIndex364, depth364
+					{
+						position368, tokenIndex368, depth368 := position, tokenIndex, depth
+						if buffer[position] != rune('{') {
+							goto l368
+						}
+						position++
+						goto l362
+					l368:
+						position, tokenIndex, depth = position368, tokenIndex368, depth368
+					}
+					{
+						position369, tokenIndex369, depth369 := position, tokenIndex, depth
+						if buffer[position] != rune('}') {
+							goto l369
+						}
+						position++
+						goto l362
+					l369:
+						position, tokenIndex, depth = position369, tokenIndex369, depth369
+					}
+					{
+						position370, tokenIndex370, depth370 := position, tokenIndex, depth
+						if !_rules[ruleEOL]() {
+							goto l370
+						}
+						goto l362
+					l370:
+						position, tokenIndex, depth = position370, tokenIndex370, depth370
+					}
+					if !matchDot() {
+						goto l362
+					}
+				}
+			l364:
+				depth--
+				add(rulePatternChar, position363)
+			}
+			return true
+		l362:
+			position, tokenIndex, depth = position362, tokenIndex362, depth362
+			return false
+		},
+		/* 45 PatternGroup <- <('{' PatternChar* '}')> */
+		func() bool {
+			position371, tokenIndex371, depth371 := position, tokenIndex, depth
+			{
+				position372 := position
+				depth++
+				if buffer[position] != rune('{') {
+					goto l371
+				}
+				position++
+			l373:
+				{
+					position374, tokenIndex374, depth374 := position, tokenIndex, depth
+					if !_rules[rulePatternChar]() {
+						goto l374
+					}
+					goto l373
+				l374:
+					position, tokenIndex, depth = position374, tokenIndex374, depth374
+				}
+				if buffer[position] != rune('}') {
+					goto l371
+				}
+				position++
+				depth--
+				add(rulePatternGroup, position372)
+			}
+			return true
+		l371:
+			position, tokenIndex, depth = position371, tokenIndex371, depth371
+			return false
+		},
+		/* 46 Samples <- <((Comment / Sample) EOL)*> */
+		func() bool {
+			{
+				position376 := position
+				depth++
+			l377:
+				{
+					position378, tokenIndex378, depth378 := position, tokenIndex, depth
 					{
-						position133, tokenIndex133, depth133 := position, tokenIndex, depth
+						position379, tokenIndex379, depth379 := position, tokenIndex, depth
 						if !_rules[ruleComment]() {
-							goto l134
+							goto l380
 						}
-						goto l133
-					l134:
-						position, tokenIndex, depth = position133, tokenIndex133, depth133
+						goto l379
+					l380:
+						position, tokenIndex, depth = position379, tokenIndex379, depth379
 						if !_rules[ruleSample]() {
-							goto l132
+							goto l378
 						}
 					}
-				l133:
+				l379:
 					if !_rules[ruleEOL]() {
-						goto l132
+						goto l378
 					}
-					goto l131
-				l132:
-					position, tokenIndex, depth = position132, tokenIndex132, depth132
+					goto l377
+				l378:
+					position, tokenIndex, depth = position378, tokenIndex378, depth378
 				}
 				depth--
-				add(ruleSamples, position130)
+				add(ruleSamples, position376)
 			}
 			return true
 		},
-		/* 18 Sample <- <(s <(!EOL .)*> Action9)> */
+		/* 47 Sample <- <(s <(!EOL .)*> Action30)> */
 		func() bool {
-			position135, tokenIndex135, depth135 := position, tokenIndex, depth
+			position381, tokenIndex381, depth381 := position, tokenIndex, depth
 			{
-				position136 := position
+				position382 := position
 				depth++
 				if !_rules[rules]() {
-					goto l135
+					goto l381
 				}
 				{
-					position137 := position
+					position383 := position
 					depth++
-				l138:
+				l384:
 					{
-						position139, tokenIndex139, depth139 := position, tokenIndex, depth
+						position385, tokenIndex385, depth385 := position, tokenIndex, depth
 						{
-							position140, tokenIndex140, depth140 := position, tokenIndex, depth
+							position386, tokenIndex386, depth386 := position, tokenIndex, depth
 							if !_rules[ruleEOL]() {
-								goto l140
+								goto l386
 							}
-							goto l139
-						l140:
-							position, tokenIndex, depth = position140, tokenIndex140, depth140
+							goto l385
+						l386:
+							position, tokenIndex, depth = position386, tokenIndex386, depth386
 						}
 						if !matchDot() {
-							goto l139
+							goto l385
 						}
-						goto l138
-					l139:
-						position, tokenIndex, depth = position139, tokenIndex139, depth139
+						goto l384
+					l385:
+						position, tokenIndex, depth = position385, tokenIndex385, depth385
 					}
 					depth--
-					add(rulePegText, position137)
+					add(rulePegText, position383)
 				}
-				if !_rules[ruleAction9]() {
-					goto l135
+				if !_rules[ruleAction30]() {
+					goto l381
 				}
 				depth--
-				add(ruleSample, position136)
+				add(ruleSample, position382)
 			}
 			return true
-		l135:
-			position, tokenIndex, depth = position135, tokenIndex135, depth135
+		l381:
+			position, tokenIndex, depth = position381, tokenIndex381, depth381
 			return false
 		},
-		/* 19 Comment <- <(s '#' (!EOL .)*)> */
+		/* 48 Comment <- <(s '#' (!EOL .)*)> */
 		func() bool {
-			position141, tokenIndex141, depth141 := position, tokenIndex, depth
+			position387, tokenIndex387, depth387 := position, tokenIndex, depth
 			{
-				position142 := position
+				position388 := position
 				depth++
 				if !_rules[rules]() {
-					goto l141
+					goto l387
 				}
 				if buffer[position] != rune('#') {
-					goto l141
+					goto l387
 				}
 				position++
-			l143:
+			l389:
 				{
-					position144, tokenIndex144, depth144 := position, tokenIndex, depth
+					position390, tokenIndex390, depth390 := position, tokenIndex, depth
 					{
-						position145, tokenIndex145, depth145 := position, tokenIndex, depth
+						position391, tokenIndex391, depth391 := position, tokenIndex, depth
 						if !_rules[ruleEOL]() {
-							goto l145
+							goto l391
 						}
-						goto l144
-					l145:
-						position, tokenIndex, depth = position145, tokenIndex145, depth145
+						goto l390
+					l391:
+						position, tokenIndex, depth = position391, tokenIndex391, depth391
 					}
 					if !matchDot() {
-						goto l144
+						goto l390
 					}
-					goto l143
-				l144:
-					position, tokenIndex, depth = position144, tokenIndex144, depth144
+					goto l389
+				l390:
+					position, tokenIndex, depth = position390, tokenIndex390, depth390
 				}
 				depth--
-				add(ruleComment, position142)
+				add(ruleComment, position388)
 			}
 			return true
-		l141:
-			position, tokenIndex, depth = position141, tokenIndex141, depth141
+		l387:
+			position, tokenIndex, depth = position387, tokenIndex387, depth387
 			return false
 		},
-		/* 20 EOF <- <!.> */
+		/* 49 EOF <- <!.> */
 		func() bool {
-			position146, tokenIndex146, depth146 := position, tokenIndex, depth
+			position392, tokenIndex392, depth392 := position, tokenIndex, depth
 			{
-				position147 := position
+				position393 := position
 				depth++
 				{
-					position148, tokenIndex148, depth148 := position, tokenIndex, depth
+					position394, tokenIndex394, depth394 := position, tokenIndex, depth
 					if !matchDot() {
-						goto l148
+						goto l394
 					}
-					goto l146
-				l148:
-					position, tokenIndex, depth = position148, tokenIndex148, depth148
+					goto l392
+				l394:
+					position, tokenIndex, depth = position394, tokenIndex394, depth394
 				}
 				depth--
-				add(ruleEOF, position147)
+				add(ruleEOF, position393)
 			}
 			return true
-		l146:
-			position, tokenIndex, depth = position146, tokenIndex146, depth146
+		l392:
+			position, tokenIndex, depth = position392, tokenIndex392, depth392
 			return false
 		},
-		/* 21 EOL <- <('\r' / '\n')> */
+		/* 50 EOL <- <('\r' / '\n')> */
 		func() bool {
-			position149, tokenIndex149, depth149 := position, tokenIndex, depth
+			position395, tokenIndex395, depth395 := position, tokenIndex, depth
 			{
-				position150 := position
+				position396 := position
 				depth++
 				{
-					position151, tokenIndex151, depth151 := position, tokenIndex, depth
+					position397, tokenIndex397, depth397 := position, tokenIndex, depth
 					if buffer[position] != rune('\r') {
-						goto l152
+						goto l398
 					}
 					position++
-					goto l151
-				l152:
-					position, tokenIndex, depth = position151, tokenIndex151, depth151
+					goto l397
+				l398:
+					position, tokenIndex, depth = position397, tokenIndex397, depth397
 					if buffer[position] != rune('\n') {
-						goto l149
+						goto l395
 					}
 					position++
 				}
-			l151:
+			l397:
 				depth--
-				add(ruleEOL, position150)
+				add(ruleEOL, position396)
 			}
 			return true
-		l149:
-			position, tokenIndex, depth = position149, tokenIndex149, depth149
+		l395:
+			position, tokenIndex, depth = position395, tokenIndex395, depth395
 			return false
 		},
-		/* 22 s <- <(' ' / '\t')*> */
+		/* 51 s <- <(' ' / '\t')*> */
 		func() bool {
 			{
-				position154 := position
+				position400 := position
 				depth++
-			l155:
+			l401:
 				{
-					position156, tokenIndex156, depth156 := position, tokenIndex, depth
+					position402, tokenIndex402, depth402 := position, tokenIndex, depth
 					{
-						position157, tokenIndex157, depth157 := position, tokenIndex, depth
+						position403, tokenIndex403, depth403 := position, tokenIndex, depth
 						if buffer[position] != rune(' ') {
-							goto l158
+							goto l404
 						}
 						position++
-						goto l157
-					l158:
-						position, tokenIndex, depth = position157, tokenIndex157, depth157
+						goto l403
+					l404:
+						position, tokenIndex, depth = position403, tokenIndex403, depth403
 						if buffer[position] != rune('\t') {
-							goto l156
+							goto l402
 						}
 						position++
 					}
-				l157:
-					goto l155
-				l156:
-					position, tokenIndex, depth = position156, tokenIndex156, depth156
+				l403:
+					goto l401
+				l402:
+					position, tokenIndex, depth = position402, tokenIndex402, depth402
 				}
 				depth--
-				add(rules, position154)
+				add(rules, position400)
 			}
 			return true
 		},
 		nil,
-		/* 25 Action0 <- <{ p.name = buffer[begin:end] }> */
+		/* 54 Action0 <- <{ p.name = text }> */
 		func() bool {
 			{
 				add(ruleAction0, position)
 			}
 			return true
 		},
-		/* 26 Action1 <- <{ p.set(p.name, p.value) }> */
+		/* 55 Action1 <- <{ p.set(p.name, p.value) }> */
 		func() bool {
 			{
 				add(ruleAction1, position)
 			}
 			return true
 		},
-		/* 27 Action2 <- <{ p.inField = false }> */
+		/* 56 Action2 <- <{ p.addInclude(p.value) }> */
 		func() bool {
 			{
 				add(ruleAction2, position)
 			}
 			return true
 		},
-		/* 28 Action3 <- <{ p.inField = true; p.newField(buffer[begin:end]) }> */
+		/* 57 Action3 <- <{ p.inField = false }> */
 		func() bool {
 			{
 				add(ruleAction3, position)
 			}
 			return true
 		},
-		/* 29 Action4 <- <{ p.value = buffer[begin:end] }> */
+		/* 58 Action4 <- <{ p.inField = true; p.newField(text) }> */
 		func() bool {
 			{
 				add(ruleAction4, position)
 			}
 			return true
 		},
-		/* 30 Action5 <- <{ p.value = buffer[begin:end] }> */
+		/* 59 Action5 <- <{ p.value = text }> */
 		func() bool {
 			{
 				add(ruleAction5, position)
 			}
 			return true
 		},
-		/* 31 Action6 <- <{ p.value = buffer[begin:end] }> */
+		/* 60 Action6 <- <{ p.value = text }> */
 		func() bool {
 			{
 				add(ruleAction6, position)
 			}
 			return true
 		},
-		/* 32 Action7 <- <{ p.value = buffer[begin:end] }> */
+		/* 61 Action7 <- <{ p.value = text }> */
 		func() bool {
 			{
 				add(ruleAction7, position)
 			}
 			return true
 		},
-		/* 33 Action8 <- <{ p.addTemplate(buffer[begin:end]) }> */
+		/* 62 Action8 <- <{ p.value = text }> */
 		func() bool {
 			{
 				add(ruleAction8, position)
 			}
 			return true
 		},
-		/* 34 Action9 <- <{ p.addSample(buffer[begin:end]) }> */
+		/* 63 Action9 <- <{ p.annotate(p.name, p.value) }> */
 		func() bool {
 			{
 				add(ruleAction9, position)
 			}
 			return true
 		},
+		/* 64 Action10 <- <{ p.addTemplate(text, p.lineNumber(begin), false) }> */
+		func() bool {
+			{
+				add(ruleAction10, position)
+			}
+			return true
+		},
+		/* 65 Action11 <- <{ p.addText(text) }> */
+		func() bool {
+			{
+				add(ruleAction11, position)
+			}
+			return true
+		},
+		/* 66 Action12 <- <{ p.addText(text) }> */
+		func() bool {
+			{
+				add(ruleAction12, position)
+			}
+			return true
+		},
+		/* 67 Action13 <- <{ p.beginGroup() }> */
+		func() bool {
+			{
+				add(ruleAction13, position)
+			}
+			return true
+		},
+		/* 68 Action14 <- <{ p.endOptional() }> */
+		func() bool {
+			{
+				add(ruleAction14, position)
+			}
+			return true
+		},
+		/* 69 Action15 <- <{ p.addText(text) }> */
+		func() bool {
+			{
+				add(ruleAction15, position)
+			}
+			return true
+		},
+		/* 70 Action16 <- <{ p.beginGroup() }> */
+		func() bool {
+			{
+				add(ruleAction16, position)
+			}
+			return true
+		},
+		/* 71 Action17 <- <{ p.nextAlternative() }> */
+		func() bool {
+			{
+				add(ruleAction17, position)
+			}
+			return true
+		},
+		/* 72 Action18 <- <{ p.endAlternation() }> */
+		func() bool {
+			{
+				add(ruleAction18, position)
+			}
+			return true
+		},
+		/* 73 Action19 <- <{ p.addText(text) }> */
+		func() bool {
+			{
+				add(ruleAction19, position)
+			}
+			return true
+		},
+		/* 74 Action20 <- <{ p.beginGroup() }> */
+		func() bool {
+			{
+				add(ruleAction20, position)
+			}
+			return true
+		},
+		/* 75 Action21 <- <{ p.endRepeat() }> */
+		func() bool {
+			{
+				add(ruleAction21, position)
+			}
+			return true
+		},
+		/* 76 Action22 <- <{ p.addText(text) }> */
+		func() bool {
+			{
+				add(ruleAction22, position)
+			}
+			return true
+		},
+		/* 77 Action23 <- <{ p.separator = p.value }> */
+		func() bool {
+			{
+				add(ruleAction23, position)
+			}
+			return true
+		},
+		/* 78 Action24 <- <{ p.addTemplate(text, p.lineNumber(begin), true) }> */
+		func() bool {
+			{
+				add(ruleAction24, position)
+			}
+			return true
+		},
+		/* 79 Action25 <- <{ p.addText(text) }> */
+		func() bool {
+			{
+				add(ruleAction25, position)
+			}
+			return true
+		},
+		/* 80 Action26 <- <{ p.addWildcard() }> */
+		func() bool {
+			{
+				add(ruleAction26, position)
+			}
+			return true
+		},
+		/* 81 Action27 <- <{ p.addPlaceholder() }> */
+		func() bool {
+			{
+				add(ruleAction27, position)
+			}
+			return true
+		},
+		/* 82 Action28 <- <{ p.name = text; p.pattern = "" }> */
+		func() bool {
+			{
+				add(ruleAction28, position)
+			}
+			return true
+		},
+		/* 83 Action29 <- <{ p.pattern = text }> */
+		func() bool {
+			{
+				add(ruleAction29, position)
+			}
+			return true
+		},
+		/* 84 Action30 <- <{ p.addSample(text) }> */
+		func() bool {
+			{
+				add(ruleAction30, position)
+			}
+			return true
+		},
 	}
 	p.rules = _rules
 }
@@ -1,22 +1,91 @@
 package main
 
-import "github.com/fd0/erpel/internal/erpel"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/fd0/erpel/internal/erpel"
+	"github.com/spf13/pflag"
+)
 
 // Rules contain the ignore rules for log messages.
 var Rules []erpel.Rules
 
+var (
+	// rulesDirs are searched for rules files in order
+	rulesDirs []string
+
+	// rulesFilter selects the rules files within the directories
+	rulesFilter erpel.FileFilter
+)
+
+// addRulesFlags adds the flags for the rules directories to flags.
+func addRulesFlags(flags *pflag.FlagSet) {
+	flags.StringSliceVarP(&rulesDirs, "rules", "r", []string{"/etc/erpel/rules.d"}, "load rules from these `directories`, later ones override files with the same name")
+	bindConfigValue("rules_dir", flags.Lookup("rules"))
+
+	flags.BoolVar(&rulesFilter.Recursive, "rules-recursive", false, "load rules from subdirectories")
+	bindConfigValue("rules_recursive", flags.Lookup("rules-recursive"))
+
+	flags.StringSliceVar(&rulesFilter.Include, "rules-include", nil, "only load rules files matching these `patterns`")
+	bindConfigValue("rules_include", flags.Lookup("rules-include"))
+
+	flags.StringSliceVar(&rulesFilter.Exclude, "rules-exclude", erpel.DefaultExclude, "skip rules files matching these `patterns`")
+	bindConfigValue("rules_exclude", flags.Lookup("rules-exclude"))
+}
+
+// tagFilter selects the templates which are loaded by their tags.
+var tagFilter erpel.TagFilter
+
 // LoadRules loads the rules from the directory and parses the files.
 func LoadRules() error {
-	V("load rules from %v\n", rulesDir)
+	V("load rules from %v\n", strings.Join(rulesDirs, ", "))
 
-	rules, err := erpel.ParseAllRulesFiles(cfg.Fields, rulesDir)
+	files, err := erpel.FindRulesFiles(rulesDirs, rulesFilter)
 	if err != nil {
 		return err
 	}
 
-	Rules = rules
+	Rules = nil
+	for _, file := range files {
+		r, notes, disabled, err := cfg.LoadRulesFile(file)
+		if err != nil {
+			return fmt.Errorf("%v: %v", file.Filename, err)
+		}
+
+		for _, note := range notes {
+			V("override: %v\n", note)
+		}
+
+		if disabled {
+			continue
+		}
+
+		removed, err := r.Select(tagFilter)
+		if err != nil {
+			return fmt.Errorf("%v: %v", r.Filename, err)
+		}
+
+		if removed > 0 {
+			V("%v: %d templates not selected by tags\n", r.Filename, removed)
+		}
+
+		if len(r.Templates) == 0 {
+			continue
+		}
+
+		for _, w := range r.Lint() {
+			V("warning: %v\n", w)
+		}
+
+		Rules = append(Rules, r)
+	}
+
+	for _, name := range cfg.UnusedOverrides(files) {
+		V("warning: override for %v does not match any rules file\n", name)
+	}
 
-	V("loaded rules from %d files\n", len(rules))
+	V("loaded rules from %d files\n", len(Rules))
 
 	return nil
 }